@@ -17,17 +17,44 @@ import (
 	"strings"
 	"time"
 
+	"srv.exe.dev/db"
+	"srv.exe.dev/db/dbgen"
 	"srv.exe.dev/internal/blog"
+	"srv.exe.dev/internal/comments"
+	"srv.exe.dev/internal/feed"
 	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/imagecache"
 	"srv.exe.dev/internal/pagedata"
+	"srv.exe.dev/internal/resume"
+	"srv.exe.dev/internal/showcase"
+	"srv.exe.dev/internal/snapshot"
+	"srv.exe.dev/internal/sources"
+	"srv.exe.dev/internal/webmention"
 )
 
 func main() {
 	outDir := flag.String("out", "dist", "output directory")
 	githubUser := flag.String("github", "HexSleeves", "GitHub username for projects")
 	basePath := flag.String("base", "", "base path for URLs (e.g., /portfolio for GitHub Pages)")
+	pinned := flag.Int("pinned", 0, "number of pinned repos to show (overrides showcase.yaml)")
+	topics := flag.Int("topics", 0, "number of topics per repo (overrides showcase.yaml)")
+	extra := flag.String("extra", "", "comma-separated owner/repo entries to add (overrides showcase.yaml)")
+	exclude := flag.String("exclude", "", "comma-separated repo glob patterns to hide (overrides showcase.yaml)")
+	pageSize := flag.Int("page-size", 0, "items per GitHub API page (overrides showcase.yaml)")
+	maxPages := flag.Int("max-pages", 0, "maximum GitHub API pages to follow (overrides showcase.yaml)")
+	snapshotPath := flag.String("projects-snapshot", "", "JSON file to save fetched project data to, or to build from with -offline")
+	offline := flag.Bool("offline", false, "build from -projects-snapshot without contacting GitHub or other sources")
+	imageCacheDir := flag.String("image-cache", "", "directory caching project preview images between builds (default: the user cache directory)")
+	commentsDB := flag.String("comments-db", "", "server SQLite database to bake approved blog comments and webmentions from")
+	webmentionEndpoint := flag.String("webmention-endpoint", "", "webmention endpoint URL to advertise on blog posts, e.g. the server's /webmention")
+	requireProjects := flag.Bool("require-projects", false, "fail the build instead of publishing a showcase with no fetched projects")
 	flag.Parse()
 
+	if *offline && *snapshotPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -offline requires -projects-snapshot")
+		os.Exit(1)
+	}
+
 	// Normalize base path
 	base := strings.TrimSuffix(*basePath, "/")
 	siteURL := "https://hexsleeves.github.io" + base
@@ -37,6 +64,7 @@ func main() {
 	baseDir := filepath.Dir(filepath.Dir(thisFile))
 	templatesDir := filepath.Join(baseDir, "..", "srv", "templates")
 	postsDir := filepath.Join(baseDir, "..", "srv", "posts")
+	dataDir := filepath.Join(baseDir, "..", "srv", "data")
 	staticDir := filepath.Join(baseDir, "..", "srv", "static")
 
 	// Create output directory first
@@ -45,8 +73,74 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Fetch GitHub projects (with retry)
-	projects := fetchGitHubProjects(*githubUser)
+	showcaseCfg, err := showcase.Load(filepath.Join(dataDir, "showcase.yaml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading showcase config: %v\n", err)
+		os.Exit(1)
+	}
+	overrides, err := showcase.LoadOverrides(filepath.Join(dataDir, "projects.yaml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading project overrides: %v\n", err)
+		os.Exit(1)
+	}
+	// Site-relative images and links in projects.yaml live under the base path.
+	overrides = overrides.WithBasePath(base)
+	fetchOpts := showcaseCfg.FetchOptions()
+	if *pinned > 0 {
+		fetchOpts.PinnedCount = *pinned
+	}
+	if *topics > 0 {
+		fetchOpts.TopicCount = *topics
+	}
+	if *extra != "" {
+		fetchOpts.ExtraRepos = splitList(*extra)
+	}
+	if *exclude != "" {
+		fetchOpts.Exclude = splitList(*exclude)
+	}
+	if *pageSize > 0 {
+		fetchOpts.PageSize = *pageSize
+	}
+	if *maxPages > 0 {
+		fetchOpts.MaxPages = *maxPages
+	}
+	if err := githubapi.ValidateFetchOptions(fetchOpts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in showcase options: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Fetch GitHub projects (with retry), or read them from a snapshot
+	var snap *snapshot.Snapshot
+	if *offline {
+		snap, err = snapshot.Load(*snapshotPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading projects snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Loaded %d projects from %s (fetched %s)\n", len(snap.Projects), *snapshotPath, snap.FetchedAt.Format(time.RFC3339))
+	} else {
+		snap = fetchSnapshot(newGitHubClient(), *githubUser, fetchOpts, showcaseCfg, overrides)
+	}
+	if len(snap.Projects) == 0 {
+		if *requireProjects {
+			fmt.Fprintln(os.Stderr, "Error: no projects were fetched and -require-projects is set")
+			os.Exit(1)
+		}
+	} else if *snapshotPath != "" && !*offline {
+		if err := snap.Save(*snapshotPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing projects snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote projects snapshot to %s\n", *snapshotPath)
+	}
+	projects := overrides.Apply(snap.Projects)
+	imagesPrefix := base + "/static/projects/"
+	images := openImageCache(*imageCacheDir, *offline)
+	if images != nil {
+		if err := images.LocalizeProjects(context.Background(), projects, imagesPrefix); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not cache project images: %v\n", err)
+		}
+	}
 	tmpl, err := loadTemplates(templatesDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading templates: %v\n", err)
@@ -68,12 +162,6 @@ func main() {
 			"Senior Software Engineer with 8 years of full-stack experience building scalable web applications and high-throughput backend services. Expert in Node.js, TypeScript, Go, and AWS.",
 			"",
 		},
-		{
-			"resume.html", "resume/index.html", "resume",
-			"Resume — Jacob LeCoq",
-			"Resume of Jacob LeCoq, Senior Software Engineer with 8 years of full-stack experience.",
-			"/resume",
-		},
 		{
 			"showcase.html", "projects/index.html", "showcase",
 			"Projects — Jacob LeCoq",
@@ -90,9 +178,25 @@ func main() {
 		sitemapURL{Loc: siteURL + "/blog", ChangeFreq: "weekly", Priority: "0.7"},
 	)
 
+	filterLink := func(f showcase.Filter) string {
+		return base + "/projects/" + f.Path()
+	}
+	showcaseData := func(data *pagedata.PageData, f showcase.Filter) {
+		data.Projects = f.Apply(projects)
+		if len(projects) > 0 {
+			data.Filters = pagedata.NewShowcaseFilters(projects, f, filterLink)
+		}
+		data.Contributions = pagedata.NewContributions(snap.Contributions)
+		data.Activity = pagedata.RecentActivity(snap.Activity)
+		data.Upstream = snap.Upstream
+	}
+
 	for _, page := range pages {
 		data := pagedata.NewPageData(page.page, base)
 		data.Projects = projects
+		if page.page == "showcase" {
+			showcaseData(&data, showcase.Filter{})
+		}
 		data.OGTitle = page.ogTitle
 		data.MetaDescription = page.description
 		data.OGPath = page.ogPath
@@ -103,12 +207,146 @@ func main() {
 		fmt.Printf("Generated %s\n", page.output)
 	}
 
+	// Pre-render each filter chip's target, since GitHub Pages ignores
+	// query strings. They are left out of the sitemap and marked noindex.
+	views := staticFilters(projects)
+	for _, f := range views {
+		data := pagedata.NewPageData("showcase", base)
+		showcaseData(&data, f)
+		data.OGTitle = "Projects — Jacob LeCoq"
+		data.MetaDescription = "Open-source projects and repositories by Jacob LeCoq, including tailscale-mcp, runeforge, and more."
+		data.OGPath = "/projects/" + f.Path()
+		data.NoIndex = true
+		outPath := filepath.Join("projects", filepath.FromSlash(f.Path()), "index.html")
+		if err := renderTemplate(tmpl, *outDir, "showcase.html", outPath, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering projects view %s: %v\n", f.Path(), err)
+			os.Exit(1)
+		}
+	}
+	if len(views) > 0 {
+		fmt.Printf("Generated %d filtered project views\n", len(views))
+	}
+
+	for _, project := range projects {
+		detail := overrides.ApplyDetail(snap.Detail(project))
+		if images != nil {
+			localized := *detail
+			if err := images.LocalizeProject(context.Background(), &localized.Project, imagesPrefix); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Could not cache project image: %v\n", err)
+			}
+			detail = &localized
+		}
+		projectPD := pagedata.NewPageData("showcase", base)
+		projectPD.OGTitle = fmt.Sprintf("%s — Jacob LeCoq", project.Name)
+		projectPD.MetaDescription = project.Description
+		projectPD.OGPath = "/projects/" + project.Key()
+		outPath := filepath.Join("projects", filepath.FromSlash(project.Key()), "index.html")
+		if err := renderTemplate(tmpl, *outDir, "project.html", outPath, pagedata.NewProjectPageData(projectPD, detail)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering project %s: %v\n", project.Name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Generated %s\n", outPath)
+
+		sitemapURLs = append(sitemapURLs, sitemapURL{
+			Loc:        siteURL + "/projects/" + project.Key(),
+			ChangeFreq: "weekly",
+			Priority:   "0.6",
+		})
+	}
+
+	if err := writeActivityFeed(*outDir, siteURL, snap.Activity); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing activity feed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated projects/activity.xml")
+
+	res, err := resume.Load(filepath.Join(dataDir, "resume.yaml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading resume: %v\n", err)
+		os.Exit(1)
+	}
+
+	resumePD := pagedata.NewPageData("resume", base)
+	resumePD.OGTitle = "Resume — Jacob LeCoq"
+	resumePD.MetaDescription = "Resume of Jacob LeCoq, Senior Software Engineer with 8 years of full-stack experience."
+	resumePD.OGPath = "/resume"
+	var languages []githubapi.LanguageShare
+	if showcaseCfg.ResumeLanguages {
+		languages = snap.Languages
+	}
+	resumeData := pagedata.ResumePageData{
+		PageData:   resumePD,
+		Resume:     res,
+		ExportBase: "/resume",
+		Languages:  languages,
+	}
+	if err := renderTemplate(tmpl, *outDir, "resume.html", "resume/index.html", resumeData); err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering resume: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated resume/index.html")
+
+	if err := writeResumeExports(*outDir, res); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing resume exports: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated resume.json, resume.pdf, resume.txt, resume.md")
+
+	if err := writeContactVCard(*outDir, staticDir, res); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing contact.vcf: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated contact.vcf")
+
+	variants, err := resume.LoadVariants(filepath.Join(dataDir, "resume-variants.yaml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading resume variants: %v\n", err)
+		os.Exit(1)
+	}
+	for _, v := range variants {
+		variantPD := pagedata.NewPageData("resume", base)
+		variantPD.OGTitle = resumePD.OGTitle
+		variantPD.MetaDescription = resumePD.MetaDescription
+		variantPD.OGPath = "/resume/" + v.Name
+		// Variants are shared by link only: noindex and no sitemap entry.
+		variantPD.NoIndex = true
+		variantRes := v.Apply(res)
+		variantData := pagedata.ResumePageData{
+			PageData:   variantPD,
+			Resume:     variantRes,
+			Variant:    &v,
+			ExportBase: "/resume/" + v.Name + "/resume",
+			Languages:  languages,
+		}
+		variantDir := filepath.Join("resume", v.Name)
+		outPath := filepath.Join(variantDir, "index.html")
+		if err := renderTemplate(tmpl, *outDir, "resume.html", outPath, variantData); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering resume variant %s: %v\n", v.Name, err)
+			os.Exit(1)
+		}
+		if err := writeResumeExports(filepath.Join(*outDir, variantDir), variantRes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing resume variant %s exports: %v\n", v.Name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Generated %s with exports\n", outPath)
+	}
+
 	posts, err := blog.LoadPosts(postsDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading blog posts: %v\n", err)
 		os.Exit(1)
 	}
 
+	var responses *postResponses
+	if *commentsDB != "" {
+		responses, err = loadResponses(*commentsDB)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading comments: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Loaded comments on %d posts and webmentions of %d from %s\n", len(responses.comments), len(responses.mentions), *commentsDB)
+	}
+
 	blogPD := pagedata.NewPageData("blog", base)
 	blogPD.OGTitle = "Blog — Jacob LeCoq"
 	blogPD.MetaDescription = "Writing on software engineering, systems programming, Go, Rust, and developer tooling."
@@ -136,6 +374,12 @@ func main() {
 			PageData: postPD,
 			Post:     &post,
 		}
+		postData.WebmentionURL = *webmentionEndpoint
+		if responses != nil {
+			// Static pages can't take new comments, so there is no form.
+			postData.Comments = pagedata.NewCommentSection(responses.comments[post.Slug], nil)
+			postData.Mentions = pagedata.NewMentionSection(responses.mentions[post.Slug])
+		}
 		outPath := filepath.Join("blog", post.Slug, "index.html")
 		if err := renderTemplate(tmpl, *outDir, "blog_post.html", outPath, postData); err != nil {
 			fmt.Fprintf(os.Stderr, "Error rendering blog post %s: %v\n", post.Slug, err)
@@ -171,6 +415,16 @@ func main() {
 		os.Exit(1)
 	}
 	fmt.Printf("Copied static files to %s\n", outStaticDir)
+	if images != nil {
+		n, err := copyProjectImages(images, projects, imagesPrefix, filepath.Join(outStaticDir, "projects"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error copying project images: %v\n", err)
+			os.Exit(1)
+		}
+		if n > 0 {
+			fmt.Printf("Copied %d project images\n", n)
+		}
+	}
 
 	if base != "" {
 		fmt.Printf("\nBuilt with base path: %s\n", base)
@@ -178,28 +432,231 @@ func main() {
 	fmt.Println("Build complete!")
 }
 
-// fetchGitHubProjects fetches GitHub repos with exponential backoff retry.
-func fetchGitHubProjects(username string) []githubapi.Project {
+// newGitHubClient returns the client shared by every GitHub call in the
+// build. Retries back off more patiently than the server's, since a build
+// can afford to wait.
+func newGitHubClient() *githubapi.Client {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	tokens, err := githubapi.TokenSourceFromEnv(httpClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring GitHub auth: %v\n", err)
+		os.Exit(1)
+	}
+	client := githubapi.NewClientWithTokenSource(httpClient, tokens)
+	client.MaxRetries = 2
+	client.BaseBackoff = 2 * time.Second
+	return client
+}
+
+// staticFilters lists the filtered and sorted projects views the showcase's
+// chips link to: each sort order alone and with each language or topic.
+// The unfiltered view is the projects page itself.
+func staticFilters(projects []githubapi.Project) []showcase.Filter {
+	if len(projects) == 0 {
+		return nil
+	}
+	languages, topics := showcase.Facets(projects)
+	var out []showcase.Filter
+	for _, sort := range showcase.Sorts {
+		if sort != "" {
+			out = append(out, showcase.Filter{Sort: sort})
+		}
+		for _, l := range languages {
+			out = append(out, showcase.Filter{Language: l.Value, Sort: sort})
+		}
+		for _, t := range topics {
+			out = append(out, showcase.Filter{Topic: t.Value, Sort: sort})
+		}
+	}
+	return out
+}
+
+// openImageCache opens the project image cache in dir, or the user cache
+// directory by default. Offline builds only use images already cached.
+// Cards are left without preview images when it cannot be opened.
+func openImageCache(dir string, offline bool) *imagecache.Cache {
+	if dir == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: No image cache directory, leaving out project images: %v\n", err)
+			return nil
+		}
+		dir = filepath.Join(userCache, "portfolio", "project-images")
+	}
 	client := &http.Client{Timeout: 10 * time.Second}
-	maxRetries := 3
-	backoff := 2 * time.Second
+	if offline {
+		client = nil
+	}
+	images, err := imagecache.New(dir, client)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not open image cache, leaving out project images: %v\n", err)
+		return nil
+	}
+	return images
+}
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		projects, err := githubapi.FetchProjects(context.Background(), client, username, os.Getenv("GITHUB_TOKEN"))
-		if err == nil {
-			fmt.Printf("Fetched %d projects from GitHub\n", len(projects))
-			return projects
+// fetchSnapshot fetches everything the build reads from GitHub and the
+// other sources. Failures are reported as warnings and leave the
+// corresponding part empty.
+func fetchSnapshot(client *githubapi.Client, username string, opts githubapi.FetchOptions, cfg showcase.Config, overrides showcase.Overrides) *snapshot.Snapshot {
+	snap := snapshot.New(username)
+	snap.Projects = fetchProjects(client, username, opts, cfg.Sources)
+	projects := overrides.Apply(snap.Projects)
+	for _, project := range projects {
+		if project.OnGitHub() {
+			snap.Details[project.Key()] = fetchProjectDetail(client, project, username)
 		}
-		if attempt < maxRetries {
-			fmt.Fprintf(os.Stderr, "Warning: GitHub fetch attempt %d/%d failed: %v — retrying in %s\n",
-				attempt, maxRetries, err, backoff)
-			time.Sleep(backoff)
-			backoff *= 2
-		} else {
-			fmt.Fprintf(os.Stderr, "Warning: Could not fetch GitHub repos after %d attempts: %v\n", maxRetries, err)
+	}
+	snap.Contributions = fetchContributions(client, username)
+	snap.Activity = fetchActivity(client, projects, opts)
+	snap.Upstream = fetchUpstream(client, username)
+	if cfg.ResumeLanguages {
+		snap.Languages = fetchLanguageStats(client, username, opts)
+	}
+	return snap
+}
+
+// fetchProjects fetches GitHub repos, followed by those of any extra
+// sources in showcase.yaml. The GitHub client retries transient failures
+// with backoff; a failing extra source is left out with a warning.
+func fetchProjects(client *githubapi.Client, username string, opts githubapi.FetchOptions, cfgs []sources.Config) []githubapi.Project {
+	extra, err := sources.FromConfig(cfgs, &http.Client{Timeout: 10 * time.Second}, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not configure project sources: %v\n", err)
+	}
+	srcs := append([]sources.ProjectSource{&sources.GitHub{Client: client, Username: username, Options: opts}}, extra...)
+	projects, err := sources.FetchAll(context.Background(), srcs)
+	var partial *sources.PartialError
+	switch {
+	case errors.As(err, &partial):
+		for _, e := range partial.Errs {
+			fmt.Fprintf(os.Stderr, "Warning: Could not fetch repos from %v\n", e)
+		}
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch GitHub repos: %v\n", err)
+		return nil
+	}
+	fmt.Printf("Fetched %d projects from %d sources\n", len(projects), len(srcs))
+	return projects
+}
+
+// fetchContributions fetches the contribution calendar for the projects
+// page's heatmap, which is left out on failure.
+func fetchContributions(client *githubapi.Client, username string) *githubapi.ContributionCalendar {
+	cal, err := client.FetchContributions(context.Background(), username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch contributions: %v\n", err)
+		return nil
+	}
+	if cal != nil {
+		fmt.Printf("Fetched %d contributions from GitHub\n", cal.Total)
+	}
+	return cal
+}
+
+// fetchUpstream fetches merged pull requests to other people's
+// repositories for the projects page, which leaves them out on failure.
+func fetchUpstream(client *githubapi.Client, username string) []githubapi.UpstreamRepo {
+	repos, err := client.FetchUpstreamContributions(context.Background(), username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch upstream contributions: %v\n", err)
+		return nil
+	}
+	if repos != nil {
+		fmt.Printf("Fetched contributions to %d upstream repositories from GitHub\n", len(repos))
+	}
+	return repos
+}
+
+// fetchActivity fetches recent releases and commits of the showcased
+// projects. The page and feed are still generated, empty, on failure.
+func fetchActivity(client *githubapi.Client, projects []githubapi.Project, opts githubapi.FetchOptions) []githubapi.ActivityItem {
+	activity, err := client.FetchActivity(context.Background(), projects, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch project activity: %v\n", err)
+		return nil
+	}
+	fmt.Printf("Fetched %d activity items from GitHub\n", len(activity))
+	return activity
+}
+
+// fetchLanguageStats fetches the language breakdown shown on the resume.
+// The resume is still generated without it on failure.
+func fetchLanguageStats(client *githubapi.Client, username string, opts githubapi.FetchOptions) []githubapi.LanguageShare {
+	languages, err := client.FetchLanguageStats(context.Background(), username, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch language stats: %v\n", err)
+		return nil
+	}
+	fmt.Printf("Fetched language stats for %d languages\n", len(languages))
+	return languages
+}
+
+// fetchProjectDetail fetches a project's README and release info. On
+// failure the page is still generated from the showcase data alone.
+func fetchProjectDetail(client *githubapi.Client, project githubapi.Project, username string) *githubapi.ProjectDetail {
+	owner := project.Owner
+	if owner == "" {
+		owner = username
+	}
+	detail, err := client.FetchProjectDetail(context.Background(), owner, project.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch details for %s: %v\n", project.Name, err)
+		return &githubapi.ProjectDetail{Project: project}
+	}
+	return detail
+}
+
+// postResponses are the approved comments and verified Webmentions of
+// each post, keyed by post slug.
+type postResponses struct {
+	comments map[string][]*comments.Comment
+	mentions map[string][]webmention.Mention
+}
+
+// loadResponses reads the comments and Webmentions from the server's
+// database.
+func loadResponses(path string) (*postResponses, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = sqlDB.Close() }()
+	q := dbgen.New(sqlDB)
+	commentRows, err := q.AllApprovedComments(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	mentionRows, err := q.AllWebmentions(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	byPost := make(map[string][]dbgen.Webmention)
+	for _, row := range mentionRows {
+		byPost[row.PostSlug] = append(byPost[row.PostSlug], row)
+	}
+	r := &postResponses{
+		comments: comments.ThreadByPost(commentRows),
+		mentions: make(map[string][]webmention.Mention, len(byPost)),
+	}
+	for slug, rows := range byPost {
+		r.mentions[slug] = webmention.FromRows(rows)
+	}
+	return r, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
 		}
 	}
-	return nil
+	return out
 }
 
 func loadTemplates(templatesDir string) (*template.Template, error) {
@@ -259,6 +716,42 @@ func writeSitemap(outDir string, urls []sitemapURL) error {
 	return os.WriteFile(filepath.Join(outDir, "sitemap.xml"), buf.Bytes(), 0o644)
 }
 
+// --- Resume ---
+
+// writeResumeExports writes resume.json, resume.pdf, resume.txt and
+// resume.md into dir.
+func writeResumeExports(dir string, res *resume.Resume) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := res.JSON()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "resume.json"), data, 0o644); err != nil {
+		return err
+	}
+	data, err = res.PDF()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "resume.pdf"), data, 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "resume.txt"), res.Text(), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "resume.md"), res.Markdown(), 0o644)
+}
+
+func writeContactVCard(outDir, staticDir string, res *resume.Resume) error {
+	photo, err := os.ReadFile(filepath.Join(staticDir, "images", "profile.jpg"))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "contact.vcf"), res.VCard(photo), 0o644)
+}
+
 // --- RSS Feed ---
 
 type rssItem struct {
@@ -326,8 +819,47 @@ func writeRSSFeed(outDir, base, siteURL string, posts []blog.Post) error {
 	return os.WriteFile(filepath.Join(feedDir, "feed.xml"), buf.Bytes(), 0o644)
 }
 
+// --- Atom Feed ---
+
+// writeActivityFeed writes projects/activity.xml.
+func writeActivityFeed(outDir, siteURL string, activity []githubapi.ActivityItem) error {
+	data, err := feed.ProjectActivity(siteURL, activity).Encode()
+	if err != nil {
+		return err
+	}
+	feedDir := filepath.Join(outDir, "projects")
+	if err := os.MkdirAll(feedDir, 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(feedDir, "activity.xml"), data, 0o644)
+}
+
 // --- File helpers ---
 
+// copyProjectImages copies the cached images the projects point at into
+// dstDir and returns how many were copied.
+func copyProjectImages(images *imagecache.Cache, projects []githubapi.Project, prefix, dstDir string) (int, error) {
+	n := 0
+	for _, p := range projects {
+		name, ok := strings.CutPrefix(p.Image, prefix)
+		if !ok || !imagecache.ValidName(name) {
+			continue
+		}
+		if err := os.MkdirAll(dstDir, 0o750); err != nil {
+			return n, err
+		}
+		data, err := os.ReadFile(filepath.Join(images.Dir(), name)) // #nosec G304 -- name is a validated content hash.
+		if err != nil {
+			return n, err
+		}
+		if err := os.WriteFile(filepath.Join(dstDir, name), data, 0o644); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
 func copyDir(srcDir, dstDir string) (err error) {
 	if err := os.MkdirAll(dstDir, 0o750); err != nil {
 		return err
@@ -1,9 +1,21 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"srv.exe.dev/db"
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/imagecache"
+	"srv.exe.dev/internal/webmention"
 )
 
 func TestCopyDirCopiesNestedFiles(t *testing.T) {
@@ -33,3 +45,112 @@ func TestCopyDirCopiesNestedFiles(t *testing.T) {
 		t.Fatalf("expected copied file contents to match source")
 	}
 }
+
+func TestCopyProjectImagesCopiesCachedPreviews(t *testing.T) {
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png-bytes"))
+	}))
+	defer images.Close()
+
+	cache, err := imagecache.New(t.TempDir(), images.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects := []githubapi.Project{
+		{Name: "custom", OpenGraphImage: images.URL + "/preview.png", CustomOpenGraphImage: true},
+		{Name: "override", Image: "https://example.com/shot.png"},
+	}
+	if err := cache.LocalizeProjects(context.Background(), projects, "/portfolio/static/projects/"); err != nil {
+		t.Fatal(err)
+	}
+
+	dstDir := filepath.Join(t.TempDir(), "static", "projects")
+	n, err := copyProjectImages(cache, projects, "/portfolio/static/projects/", dstDir)
+	if err != nil || n != 1 {
+		t.Fatalf("copyProjectImages = %d, %v; want one image", n, err)
+	}
+	name := strings.TrimPrefix(projects[0].Image, "/portfolio/static/projects/")
+	if data, err := os.ReadFile(filepath.Join(dstDir, name)); err != nil || string(data) != "png-bytes" {
+		t.Fatalf("copied image = %q, %v", data, err)
+	}
+}
+
+func TestStaticFiltersCoverEveryChip(t *testing.T) {
+	projects := []githubapi.Project{
+		{Name: "runeforge", Language: "Go", Topics: []string{"game"}},
+		{Name: "atlas", Language: "C++"},
+	}
+	var paths []string
+	for _, f := range staticFilters(projects) {
+		paths = append(paths, f.Path())
+	}
+	// Each sort order alone and with each of three facets, less the
+	// unfiltered projects page.
+	if len(paths) != 15 {
+		t.Fatalf("expected 15 views, got %d: %v", len(paths), paths)
+	}
+	for _, want := range []string{"lang/cpp/", "topic/game/sort/stars/", "sort/name/"} {
+		if !slices.Contains(paths, want) {
+			t.Fatalf("expected a view at %s, got %v", want, paths)
+		}
+	}
+	if staticFilters(nil) != nil {
+		t.Fatal("expected no views without projects")
+	}
+}
+
+func TestLoadResponsesBakesCommentsAndWebmentions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "site.sqlite3")
+	sqlDB, err := db.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RunMigrations(sqlDB); err != nil {
+		t.Fatal(err)
+	}
+	q := dbgen.New(sqlDB)
+	now := time.Now().UTC()
+	insert := func(slug, author string, parent *int64, approved bool) int64 {
+		t.Helper()
+		id, err := q.InsertComment(context.Background(), dbgen.InsertCommentParams{PostSlug: slug, ParentID: parent, Author: author, Body: "hi", Ip: "192.0.2.1", CreatedAt: now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if approved {
+			if _, err := q.ApproveComment(context.Background(), dbgen.ApproveCommentParams{ApprovedAt: &now, ID: id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return id
+	}
+	ada := insert("hello", "ada", nil, true)
+	insert("hello", "bob", &ada, true)
+	insert("hello", "spam", nil, false)
+	insert("other", "cy", nil, true)
+	if err := q.UpsertWebmention(context.Background(), dbgen.UpsertWebmentionParams{
+		Source: "https://example.com/like", Target: "https://hexsleeves.github.io/portfolio/blog/hello/", PostSlug: "hello",
+		Type: "like", AuthorName: "dee", VerifiedAt: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	responses, err := loadResponses(path)
+	if err != nil {
+		t.Fatalf("loadResponses returned error: %v", err)
+	}
+	hello := responses.comments["hello"]
+	if len(responses.comments) != 2 || len(hello) != 1 || hello[0].Author != "ada" || len(hello[0].Replies) != 1 {
+		t.Fatalf("expected ada's thread with bob's reply and no pending comment, got %+v", responses.comments)
+	}
+	if mentions := responses.mentions["hello"]; len(mentions) != 1 || mentions[0].Type != webmention.TypeLike {
+		t.Fatalf("expected dee's like, got %+v", responses.mentions)
+	}
+
+	if _, err := loadResponses(filepath.Join(t.TempDir(), "missing.sqlite3")); err == nil {
+		t.Fatal("expected a missing database to be an error rather than created")
+	}
+}
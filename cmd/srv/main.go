@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
+	"srv.exe.dev/db"
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/analytics"
 	"srv.exe.dev/srv"
 )
 
@@ -26,6 +31,9 @@ func runMain(stderr io.Writer) int {
 
 func run() error {
 	flag.Parse()
+	if flag.Arg(0) == "export" {
+		return runExport(context.Background(), flag.Args()[1:], os.Stdout, time.Now())
+	}
 	hostname, err := os.Hostname()
 	if err != nil {
 		hostname = "unknown"
@@ -36,3 +44,39 @@ func run() error {
 	}
 	return server.Serve(*flagListenAddr)
 }
+
+// runExport writes the page views in the server's database to out, like
+// /admin/stats/export:
+//
+//	portfolio export -format json -from 2026-01-01 -to 2026-01-31
+func runExport(ctx context.Context, args []string, out io.Writer, now time.Time) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dbPath := fs.String("db", "db.sqlite3", "database to export from")
+	format := fs.String("format", "csv", "output format, csv or json")
+	from := fs.String("from", "", "first day, YYYY-MM-DD (default: 30 days before -to)")
+	to := fs.String("to", "", "last day, YYYY-MM-DD (default: today)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !analytics.ValidExportFormat(*format) {
+		return fmt.Errorf("export: format must be csv or json, not %q", *format)
+	}
+	rng, err := analytics.ParseRange(*from, *to, now)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	// Don't let db.Open create a missing database.
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	wdb, err := db.Open(*dbPath)
+	if err != nil {
+		return fmt.Errorf("export: open db: %w", err)
+	}
+	defer func() { _ = wdb.Close() }()
+	views, err := analytics.PageViews(ctx, dbgen.New(wdb), rng)
+	if err != nil {
+		return fmt.Errorf("export page views: %w", err)
+	}
+	return analytics.Export(out, *format, views)
+}
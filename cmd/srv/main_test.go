@@ -2,9 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"srv.exe.dev/db"
+	"srv.exe.dev/db/dbgen"
 )
 
 func TestRunMainReturnsExitCodes(t *testing.T) {
@@ -37,3 +43,52 @@ func TestRunMainReturnsExitCodes(t *testing.T) {
 		}
 	})
 }
+
+func TestRunExport(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "db.sqlite3")
+	wdb, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RunMigrations(wdb); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	for _, v := range []dbgen.InsertPageViewParams{
+		{VisitorID: "a", Path: "/blog/hello", ReferrerHost: "news.example", ViewedAt: now.AddDate(0, 0, -2)},
+		{VisitorID: "b", Path: "/", ViewedAt: now.AddDate(0, 0, -40)},
+	} {
+		if err := dbgen.New(wdb).InsertPageView(context.Background(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := wdb.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runExport(context.Background(), []string{"-db", dbPath}, &out, now); err != nil {
+		t.Fatal(err)
+	}
+	if want := "day,path,referrer_host,views,visitors\n2026-05-08,/blog/hello,news.example,1,1\n"; out.String() != want {
+		t.Fatalf("csv export = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runExport(context.Background(), []string{"-db", dbPath, "-format", "json", "-from", "2026-03-01", "-to", "2026-04-30"}, &out, now); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"day": "2026-03-31"`) || strings.Contains(out.String(), "hello") {
+		t.Fatalf("expected only the older view, got %s", out.String())
+	}
+
+	for name, args := range map[string][]string{
+		"bad format": {"-db", dbPath, "-format", "xml"},
+		"bad range":  {"-db", dbPath, "-from", "2026-05-02", "-to", "2026-05-01"},
+		"missing db": {"-db", filepath.Join(t.TempDir(), "missing.sqlite3")},
+	} {
+		if err := runExport(context.Background(), args, &out, now); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
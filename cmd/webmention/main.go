@@ -0,0 +1,103 @@
+// Command webmention sends Webmentions from newly published blog posts to
+// every page they link to that accepts them.
+//
+//	go run ./cmd/webmention -base /portfolio my-new-post
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"srv.exe.dev/internal/blog"
+	"srv.exe.dev/internal/webmention"
+)
+
+func main() {
+	site := flag.String("site", "https://hexsleeves.github.io", "site the posts are published on")
+	basePath := flag.String("base", "", "base path for URLs (e.g., /portfolio for GitHub Pages)")
+	postsDir := flag.String("posts", "", "directory of blog posts (default: srv/posts)")
+	dryRun := flag.Bool("dry-run", false, "list the endpoints that would be notified without sending")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: webmention [flags] post-slug...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *postsDir == "" {
+		_, thisFile, _, _ := runtime.Caller(0)
+		*postsDir = filepath.Join(filepath.Dir(thisFile), "..", "..", "srv", "posts")
+	}
+	siteURL := strings.TrimSuffix(*site, "/") + strings.TrimSuffix(*basePath, "/")
+	client := webmention.NewClient(15 * time.Second)
+
+	failed := 0
+	for _, slug := range flag.Args() {
+		post, err := blog.LoadPost(*postsDir, slug+".md")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading post %s: %v\n", slug, err)
+			os.Exit(1)
+		}
+		if !post.Published {
+			fmt.Fprintf(os.Stderr, "Error: post %s is not published\n", slug)
+			os.Exit(1)
+		}
+		source := siteURL + "/blog/" + slug
+		failed += sendWebmentions(context.Background(), client, source, string(post.Content), *dryRun, os.Stdout)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d webmentions failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+// sendWebmentions notifies every external page linked from content that
+// source links to it, returning how many could not be notified. Pages
+// without an endpoint are skipped.
+func sendWebmentions(ctx context.Context, client *http.Client, source, content string, dryRun bool, out io.Writer) int {
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Warning: Invalid source %s: %v\n", source, err)
+		return 1
+	}
+	failed := 0
+	for _, target := range webmention.Links(content) {
+		if u, err := url.Parse(target); err == nil && strings.EqualFold(u.Host, sourceURL.Host) {
+			continue
+		}
+		endpoint, err := webmention.Discover(ctx, client, target)
+		switch {
+		case errors.Is(err, webmention.ErrNoEndpoint):
+			_, _ = fmt.Fprintf(out, "No webmention endpoint for %s\n", target)
+			continue
+		case err != nil:
+			_, _ = fmt.Fprintf(out, "Warning: Could not discover endpoint for %s: %v\n", target, err)
+			failed++
+			continue
+		}
+		if dryRun {
+			_, _ = fmt.Fprintf(out, "Would send webmention for %s to %s\n", target, endpoint)
+			continue
+		}
+		if err := webmention.Send(ctx, client, endpoint, source, target); err != nil {
+			_, _ = fmt.Fprintf(out, "Warning: Could not send webmention for %s: %v\n", target, err)
+			failed++
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "Sent webmention for %s to %s\n", target, endpoint)
+	}
+	return failed
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendWebmentionsNotifiesLinkedPages(t *testing.T) {
+	var received []string
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/header":
+			w.Header().Set("Link", `</endpoint?via=header>; rel="webmention"`)
+			_, _ = io.WriteString(w, "ok")
+		case "/html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = io.WriteString(w, `<html><head><link rel="webmention" href="/endpoint?via=html"></head></html>`)
+		case "/none":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = io.WriteString(w, `<p>no endpoint</p>`)
+		case "/endpoint":
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			received = append(received, r.URL.Query().Get("via")+" "+r.PostForm.Get("source")+" "+r.PostForm.Get("target"))
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer stand.Close()
+
+	content := fmt.Sprintf(`<p><a href="%[1]s/header">one</a>, <a href="%[1]s/html">two</a>,
+		<a href="%[1]s/none">three</a>, <a href="%[1]s/missing">four</a>,
+		<a href="/blog/other">relative</a>, <a href="%[1]s/header">again</a></p>`, stand.URL)
+	source := "https://example.com/blog/hello"
+
+	var out bytes.Buffer
+	if failed := sendWebmentions(context.Background(), stand.Client(), source, content, true, &out); failed != 1 || len(received) != 0 {
+		t.Fatalf("dry run: failed = %d, received %v\n%s", failed, received, out.String())
+	}
+	if !strings.Contains(out.String(), "Would send webmention for "+stand.URL+"/html") {
+		t.Fatalf("expected the dry run to list endpoints, got %s", out.String())
+	}
+
+	out.Reset()
+	failed := sendWebmentions(context.Background(), stand.Client(), source, content, false, &out)
+	want := []string{
+		"header " + source + " " + stand.URL + "/header",
+		"html " + source + " " + stand.URL + "/html",
+	}
+	if failed != 1 || strings.Join(received, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("failed = %d, received %q; want 1 failure (the 404) and %q", failed, received, want)
+	}
+	if !strings.Contains(out.String(), "No webmention endpoint for "+stand.URL+"/none") {
+		t.Fatalf("expected pages without an endpoint to be reported, got %s", out.String())
+	}
+}
@@ -19,24 +19,18 @@ import (
 var migrationFS embed.FS
 
 // Open opens an sqlite database and prepares pragmas suitable for a small web app.
+// foreign_keys and busy_timeout only last for one connection, so they are
+// passed in the DSN, which applies them to every connection the pool opens.
 func Open(path string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", path)
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(1000)")
 	if err != nil {
 		return nil, err
 	}
-	// Light pragmas similar
-	if _, err := db.Exec("PRAGMA foreign_keys=ON;"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("enable foreign keys: %w", err)
-	}
+	// WAL mode is stored in the database file, so once is enough.
 	if _, err := db.Exec("PRAGMA journal_mode=wal;"); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("set WAL: %w", err)
 	}
-	if _, err := db.Exec("PRAGMA busy_timeout=1000;"); err != nil {
-		_ = db.Close()
-		return nil, fmt.Errorf("set busy_timeout: %w", err)
-	}
 	return db, nil
 }
 
@@ -0,0 +1,236 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: comments.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+)
+
+const allApprovedComments = `-- name: AllApprovedComments :many
+SELECT
+  id, post_slug, parent_id, author, body, ip, created_at, approved_at
+FROM
+  comments
+WHERE
+  approved_at IS NOT NULL
+ORDER BY
+  post_slug,
+  created_at,
+  id
+`
+
+func (q *Queries) AllApprovedComments(ctx context.Context) ([]Comment, error) {
+	rows, err := q.db.QueryContext(ctx, allApprovedComments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Comment{}
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostSlug,
+			&i.ParentID,
+			&i.Author,
+			&i.Body,
+			&i.Ip,
+			&i.CreatedAt,
+			&i.ApprovedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const approveComment = `-- name: ApproveComment :execrows
+UPDATE comments
+SET
+  approved_at = ?
+WHERE
+  id = ?
+  AND approved_at IS NULL
+`
+
+type ApproveCommentParams struct {
+	ApprovedAt *time.Time `json:"approved_at"`
+	ID         int64      `json:"id"`
+}
+
+func (q *Queries) ApproveComment(ctx context.Context, arg ApproveCommentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, approveComment, arg.ApprovedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const approvedComments = `-- name: ApprovedComments :many
+SELECT
+  id, post_slug, parent_id, author, body, ip, created_at, approved_at
+FROM
+  comments
+WHERE
+  post_slug = ?
+  AND approved_at IS NOT NULL
+ORDER BY
+  created_at,
+  id
+`
+
+func (q *Queries) ApprovedComments(ctx context.Context, postSlug string) ([]Comment, error) {
+	rows, err := q.db.QueryContext(ctx, approvedComments, postSlug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Comment{}
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostSlug,
+			&i.ParentID,
+			&i.Author,
+			&i.Body,
+			&i.Ip,
+			&i.CreatedAt,
+			&i.ApprovedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const commentByID = `-- name: CommentByID :one
+SELECT
+  id, post_slug, parent_id, author, body, ip, created_at, approved_at
+FROM
+  comments
+WHERE
+  id = ?
+`
+
+func (q *Queries) CommentByID(ctx context.Context, id int64) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, commentByID, id)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.PostSlug,
+		&i.ParentID,
+		&i.Author,
+		&i.Body,
+		&i.Ip,
+		&i.CreatedAt,
+		&i.ApprovedAt,
+	)
+	return i, err
+}
+
+const deleteComment = `-- name: DeleteComment :execrows
+DELETE FROM comments
+WHERE
+  id = ?
+`
+
+func (q *Queries) DeleteComment(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteComment, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertComment = `-- name: InsertComment :one
+INSERT INTO
+  comments (post_slug, parent_id, author, body, ip, created_at)
+VALUES
+  (?, ?, ?, ?, ?, ?) RETURNING id
+`
+
+type InsertCommentParams struct {
+	PostSlug  string    `json:"post_slug"`
+	ParentID  *int64    `json:"parent_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Ip        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) InsertComment(ctx context.Context, arg InsertCommentParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertComment,
+		arg.PostSlug,
+		arg.ParentID,
+		arg.Author,
+		arg.Body,
+		arg.Ip,
+		arg.CreatedAt,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const pendingComments = `-- name: PendingComments :many
+SELECT
+  id, post_slug, parent_id, author, body, ip, created_at, approved_at
+FROM
+  comments
+WHERE
+  approved_at IS NULL
+ORDER BY
+  created_at,
+  id
+`
+
+func (q *Queries) PendingComments(ctx context.Context) ([]Comment, error) {
+	rows, err := q.db.QueryContext(ctx, pendingComments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Comment{}
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostSlug,
+			&i.ParentID,
+			&i.Author,
+			&i.Body,
+			&i.Ip,
+			&i.CreatedAt,
+			&i.ApprovedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
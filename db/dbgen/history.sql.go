@@ -0,0 +1,174 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: history.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+)
+
+const allProjectHistorySince = `-- name: AllProjectHistorySince :many
+SELECT
+  forge,
+  owner,
+  project,
+  day,
+  stars,
+  forks
+FROM
+  project_history
+WHERE
+  day >= ?
+ORDER BY
+  forge,
+  owner,
+  project,
+  day
+`
+
+type AllProjectHistorySinceRow struct {
+	Forge   string `json:"forge"`
+	Owner   string `json:"owner"`
+	Project string `json:"project"`
+	Day     string `json:"day"`
+	Stars   int64  `json:"stars"`
+	Forks   int64  `json:"forks"`
+}
+
+func (q *Queries) AllProjectHistorySince(ctx context.Context, day string) ([]AllProjectHistorySinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, allProjectHistorySince, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AllProjectHistorySinceRow{}
+	for rows.Next() {
+		var i AllProjectHistorySinceRow
+		if err := rows.Scan(
+			&i.Forge,
+			&i.Owner,
+			&i.Project,
+			&i.Day,
+			&i.Stars,
+			&i.Forks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const projectHistorySince = `-- name: ProjectHistorySince :many
+SELECT
+  forge,
+  owner,
+  project,
+  day,
+  stars,
+  forks
+FROM
+  project_history
+WHERE
+  forge = ?
+  AND owner = ?
+  AND project = ?
+  AND day >= ?
+ORDER BY
+  day
+`
+
+type ProjectHistorySinceParams struct {
+	Forge   string `json:"forge"`
+	Owner   string `json:"owner"`
+	Project string `json:"project"`
+	Day     string `json:"day"`
+}
+
+type ProjectHistorySinceRow struct {
+	Forge   string `json:"forge"`
+	Owner   string `json:"owner"`
+	Project string `json:"project"`
+	Day     string `json:"day"`
+	Stars   int64  `json:"stars"`
+	Forks   int64  `json:"forks"`
+}
+
+func (q *Queries) ProjectHistorySince(ctx context.Context, arg ProjectHistorySinceParams) ([]ProjectHistorySinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, projectHistorySince,
+		arg.Forge,
+		arg.Owner,
+		arg.Project,
+		arg.Day,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProjectHistorySinceRow{}
+	for rows.Next() {
+		var i ProjectHistorySinceRow
+		if err := rows.Scan(
+			&i.Forge,
+			&i.Owner,
+			&i.Project,
+			&i.Day,
+			&i.Stars,
+			&i.Forks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertProjectHistory = `-- name: UpsertProjectHistory :exec
+INSERT INTO
+  project_history (forge, owner, project, day, stars, forks, recorded_at)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (forge, owner, project, day) DO
+UPDATE
+SET
+  stars = excluded.stars,
+  forks = excluded.forks,
+  recorded_at = excluded.recorded_at
+`
+
+type UpsertProjectHistoryParams struct {
+	Forge      string    `json:"forge"`
+	Owner      string    `json:"owner"`
+	Project    string    `json:"project"`
+	Day        string    `json:"day"`
+	Stars      int64     `json:"stars"`
+	Forks      int64     `json:"forks"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func (q *Queries) UpsertProjectHistory(ctx context.Context, arg UpsertProjectHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProjectHistory,
+		arg.Forge,
+		arg.Owner,
+		arg.Project,
+		arg.Day,
+		arg.Stars,
+		arg.Forks,
+		arg.RecordedAt,
+	)
+	return err
+}
@@ -8,15 +8,81 @@ import (
 	"time"
 )
 
+type Comment struct {
+	ID         int64      `json:"id"`
+	PostSlug   string     `json:"post_slug"`
+	ParentID   *int64     `json:"parent_id"`
+	Author     string     `json:"author"`
+	Body       string     `json:"body"`
+	Ip         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ApprovedAt *time.Time `json:"approved_at"`
+}
+
+type DailyPageView struct {
+	Day          string `json:"day"`
+	Path         string `json:"path"`
+	ReferrerHost string `json:"referrer_host"`
+	Views        int64  `json:"views"`
+	Visitors     int64  `json:"visitors"`
+}
+
+type DailyVisitor struct {
+	Day      string `json:"day"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
 type Migration struct {
 	MigrationNumber int64     `json:"migration_number"`
 	MigrationName   string    `json:"migration_name"`
 	ExecutedAt      time.Time `json:"executed_at"`
 }
 
+type PageView struct {
+	ID           int64     `json:"id"`
+	VisitorID    string    `json:"visitor_id"`
+	Path         string    `json:"path"`
+	ReferrerHost string    `json:"referrer_host"`
+	UtmSource    string    `json:"utm_source"`
+	UtmMedium    string    `json:"utm_medium"`
+	UtmCampaign  string    `json:"utm_campaign"`
+	ViewedAt     time.Time `json:"viewed_at"`
+}
+
+type ProjectHistory struct {
+	Forge      string    `json:"forge"`
+	Owner      string    `json:"owner"`
+	Project    string    `json:"project"`
+	Day        string    `json:"day"`
+	Stars      int64     `json:"stars"`
+	Forks      int64     `json:"forks"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
 type Visitor struct {
 	ID        string    `json:"id"`
 	ViewCount int64     `json:"view_count"`
 	CreatedAt time.Time `json:"created_at"`
 	LastSeen  time.Time `json:"last_seen"`
 }
+
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	Action     string    `json:"action"`
+	Repository string    `json:"repository"`
+	Handled    bool      `json:"handled"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+type Webmention struct {
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	PostSlug   string    `json:"post_slug"`
+	Type       string    `json:"type"`
+	AuthorName string    `json:"author_name"`
+	AuthorUrl  string    `json:"author_url"`
+	Content    string    `json:"content"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
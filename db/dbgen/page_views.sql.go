@@ -0,0 +1,442 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: page_views.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+)
+
+const activeVisitors = `-- name: ActiveVisitors :one
+SELECT
+  COUNT(DISTINCT visitor_id) AS visitors,
+  COUNT(*) AS views
+FROM
+  page_views
+WHERE
+  viewed_at >= ?
+`
+
+type ActiveVisitorsRow struct {
+	Visitors int64 `json:"visitors"`
+	Views    int64 `json:"views"`
+}
+
+func (q *Queries) ActiveVisitors(ctx context.Context, viewedAt time.Time) (ActiveVisitorsRow, error) {
+	row := q.db.QueryRowContext(ctx, activeVisitors, viewedAt)
+	var i ActiveVisitorsRow
+	err := row.Scan(&i.Visitors, &i.Views)
+	return i, err
+}
+
+const dailyPageViews = `-- name: DailyPageViews :many
+SELECT
+  day,
+  views,
+  visitors
+FROM
+  daily_visitors
+WHERE
+  day >= ?1
+  AND day < ?2
+UNION ALL
+SELECT
+  CAST(substr(viewed_at, 1, 10) AS TEXT) AS day,
+  COUNT(*) AS views,
+  COUNT(DISTINCT visitor_id) AS visitors
+FROM
+  page_views
+WHERE
+  viewed_at >= ?3
+  AND viewed_at < ?4
+GROUP BY
+  CAST(substr(viewed_at, 1, 10) AS TEXT)
+ORDER BY
+  day
+`
+
+type DailyPageViewsParams struct {
+	FromDay  string    `json:"from_day"`
+	UntilDay string    `json:"until_day"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+}
+
+// Days older than the retention window come from daily_visitors, newer
+// ones from page_views. Timestamps are stored in UTC as Go formats them,
+// starting with the date, so substr(viewed_at, 1, 10) is the day.
+func (q *Queries) DailyPageViews(ctx context.Context, arg DailyPageViewsParams) ([]DailyVisitor, error) {
+	rows, err := q.db.QueryContext(ctx, dailyPageViews,
+		arg.FromDay,
+		arg.UntilDay,
+		arg.Since,
+		arg.Until,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DailyVisitor{}
+	for rows.Next() {
+		var i DailyVisitor
+		if err := rows.Scan(&i.Day, &i.Views, &i.Visitors); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dailyPageViewsByPath = `-- name: DailyPageViewsByPath :many
+SELECT
+  day,
+  path,
+  referrer_host,
+  views,
+  visitors
+FROM
+  daily_page_views
+WHERE
+  day >= ?1
+  AND day < ?2
+UNION ALL
+SELECT
+  day,
+  path,
+  referrer_host,
+  COUNT(*) AS views,
+  SUM(first_view) AS visitors
+FROM
+  (
+    SELECT
+      CAST(substr(viewed_at, 1, 10) AS TEXT) AS day,
+      path,
+      referrer_host,
+      ROW_NUMBER() OVER (
+        PARTITION BY
+          substr(viewed_at, 1, 10),
+          path,
+          visitor_id
+        ORDER BY
+          id
+      ) = 1 AS first_view
+    FROM
+      page_views
+    WHERE
+      viewed_at >= ?3
+      AND viewed_at < ?4
+  )
+GROUP BY
+  day,
+  path,
+  referrer_host
+ORDER BY
+  day,
+  path,
+  referrer_host
+`
+
+type DailyPageViewsByPathParams struct {
+	FromDay  string    `json:"from_day"`
+	UntilDay string    `json:"until_day"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+}
+
+// Views per day, path and referrer, from daily_page_views and page_views
+// like DailyPageViews. A visitor counts once per day and path, towards the
+// referrer of their first view of it, so visitors sum exactly over
+// referrers.
+func (q *Queries) DailyPageViewsByPath(ctx context.Context, arg DailyPageViewsByPathParams) ([]DailyPageView, error) {
+	rows, err := q.db.QueryContext(ctx, dailyPageViewsByPath,
+		arg.FromDay,
+		arg.UntilDay,
+		arg.Since,
+		arg.Until,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DailyPageView{}
+	for rows.Next() {
+		var i DailyPageView
+		if err := rows.Scan(
+			&i.Day,
+			&i.Path,
+			&i.ReferrerHost,
+			&i.Views,
+			&i.Visitors,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePageViewsBefore = `-- name: DeletePageViewsBefore :execrows
+DELETE FROM page_views
+WHERE
+  viewed_at < ?
+`
+
+func (q *Queries) DeletePageViewsBefore(ctx context.Context, viewedAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePageViewsBefore, viewedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertPageView = `-- name: InsertPageView :exec
+INSERT INTO
+  page_views (
+    visitor_id,
+    path,
+    referrer_host,
+    utm_source,
+    utm_medium,
+    utm_campaign,
+    viewed_at
+  )
+VALUES
+  (?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertPageViewParams struct {
+	VisitorID    string    `json:"visitor_id"`
+	Path         string    `json:"path"`
+	ReferrerHost string    `json:"referrer_host"`
+	UtmSource    string    `json:"utm_source"`
+	UtmMedium    string    `json:"utm_medium"`
+	UtmCampaign  string    `json:"utm_campaign"`
+	ViewedAt     time.Time `json:"viewed_at"`
+}
+
+func (q *Queries) InsertPageView(ctx context.Context, arg InsertPageViewParams) error {
+	_, err := q.db.ExecContext(ctx, insertPageView,
+		arg.VisitorID,
+		arg.Path,
+		arg.ReferrerHost,
+		arg.UtmSource,
+		arg.UtmMedium,
+		arg.UtmCampaign,
+		arg.ViewedAt,
+	)
+	return err
+}
+
+const recentPages = `-- name: RecentPages :many
+SELECT
+  path,
+  COUNT(*) AS views,
+  COUNT(DISTINCT visitor_id) AS visitors
+FROM
+  page_views
+WHERE
+  viewed_at >= ?
+GROUP BY
+  path
+ORDER BY
+  views DESC,
+  path
+LIMIT
+  ?
+`
+
+type RecentPagesParams struct {
+	ViewedAt time.Time `json:"viewed_at"`
+	Limit    int64     `json:"limit"`
+}
+
+type RecentPagesRow struct {
+	Path     string `json:"path"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+func (q *Queries) RecentPages(ctx context.Context, arg RecentPagesParams) ([]RecentPagesRow, error) {
+	rows, err := q.db.QueryContext(ctx, recentPages, arg.ViewedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RecentPagesRow{}
+	for rows.Next() {
+		var i RecentPagesRow
+		if err := rows.Scan(&i.Path, &i.Views, &i.Visitors); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rollUpPageViews = `-- name: RollUpPageViews :exec
+INSERT INTO
+  daily_page_views (day, path, referrer_host, views, visitors)
+SELECT
+  day,
+  path,
+  referrer_host,
+  COUNT(*),
+  SUM(first_view)
+FROM
+  (
+    SELECT
+      CAST(substr(viewed_at, 1, 10) AS TEXT) AS day,
+      path,
+      referrer_host,
+      ROW_NUMBER() OVER (
+        PARTITION BY
+          substr(viewed_at, 1, 10),
+          path,
+          visitor_id
+        ORDER BY
+          id
+      ) = 1 AS first_view
+    FROM
+      page_views
+    WHERE
+      viewed_at < ?
+  )
+WHERE
+  true
+GROUP BY
+  day,
+  path,
+  referrer_host
+ON CONFLICT (day, path, referrer_host) DO
+UPDATE
+SET
+  views = views + excluded.views,
+  visitors = visitors + excluded.visitors
+`
+
+// Visitors are attributed like in DailyPageViewsByPath.
+func (q *Queries) RollUpPageViews(ctx context.Context, viewedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, rollUpPageViews, viewedAt)
+	return err
+}
+
+const rollUpVisitors = `-- name: RollUpVisitors :exec
+INSERT INTO
+  daily_visitors (day, views, visitors)
+SELECT
+  CAST(substr(viewed_at, 1, 10) AS TEXT),
+  COUNT(*),
+  COUNT(DISTINCT visitor_id)
+FROM
+  page_views
+WHERE
+  viewed_at < ?
+GROUP BY
+  1
+ON CONFLICT (day) DO
+UPDATE
+SET
+  views = views + excluded.views,
+  visitors = visitors + excluded.visitors
+`
+
+func (q *Queries) RollUpVisitors(ctx context.Context, viewedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, rollUpVisitors, viewedAt)
+	return err
+}
+
+const topCampaigns = `-- name: TopCampaigns :many
+SELECT
+  utm_source,
+  utm_medium,
+  utm_campaign,
+  COUNT(*) AS views,
+  COUNT(DISTINCT visitor_id) AS visitors
+FROM
+  page_views
+WHERE
+  viewed_at >= ?1
+  AND viewed_at < ?2
+  AND (
+    utm_source != ''
+    OR utm_medium != ''
+    OR utm_campaign != ''
+  )
+GROUP BY
+  utm_source,
+  utm_medium,
+  utm_campaign
+ORDER BY
+  views DESC,
+  utm_source,
+  utm_medium,
+  utm_campaign
+LIMIT
+  ?3
+`
+
+type TopCampaignsParams struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+	Limit int64     `json:"limit"`
+}
+
+type TopCampaignsRow struct {
+	UtmSource   string `json:"utm_source"`
+	UtmMedium   string `json:"utm_medium"`
+	UtmCampaign string `json:"utm_campaign"`
+	Views       int64  `json:"views"`
+	Visitors    int64  `json:"visitors"`
+}
+
+// Campaigns are not rolled up, so only views inside the retention window
+// count.
+func (q *Queries) TopCampaigns(ctx context.Context, arg TopCampaignsParams) ([]TopCampaignsRow, error) {
+	rows, err := q.db.QueryContext(ctx, topCampaigns, arg.Since, arg.Until, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TopCampaignsRow{}
+	for rows.Next() {
+		var i TopCampaignsRow
+		if err := rows.Scan(
+			&i.UtmSource,
+			&i.UtmMedium,
+			&i.UtmCampaign,
+			&i.Views,
+			&i.Visitors,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -10,6 +10,20 @@ import (
 	"time"
 )
 
+const deleteVisitorsSeenBefore = `-- name: DeleteVisitorsSeenBefore :execrows
+DELETE FROM visitors
+WHERE
+  last_seen < ?
+`
+
+func (q *Queries) DeleteVisitorsSeenBefore(ctx context.Context, lastSeen time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteVisitorsSeenBefore, lastSeen)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const upsertVisitor = `-- name: UpsertVisitor :exec
 INSERT INTO
   visitors (id, view_count, created_at, last_seen)
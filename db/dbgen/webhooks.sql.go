@@ -0,0 +1,42 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+)
+
+const insertWebhookDelivery = `-- name: InsertWebhookDelivery :execrows
+INSERT INTO
+  webhook_deliveries (id, event, action, repository, handled, received_at)
+VALUES
+  (?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING
+`
+
+type InsertWebhookDeliveryParams struct {
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	Action     string    `json:"action"`
+	Repository string    `json:"repository"`
+	Handled    bool      `json:"handled"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+func (q *Queries) InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertWebhookDelivery,
+		arg.ID,
+		arg.Event,
+		arg.Action,
+		arg.Repository,
+		arg.Handled,
+		arg.ReceivedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,167 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webmentions.sql
+
+package dbgen
+
+import (
+	"context"
+	"time"
+)
+
+const allWebmentions = `-- name: AllWebmentions :many
+SELECT
+  source, target, post_slug, type, author_name, author_url, content, verified_at
+FROM
+  webmentions
+ORDER BY
+  post_slug,
+  verified_at,
+  source
+`
+
+func (q *Queries) AllWebmentions(ctx context.Context) ([]Webmention, error) {
+	rows, err := q.db.QueryContext(ctx, allWebmentions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Webmention{}
+	for rows.Next() {
+		var i Webmention
+		if err := rows.Scan(
+			&i.Source,
+			&i.Target,
+			&i.PostSlug,
+			&i.Type,
+			&i.AuthorName,
+			&i.AuthorUrl,
+			&i.Content,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteWebmention = `-- name: DeleteWebmention :execrows
+DELETE FROM webmentions
+WHERE
+  source = ?
+  AND target = ?
+`
+
+type DeleteWebmentionParams struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+func (q *Queries) DeleteWebmention(ctx context.Context, arg DeleteWebmentionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebmention, arg.Source, arg.Target)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertWebmention = `-- name: UpsertWebmention :exec
+INSERT INTO
+  webmentions (
+    source,
+    target,
+    post_slug,
+    type,
+    author_name,
+    author_url,
+    content,
+    verified_at
+  )
+VALUES
+  (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (source, target) DO
+UPDATE
+SET
+  post_slug = excluded.post_slug,
+  type = excluded.type,
+  author_name = excluded.author_name,
+  author_url = excluded.author_url,
+  content = excluded.content,
+  verified_at = excluded.verified_at
+`
+
+type UpsertWebmentionParams struct {
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	PostSlug   string    `json:"post_slug"`
+	Type       string    `json:"type"`
+	AuthorName string    `json:"author_name"`
+	AuthorUrl  string    `json:"author_url"`
+	Content    string    `json:"content"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+func (q *Queries) UpsertWebmention(ctx context.Context, arg UpsertWebmentionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWebmention,
+		arg.Source,
+		arg.Target,
+		arg.PostSlug,
+		arg.Type,
+		arg.AuthorName,
+		arg.AuthorUrl,
+		arg.Content,
+		arg.VerifiedAt,
+	)
+	return err
+}
+
+const webmentionsForPost = `-- name: WebmentionsForPost :many
+SELECT
+  source, target, post_slug, type, author_name, author_url, content, verified_at
+FROM
+  webmentions
+WHERE
+  post_slug = ?
+ORDER BY
+  verified_at,
+  source
+`
+
+func (q *Queries) WebmentionsForPost(ctx context.Context, postSlug string) ([]Webmention, error) {
+	rows, err := q.db.QueryContext(ctx, webmentionsForPost, postSlug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Webmention{}
+	for rows.Next() {
+		var i Webmention
+		if err := rows.Scan(
+			&i.Source,
+			&i.Target,
+			&i.PostSlug,
+			&i.Type,
+			&i.AuthorName,
+			&i.AuthorUrl,
+			&i.Content,
+			&i.VerifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,19 @@
+-- GitHub webhook deliveries, keyed by X-GitHub-Delivery so redeliveries
+-- are recognised.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    event TEXT NOT NULL,
+    action TEXT NOT NULL,
+    repository TEXT NOT NULL,
+    -- Whether the delivery triggered a refresh.
+    handled BOOLEAN NOT NULL,
+    received_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_received_at ON webhook_deliveries (received_at);
+
+-- Record execution of this migration
+INSERT
+OR IGNORE INTO migrations (migration_number, migration_name)
+VALUES
+    (002, '002-webhook-deliveries');
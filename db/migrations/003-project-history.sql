@@ -0,0 +1,24 @@
+-- Daily star and fork counts per showcase project. Each successful fetch
+-- upserts the row for the current UTC day, so the last fetch of a day wins.
+-- Projects are keyed by forge and owner as well as name, so same-named
+-- repositories keep separate series.
+CREATE TABLE IF NOT EXISTS project_history (
+    -- githubapi.Project.Forge, e.g. "github" or "gitlab".
+    forge TEXT NOT NULL,
+    owner TEXT NOT NULL,
+    project TEXT NOT NULL,
+    -- UTC date, YYYY-MM-DD.
+    day TEXT NOT NULL,
+    stars INTEGER NOT NULL,
+    forks INTEGER NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (forge, owner, project, day)
+);
+
+CREATE INDEX IF NOT EXISTS project_history_day ON project_history (day);
+
+-- Record execution of this migration
+INSERT
+OR IGNORE INTO migrations (migration_number, migration_name)
+VALUES
+    (003, '003-project-history');
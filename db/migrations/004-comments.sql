@@ -0,0 +1,26 @@
+-- Reader comments on blog posts. New comments are hidden until a
+-- moderator approves them; replies point at the comment they answer and
+-- are removed with it.
+CREATE TABLE IF NOT EXISTS comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    post_slug TEXT NOT NULL,
+    parent_id INTEGER REFERENCES comments (id) ON DELETE CASCADE,
+    author TEXT NOT NULL,
+    -- Markdown, rendered when the comment is shown.
+    body TEXT NOT NULL,
+    -- Client IP, kept for moderation only.
+    ip TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    -- NULL until the comment is approved.
+    approved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS comments_post_slug ON comments (post_slug, created_at);
+
+CREATE INDEX IF NOT EXISTS comments_parent_id ON comments (parent_id);
+
+-- Record execution of this migration
+INSERT
+OR IGNORE INTO migrations (migration_number, migration_name)
+VALUES
+    (004, '004-comments');
@@ -0,0 +1,24 @@
+-- Verified Webmentions of blog posts. A source mentions a target at most
+-- once; re-sending a mention updates it, and one whose source no longer
+-- links to the target is deleted.
+CREATE TABLE IF NOT EXISTS webmentions (
+    source TEXT NOT NULL,
+    target TEXT NOT NULL,
+    post_slug TEXT NOT NULL,
+    -- like, repost, reply or mention.
+    type TEXT NOT NULL,
+    author_name TEXT NOT NULL,
+    author_url TEXT NOT NULL,
+    -- Plain text of a reply, or the source's title.
+    content TEXT NOT NULL,
+    verified_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (source, target)
+);
+
+CREATE INDEX IF NOT EXISTS webmentions_post_slug ON webmentions (post_slug, verified_at);
+
+-- Record execution of this migration
+INSERT
+OR IGNORE INTO migrations (migration_number, migration_name)
+VALUES
+    (005, '005-webmentions');
@@ -0,0 +1,21 @@
+-- Page views recorded by the analytics middleware. Visitors are the
+-- daily-rotating hashes in visitors; no IP address or user agent is kept.
+CREATE TABLE IF NOT EXISTS page_views (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    visitor_id TEXT NOT NULL,
+    path TEXT NOT NULL,
+    -- Host of an external Referer, or '' for direct and internal visits.
+    referrer_host TEXT NOT NULL DEFAULT '',
+    utm_source TEXT NOT NULL DEFAULT '',
+    utm_medium TEXT NOT NULL DEFAULT '',
+    utm_campaign TEXT NOT NULL DEFAULT '',
+    viewed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS page_views_viewed_at ON page_views (viewed_at);
+
+-- Record execution of this migration
+INSERT
+OR IGNORE INTO migrations (migration_number, migration_name)
+VALUES
+    (006, '006-page-views');
@@ -0,0 +1,25 @@
+-- Daily aggregates of page_views. Raw views older than the retention
+-- window are rolled up here and deleted, in one transaction, so a day is
+-- counted either here or in page_views but never in both.
+CREATE TABLE IF NOT EXISTS daily_page_views (
+    day TEXT NOT NULL, -- YYYY-MM-DD, UTC
+    path TEXT NOT NULL,
+    referrer_host TEXT NOT NULL,
+    views INTEGER NOT NULL,
+    visitors INTEGER NOT NULL,
+    PRIMARY KEY (day, path, referrer_host)
+);
+
+-- Views and unique visitors per day, which can't be summed from the rows
+-- above since a visitor may view several pages.
+CREATE TABLE IF NOT EXISTS daily_visitors (
+    day TEXT PRIMARY KEY, -- YYYY-MM-DD, UTC
+    views INTEGER NOT NULL,
+    visitors INTEGER NOT NULL
+);
+
+-- Record execution of this migration
+INSERT
+OR IGNORE INTO migrations (migration_number, migration_name)
+VALUES
+    (007, '007-page-view-rollups');
@@ -0,0 +1,61 @@
+-- name: InsertComment :one
+INSERT INTO
+  comments (post_slug, parent_id, author, body, ip, created_at)
+VALUES
+  (?, ?, ?, ?, ?, ?) RETURNING id;
+
+-- name: CommentByID :one
+SELECT
+  *
+FROM
+  comments
+WHERE
+  id = ?;
+
+-- name: ApprovedComments :many
+SELECT
+  *
+FROM
+  comments
+WHERE
+  post_slug = ?
+  AND approved_at IS NOT NULL
+ORDER BY
+  created_at,
+  id;
+
+-- name: AllApprovedComments :many
+SELECT
+  *
+FROM
+  comments
+WHERE
+  approved_at IS NOT NULL
+ORDER BY
+  post_slug,
+  created_at,
+  id;
+
+-- name: PendingComments :many
+SELECT
+  *
+FROM
+  comments
+WHERE
+  approved_at IS NULL
+ORDER BY
+  created_at,
+  id;
+
+-- name: ApproveComment :execrows
+UPDATE comments
+SET
+  approved_at = ?
+WHERE
+  id = ?
+  AND approved_at IS NULL;
+
+-- name: DeleteComment :execrows
+DELETE FROM comments
+WHERE
+  id = ?;
@@ -0,0 +1,46 @@
+-- name: UpsertProjectHistory :exec
+INSERT INTO
+  project_history (forge, owner, project, day, stars, forks, recorded_at)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?) ON CONFLICT (forge, owner, project, day) DO
+UPDATE
+SET
+  stars = excluded.stars,
+  forks = excluded.forks,
+  recorded_at = excluded.recorded_at;
+
+-- name: ProjectHistorySince :many
+SELECT
+  forge,
+  owner,
+  project,
+  day,
+  stars,
+  forks
+FROM
+  project_history
+WHERE
+  forge = ?
+  AND owner = ?
+  AND project = ?
+  AND day >= ?
+ORDER BY
+  day;
+
+-- name: AllProjectHistorySince :many
+SELECT
+  forge,
+  owner,
+  project,
+  day,
+  stars,
+  forks
+FROM
+  project_history
+WHERE
+  day >= ?
+ORDER BY
+  forge,
+  owner,
+  project,
+  day;
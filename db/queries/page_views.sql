@@ -0,0 +1,215 @@
+-- name: InsertPageView :exec
+INSERT INTO
+  page_views (
+    visitor_id,
+    path,
+    referrer_host,
+    utm_source,
+    utm_medium,
+    utm_campaign,
+    viewed_at
+  )
+VALUES
+  (?, ?, ?, ?, ?, ?, ?);
+
+-- name: DailyPageViews :many
+-- Days older than the retention window come from daily_visitors, newer
+-- ones from page_views. Timestamps are stored in UTC as Go formats them,
+-- starting with the date, so substr(viewed_at, 1, 10) is the day.
+SELECT
+  day,
+  views,
+  visitors
+FROM
+  daily_visitors
+WHERE
+  day >= sqlc.arg(from_day)
+  AND day < sqlc.arg(until_day)
+UNION ALL
+SELECT
+  CAST(substr(viewed_at, 1, 10) AS TEXT) AS day,
+  COUNT(*) AS views,
+  COUNT(DISTINCT visitor_id) AS visitors
+FROM
+  page_views
+WHERE
+  viewed_at >= sqlc.arg(since)
+  AND viewed_at < sqlc.arg(until)
+GROUP BY
+  CAST(substr(viewed_at, 1, 10) AS TEXT)
+ORDER BY
+  day;
+
+-- name: DailyPageViewsByPath :many
+-- Views per day, path and referrer, from daily_page_views and page_views
+-- like DailyPageViews. A visitor counts once per day and path, towards the
+-- referrer of their first view of it, so visitors sum exactly over
+-- referrers.
+SELECT
+  day,
+  path,
+  referrer_host,
+  views,
+  visitors
+FROM
+  daily_page_views
+WHERE
+  day >= sqlc.arg(from_day)
+  AND day < sqlc.arg(until_day)
+UNION ALL
+SELECT
+  day,
+  path,
+  referrer_host,
+  COUNT(*) AS views,
+  SUM(first_view) AS visitors
+FROM
+  (
+    SELECT
+      CAST(substr(viewed_at, 1, 10) AS TEXT) AS day,
+      path,
+      referrer_host,
+      ROW_NUMBER() OVER (
+        PARTITION BY
+          substr(viewed_at, 1, 10),
+          path,
+          visitor_id
+        ORDER BY
+          id
+      ) = 1 AS first_view
+    FROM
+      page_views
+    WHERE
+      viewed_at >= sqlc.arg(since)
+      AND viewed_at < sqlc.arg(until)
+  )
+GROUP BY
+  day,
+  path,
+  referrer_host
+ORDER BY
+  day,
+  path,
+  referrer_host;
+
+-- name: TopCampaigns :many
+-- Campaigns are not rolled up, so only views inside the retention window
+-- count.
+SELECT
+  utm_source,
+  utm_medium,
+  utm_campaign,
+  COUNT(*) AS views,
+  COUNT(DISTINCT visitor_id) AS visitors
+FROM
+  page_views
+WHERE
+  viewed_at >= sqlc.arg(since)
+  AND viewed_at < sqlc.arg(until)
+  AND (
+    utm_source != ''
+    OR utm_medium != ''
+    OR utm_campaign != ''
+  )
+GROUP BY
+  utm_source,
+  utm_medium,
+  utm_campaign
+ORDER BY
+  views DESC,
+  utm_source,
+  utm_medium,
+  utm_campaign
+LIMIT
+  sqlc.arg(limit);
+
+-- name: ActiveVisitors :one
+SELECT
+  COUNT(DISTINCT visitor_id) AS visitors,
+  COUNT(*) AS views
+FROM
+  page_views
+WHERE
+  viewed_at >= ?;
+
+-- name: RecentPages :many
+SELECT
+  path,
+  COUNT(*) AS views,
+  COUNT(DISTINCT visitor_id) AS visitors
+FROM
+  page_views
+WHERE
+  viewed_at >= ?
+GROUP BY
+  path
+ORDER BY
+  views DESC,
+  path
+LIMIT
+  ?;
+
+-- name: RollUpPageViews :exec
+-- Visitors are attributed like in DailyPageViewsByPath.
+INSERT INTO
+  daily_page_views (day, path, referrer_host, views, visitors)
+SELECT
+  day,
+  path,
+  referrer_host,
+  COUNT(*),
+  SUM(first_view)
+FROM
+  (
+    SELECT
+      CAST(substr(viewed_at, 1, 10) AS TEXT) AS day,
+      path,
+      referrer_host,
+      ROW_NUMBER() OVER (
+        PARTITION BY
+          substr(viewed_at, 1, 10),
+          path,
+          visitor_id
+        ORDER BY
+          id
+      ) = 1 AS first_view
+    FROM
+      page_views
+    WHERE
+      viewed_at < ?
+  )
+WHERE
+  true
+GROUP BY
+  day,
+  path,
+  referrer_host
+ON CONFLICT (day, path, referrer_host) DO
+UPDATE
+SET
+  views = views + excluded.views,
+  visitors = visitors + excluded.visitors;
+
+-- name: RollUpVisitors :exec
+INSERT INTO
+  daily_visitors (day, views, visitors)
+SELECT
+  CAST(substr(viewed_at, 1, 10) AS TEXT),
+  COUNT(*),
+  COUNT(DISTINCT visitor_id)
+FROM
+  page_views
+WHERE
+  viewed_at < ?
+GROUP BY
+  1
+ON CONFLICT (day) DO
+UPDATE
+SET
+  views = views + excluded.views,
+  visitors = visitors + excluded.visitors;
+
+-- name: DeletePageViewsBefore :execrows
+DELETE FROM page_views
+WHERE
+  viewed_at < ?;
@@ -15,3 +15,8 @@ FROM
   visitors
 WHERE
   id = ?;
+
+-- name: DeleteVisitorsSeenBefore :execrows
+DELETE FROM visitors
+WHERE
+  last_seen < ?;
@@ -0,0 +1,5 @@
+-- name: InsertWebhookDelivery :execrows
+INSERT INTO
+  webhook_deliveries (id, event, action, repository, handled, received_at)
+VALUES
+  (?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,49 @@
+-- name: UpsertWebmention :exec
+INSERT INTO
+  webmentions (
+    source,
+    target,
+    post_slug,
+    type,
+    author_name,
+    author_url,
+    content,
+    verified_at
+  )
+VALUES
+  (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (source, target) DO
+UPDATE
+SET
+  post_slug = excluded.post_slug,
+  type = excluded.type,
+  author_name = excluded.author_name,
+  author_url = excluded.author_url,
+  content = excluded.content,
+  verified_at = excluded.verified_at;
+
+-- name: DeleteWebmention :execrows
+DELETE FROM webmentions
+WHERE
+  source = ?
+  AND target = ?;
+
+-- name: WebmentionsForPost :many
+SELECT
+  *
+FROM
+  webmentions
+WHERE
+  post_slug = ?
+ORDER BY
+  verified_at,
+  source;
+
+-- name: AllWebmentions :many
+SELECT
+  *
+FROM
+  webmentions
+ORDER BY
+  post_slug,
+  verified_at,
+  source;
@@ -0,0 +1,123 @@
+// Package analytics derives privacy-preserving page view records: visitors
+// are identified by a salted hash that changes every day, no cookies are
+// set, and bots and visitors asking not to be tracked are left out.
+package analytics
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxFieldLength caps recorded paths, hosts and campaign parameters.
+const MaxFieldLength = 200
+
+// Hasher turns a client's IP address and user agent into a visitor ID. The
+// salt is random, kept only in memory and replaced at each UTC midnight,
+// so IDs cannot be linked across days or reversed into addresses. A
+// restart also replaces it, counting returning visitors again that day.
+type Hasher struct {
+	mu   sync.Mutex
+	day  string
+	salt []byte
+}
+
+// VisitorID returns the visitor ID for ip and userAgent on now's day.
+func (h *Hasher) VisitorID(ip, userAgent string, now time.Time) string {
+	h.mu.Lock()
+	day := now.UTC().Format(time.DateOnly)
+	if day != h.day {
+		h.day = day
+		h.salt = make([]byte, 32)
+		_, _ = rand.Read(h.salt)
+	}
+	salt := h.salt
+	h.mu.Unlock()
+
+	sum := sha256.New()
+	sum.Write(salt)
+	sum.Write([]byte(day + "\x00" + ip + "\x00" + userAgent))
+	return hex.EncodeToString(sum.Sum(nil))[:32]
+}
+
+// botMarkers are user agent substrings of crawlers, link previewers,
+// monitoring and HTTP libraries, matched case-insensitively.
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "archiver", "fetcher", "scraper",
+	"facebookexternalhit", "embedly", "preview", "lighthouse", "headless",
+	"pingdom", "uptime", "monitor", "curl/", "wget/", "python-requests",
+	"python-urllib", "go-http-client", "java/", "okhttp", "libwww", "httpie",
+	"axios/", "node-fetch",
+}
+
+// IsBot reports whether userAgent looks automated. Browsers always send a
+// user agent, so an empty one counts as a bot.
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, m := range botMarkers {
+		if strings.Contains(ua, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// OptedOut reports whether the request asks not to be tracked, through Do
+// Not Track or Global Privacy Control.
+func OptedOut(r *http.Request) bool {
+	return r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1"
+}
+
+// ReferrerHost returns the host of a Referer header, or "" when there is
+// none or it is ownHost, as for navigation within the site.
+func ReferrerHost(referer, ownHost string) string {
+	u, err := url.Parse(referer)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == strings.ToLower(hostOnly(ownHost)) {
+		return ""
+	}
+	return Clip(strings.TrimPrefix(host, "www."))
+}
+
+func hostOnly(hostport string) string {
+	if u, err := url.Parse("//" + hostport); err == nil {
+		return u.Hostname()
+	}
+	return hostport
+}
+
+// Campaign holds a page view's UTM parameters.
+type Campaign struct {
+	Source   string
+	Medium   string
+	Campaign string
+}
+
+// CampaignFrom reads the utm_source, utm_medium and utm_campaign
+// parameters of q.
+func CampaignFrom(q url.Values) Campaign {
+	return Campaign{
+		Source:   Clip(strings.TrimSpace(q.Get("utm_source"))),
+		Medium:   Clip(strings.TrimSpace(q.Get("utm_medium"))),
+		Campaign: Clip(strings.TrimSpace(q.Get("utm_campaign"))),
+	}
+}
+
+// Clip shortens s to at most MaxFieldLength bytes of valid UTF-8.
+func Clip(s string) string {
+	if len(s) > MaxFieldLength {
+		s = s[:MaxFieldLength]
+	}
+	return strings.ToValidUTF8(s, "")
+}
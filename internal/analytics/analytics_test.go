@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVisitorIDRotatesDaily(t *testing.T) {
+	var h Hasher
+	day := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	id := h.VisitorID("203.0.113.7", "Firefox", day)
+	if again := h.VisitorID("203.0.113.7", "Firefox", day.Add(14*time.Hour)); again != id {
+		t.Fatalf("expected the same ID within a day, got %s and %s", id, again)
+	}
+	if other := h.VisitorID("203.0.113.8", "Firefox", day); other == id {
+		t.Fatal("expected another address to get another ID")
+	}
+	if other := h.VisitorID("203.0.113.7", "Chrome", day); other == id {
+		t.Fatal("expected another user agent to get another ID")
+	}
+	if next := h.VisitorID("203.0.113.7", "Firefox", day.Add(15*time.Hour)); next == id {
+		t.Fatal("expected the ID to change the next day")
+	}
+	var restarted Hasher
+	if fresh := restarted.VisitorID("203.0.113.7", "Firefox", day); fresh == id {
+		t.Fatal("expected a new salt to change the ID")
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	for ua, want := range map[string]bool{
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15": false,
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:140.0) Gecko/20100101 Firefox/140.0":                                   false,
+		"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)":                                            true,
+		"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)":                                          true,
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/126.0.0.0 Safari/537.36":      true,
+		"curl/8.7.1":         true,
+		"Go-http-client/2.0": true,
+		"":                   true,
+	} {
+		if got := IsBot(ua); got != want {
+			t.Errorf("IsBot(%q) = %v, want %v", ua, got, want)
+		}
+	}
+}
+
+func TestOptedOut(t *testing.T) {
+	for header, want := range map[string]bool{"": false, "DNT: 0": false, "DNT: 1": true, "Sec-GPC: 1": true} {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		if name, value, ok := strings.Cut(header, ": "); ok {
+			r.Header.Set(name, value)
+		}
+		if got := OptedOut(r); got != want {
+			t.Errorf("OptedOut with %q = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func TestReferrerHost(t *testing.T) {
+	for referer, want := range map[string]string{
+		"https://www.Google.com/search?q=x": "google.com",
+		"https://news.example:8443/item":    "news.example",
+		"https://example.com/blog":          "",
+		"http://example.com:8000/":          "",
+		"android-app://com.slack/":          "",
+		"not a url":                         "",
+		"":                                  "",
+	} {
+		if got := ReferrerHost(referer, "example.com:8000"); got != want {
+			t.Errorf("ReferrerHost(%q) = %q, want %q", referer, got, want)
+		}
+	}
+}
+
+func TestCampaignFrom(t *testing.T) {
+	q, _ := url.ParseQuery("utm_source=+newsletter&utm_medium=email&utm_campaign=" + strings.Repeat("é", MaxFieldLength))
+	c := CampaignFrom(q)
+	if c.Source != "newsletter" || c.Medium != "email" {
+		t.Fatalf("unexpected campaign %+v", c)
+	}
+	if len(c.Campaign) > MaxFieldLength || !strings.HasPrefix(c.Campaign, "éé") || strings.ContainsRune(c.Campaign, '�') {
+		t.Fatalf("expected the campaign to be clipped to valid UTF-8, got %q", c.Campaign)
+	}
+}
@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"srv.exe.dev/db/dbgen"
+)
+
+// ValidExportFormat reports whether Export writes format.
+func ValidExportFormat(format string) bool {
+	return slices.Contains([]string{"csv", "json"}, format)
+}
+
+// ExportContentType returns the media type of an export format.
+func ExportContentType(format string) string {
+	if format == "json" {
+		return "application/json"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Export writes rows to w as CSV, with a header row, or as a JSON array.
+func Export(w io.Writer, format string, rows []dbgen.DailyPageView) error {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"day", "path", "referrer_host", "views", "visitors"})
+		for _, r := range rows {
+			_ = cw.Write([]string{r.Day, csvText(r.Path), csvText(r.ReferrerHost), strconv.FormatInt(r.Views, 10), strconv.FormatInt(r.Visitors, 10)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	default:
+		return fmt.Errorf("unknown export format %q (want csv or json)", format)
+	}
+}
+
+// csvText keeps a recorded value, which visitors control, from being read
+// as a formula by spreadsheets.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,42 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"srv.exe.dev/db/dbgen"
+)
+
+func TestExport(t *testing.T) {
+	rows := []dbgen.DailyPageView{
+		{Day: "2026-05-01", Path: "/blog/hello", ReferrerHost: "news.example", Views: 3, Visitors: 2},
+		{Day: "2026-05-01", Path: "/a,b", ReferrerHost: "=cmd", Views: 1, Visitors: 1},
+	}
+
+	var b bytes.Buffer
+	if err := Export(&b, "csv", rows); err != nil {
+		t.Fatal(err)
+	}
+	want := "day,path,referrer_host,views,visitors\n" +
+		"2026-05-01,/blog/hello,news.example,3,2\n" +
+		"2026-05-01,\"/a,b\",'=cmd,1,1\n"
+	if b.String() != want {
+		t.Fatalf("csv = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := Export(&b, "json", rows); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []dbgen.DailyPageView
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, rows) {
+		t.Fatalf("json round trip = %+v, %v", decoded, err)
+	}
+
+	b.Reset()
+	if err := Export(&b, "xml", rows); err == nil || ValidExportFormat("xml") {
+		t.Fatal("expected xml to be refused")
+	}
+}
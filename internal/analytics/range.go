@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultRangeDays is the span reported when no dates are given.
+const DefaultRangeDays = 30
+
+// MaxRangeDays caps the span of a report.
+const MaxRangeDays = 731
+
+// Range is an inclusive span of UTC days.
+type Range struct {
+	From, To time.Time
+}
+
+// LastDays returns the range of n days ending with today.
+func LastDays(n int, today time.Time) Range {
+	to := day(today)
+	return Range{From: to.AddDate(0, 0, 1-n), To: to}
+}
+
+// ParseRange reads a range from YYYY-MM-DD dates. An empty to means today,
+// and an empty from means DefaultRangeDays before to.
+func ParseRange(from, to string, today time.Time) (Range, error) {
+	r := LastDays(DefaultRangeDays, today)
+	if to != "" {
+		t, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid to date %q", to)
+		}
+		r = LastDays(DefaultRangeDays, t)
+	}
+	if from != "" {
+		f, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid from date %q", from)
+		}
+		r.From = f
+	}
+	if r.From.After(r.To) {
+		return Range{}, errors.New("from date is after to date")
+	}
+	if r.Days() > MaxRangeDays {
+		return Range{}, fmt.Errorf("range is longer than %d days", MaxRangeDays)
+	}
+	return r, nil
+}
+
+// Since returns the start of the range.
+func (r Range) Since() time.Time {
+	return r.From
+}
+
+// Until returns the end of the range, midnight after its last day.
+func (r Range) Until() time.Time {
+	return r.To.AddDate(0, 0, 1)
+}
+
+// Days returns how many days the range spans.
+func (r Range) Days() int {
+	return int(r.To.Sub(r.From).Hours()/24) + 1
+}
+
+// Dates returns each day of the range as YYYY-MM-DD.
+func (r Range) Dates() []string {
+	dates := make([]string, 0, r.Days())
+	for d := r.From; d.Before(r.Until()); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format(time.DateOnly))
+	}
+	return dates
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
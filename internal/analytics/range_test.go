@@ -0,0 +1,38 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	today := time.Date(2026, 5, 10, 18, 30, 0, 0, time.UTC)
+
+	r, err := ParseRange("", "", today)
+	if err != nil || r.From.Format(time.DateOnly) != "2026-04-11" || r.To.Format(time.DateOnly) != "2026-05-10" || r.Days() != DefaultRangeDays {
+		t.Fatalf("default range = %v to %v (%d days), %v", r.From, r.To, r.Days(), err)
+	}
+	if !r.Until().Equal(time.Date(2026, 5, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Until = %v, want midnight after the last day", r.Until())
+	}
+
+	r, err = ParseRange("2026-02-27", "2026-03-01", today)
+	if err != nil || strings.Join(r.Dates(), " ") != "2026-02-27 2026-02-28 2026-03-01" {
+		t.Fatalf("dates = %v, %v", r.Dates(), err)
+	}
+	if r, err := ParseRange("", "2026-01-30", today); err != nil || r.From.Format(time.DateOnly) != "2026-01-01" {
+		t.Fatalf("expected from to default to %d days before to, got %v, %v", DefaultRangeDays, r.From, err)
+	}
+
+	for _, tc := range []struct{ from, to string }{
+		{"2026-05-02", "2026-05-01"},
+		{"yesterday", ""},
+		{"", "2026-13-01"},
+		{"2020-01-01", "2026-01-01"},
+	} {
+		if _, err := ParseRange(tc.from, tc.to, today); err == nil {
+			t.Errorf("expected ParseRange(%q, %q) to fail", tc.from, tc.to)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+)
+
+// DefaultRetentionDays is how long raw page views and visitors are kept
+// unless configured otherwise.
+const DefaultRetentionDays = 90
+
+// ParseRetentionDays reads a retention window in days, DefaultRetentionDays
+// when s is empty.
+func ParseRetentionDays(s string) (int, error) {
+	if s == "" {
+		return DefaultRetentionDays, nil
+	}
+	days, err := strconv.Atoi(s)
+	if err != nil || days < 1 {
+		return 0, fmt.Errorf("invalid retention %q: want a positive number of days", s)
+	}
+	return days, nil
+}
+
+// Cutoff returns the start of the oldest day kept when retaining days
+// days of raw page views, counting today.
+func Cutoff(days int, now time.Time) time.Time {
+	return day(now).AddDate(0, 0, 1-days)
+}
+
+// RollUpResult reports what RollUp removed.
+type RollUpResult struct {
+	PageViews int64
+	Visitors  int64
+}
+
+// RollUp adds the page views recorded before cutoff to the daily
+// aggregates and deletes them, then deletes visitors last seen before
+// cutoff. The views are moved in one transaction, so no day is counted
+// twice, and running it again with the same cutoff does nothing.
+func RollUp(ctx context.Context, db *sql.DB, cutoff time.Time) (RollUpResult, error) {
+	var res RollUpResult
+	cutoff = cutoff.UTC()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	q := dbgen.New(tx)
+	if err := q.RollUpPageViews(ctx, cutoff); err != nil {
+		return res, fmt.Errorf("roll up page views: %w", err)
+	}
+	if err := q.RollUpVisitors(ctx, cutoff); err != nil {
+		return res, fmt.Errorf("roll up visitors: %w", err)
+	}
+	if res.PageViews, err = q.DeletePageViewsBefore(ctx, cutoff); err != nil {
+		return res, fmt.Errorf("delete page views: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return res, err
+	}
+
+	if res.Visitors, err = dbgen.New(db).DeleteVisitorsSeenBefore(ctx, cutoff); err != nil {
+		return res, fmt.Errorf("delete visitors: %w", err)
+	}
+	return res, nil
+}
+
+// Daily returns the views and unique visitors of each day in r that has
+// any, whether rolled up or not, in date order.
+func Daily(ctx context.Context, q *dbgen.Queries, r Range) ([]dbgen.DailyVisitor, error) {
+	return q.DailyPageViews(ctx, dbgen.DailyPageViewsParams{
+		FromDay:  r.From.Format(time.DateOnly),
+		UntilDay: r.Until().Format(time.DateOnly),
+		Since:    r.Since(),
+		Until:    r.Until(),
+	})
+}
+
+// PageViews returns the views in r per day, path and referrer, whether
+// rolled up or not, ordered by day, path and referrer.
+func PageViews(ctx context.Context, q *dbgen.Queries, r Range) ([]dbgen.DailyPageView, error) {
+	return q.DailyPageViewsByPath(ctx, dbgen.DailyPageViewsByPathParams{
+		FromDay:  r.From.Format(time.DateOnly),
+		UntilDay: r.Until().Format(time.DateOnly),
+		Since:    r.Since(),
+		Until:    r.Until(),
+	})
+}
+
+// Total is the views and visitors of one path or referrer.
+type Total struct {
+	Key      string
+	Views    int
+	Visitors int
+}
+
+// Top sums rows by key, skipping empty keys, and returns the n with the
+// most views. Rows count each visitor once per day and path, so visitors
+// are exact per path but may count twice per referrer.
+func Top(rows []dbgen.DailyPageView, key func(dbgen.DailyPageView) string, n int) []Total {
+	index := make(map[string]int)
+	var totals []Total
+	for _, row := range rows {
+		k := key(row)
+		if k == "" {
+			continue
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(totals)
+			index[k] = i
+			totals = append(totals, Total{Key: k})
+		}
+		totals[i].Views += int(row.Views)
+		totals[i].Visitors += int(row.Visitors)
+	}
+	slices.SortFunc(totals, func(a, b Total) int {
+		return cmp.Or(cmp.Compare(b.Views, a.Views), cmp.Compare(a.Key, b.Key))
+	})
+	return totals[:min(n, len(totals))]
+}
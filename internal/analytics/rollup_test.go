@@ -0,0 +1,118 @@
+package analytics
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"srv.exe.dev/db"
+	"srv.exe.dev/db/dbgen"
+)
+
+func TestRollUpKeepsTotals(t *testing.T) {
+	wdb, err := db.Open(filepath.Join(t.TempDir(), "test.sqlite3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wdb.Close()
+	if err := db.RunMigrations(wdb); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	q := dbgen.New(wdb)
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	at := func(daysAgo int, hour int) time.Time {
+		return time.Date(2026, 5, 10-daysAgo, hour, 0, 0, 0, time.UTC)
+	}
+	for _, v := range []dbgen.InsertPageViewParams{
+		// Visitor a lands on /blog/hello from a search and reloads it.
+		{VisitorID: "a", Path: "/blog/hello", ReferrerHost: "search.example", ViewedAt: at(5, 9)},
+		{VisitorID: "a", Path: "/blog/hello", ViewedAt: at(5, 10)},
+		{VisitorID: "a", Path: "/projects", ViewedAt: at(5, 11)},
+		{VisitorID: "b", Path: "/blog/hello", ViewedAt: at(5, 23)},
+		{VisitorID: "c", Path: "/", ReferrerHost: "news.example", ViewedAt: at(4, 8)},
+		{VisitorID: "d", Path: "/", ViewedAt: at(1, 8)},
+	} {
+		if err := q.InsertPageView(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []dbgen.UpsertVisitorParams{
+		{ID: "a", CreatedAt: at(5, 9), LastSeen: at(5, 11)},
+		{ID: "d", CreatedAt: at(1, 8), LastSeen: at(1, 8)},
+	} {
+		if err := q.UpsertVisitor(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rng := LastDays(7, now)
+	load := func() ([]dbgen.DailyVisitor, []dbgen.DailyPageView) {
+		t.Helper()
+		daily, err := Daily(ctx, q, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		views, err := PageViews(ctx, q, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return daily, views
+	}
+	daily, views := load()
+	wantDaily := []dbgen.DailyVisitor{
+		{Day: "2026-05-05", Views: 4, Visitors: 2},
+		{Day: "2026-05-06", Views: 1, Visitors: 1},
+		{Day: "2026-05-09", Views: 1, Visitors: 1},
+	}
+	if !reflect.DeepEqual(daily, wantDaily) {
+		t.Fatalf("daily = %+v, want %+v", daily, wantDaily)
+	}
+	pages := Top(views, func(v dbgen.DailyPageView) string { return v.Path }, 2)
+	if want := []Total{{"/blog/hello", 3, 2}, {"/", 2, 2}}; !reflect.DeepEqual(pages, want) {
+		t.Fatalf("top pages = %+v, want %+v", pages, want)
+	}
+
+	cutoff := Cutoff(3, now)
+	if cutoff.Format(time.DateOnly) != "2026-05-08" {
+		t.Fatalf("Cutoff = %v", cutoff)
+	}
+	res, err := RollUp(ctx, wdb, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.PageViews != 5 || res.Visitors != 1 {
+		t.Fatalf("RollUp = %+v, want 5 views and 1 visitor removed", res)
+	}
+	if again, err := RollUp(ctx, wdb, cutoff); err != nil || again != (RollUpResult{}) {
+		t.Fatalf("expected a second run to do nothing, got %+v, %v", again, err)
+	}
+
+	rolledDaily, rolledViews := load()
+	if !reflect.DeepEqual(rolledDaily, daily) || !reflect.DeepEqual(rolledViews, views) {
+		t.Fatalf("expected rolling up to keep the totals:\n%+v\n%+v\nwant\n%+v\n%+v", rolledDaily, rolledViews, daily, views)
+	}
+	if _, err := q.VisitorWithID(ctx, "a"); err == nil {
+		t.Fatal("expected the stale visitor to be deleted")
+	}
+	if _, err := q.VisitorWithID(ctx, "d"); err != nil {
+		t.Fatalf("expected the recent visitor to be kept: %v", err)
+	}
+}
+
+func TestParseRetentionDays(t *testing.T) {
+	if days, err := ParseRetentionDays(""); err != nil || days != DefaultRetentionDays {
+		t.Fatalf("ParseRetentionDays(\"\") = %d, %v", days, err)
+	}
+	if days, err := ParseRetentionDays("30"); err != nil || days != 30 {
+		t.Fatalf("ParseRetentionDays(\"30\") = %d, %v", days, err)
+	}
+	for _, s := range []string{"0", "-1", "90d"} {
+		if _, err := ParseRetentionDays(s); err == nil {
+			t.Errorf("expected ParseRetentionDays(%q) to fail", s)
+		}
+	}
+}
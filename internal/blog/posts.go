@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	mdhtml "github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 	"gopkg.in/yaml.v3"
@@ -126,8 +127,51 @@ func ParsePost(data []byte) (*Post, error) {
 }
 
 func RenderMarkdown(data []byte) template.HTML {
+	return RenderMarkdownWith(data, nil)
+}
+
+// RenderUserMarkdown renders Markdown written by visitors, such as blog
+// comments. Raw HTML and unsafe links are dropped as for posts, and every
+// link is marked rel="nofollow ugc noopener", so visitors cannot use the
+// site to pass ranking to their own pages.
+func RenderUserMarkdown(data []byte) template.HTML {
+	doc := parser.NewWithExtensions(parser.CommonExtensions).Parse(data)
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if link, ok := node.(*ast.Link); ok && entering {
+			link.AdditionalAttributes = append(link.AdditionalAttributes, `rel="nofollow ugc noopener"`)
+		}
+		return ast.GoToNext
+	})
+	renderer := mdhtml.NewRenderer(mdhtml.RendererOptions{
+		Flags: mdhtml.CommonFlags | mdhtml.SkipHTML | mdhtml.Safelink | mdhtml.HrefTargetBlank,
+	})
+	// #nosec G203 -- raw HTML is skipped and unsafe links are stripped before marking the rendered output trusted.
+	return template.HTML(markdown.Render(doc, renderer))
+}
+
+// RenderMarkdownWith renders Markdown like RenderMarkdown, passing every
+// link and image destination through rewrite first. It is used to render
+// content written for another site, such as a GitHub README, whose
+// relative links would otherwise break. A nil rewrite leaves links as-is.
+func RenderMarkdownWith(data []byte, rewrite func(dest string, image bool) string) template.HTML {
 	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
 	p := parser.NewWithExtensions(extensions)
+	doc := p.Parse(data)
+
+	if rewrite != nil {
+		ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+			if !entering {
+				return ast.GoToNext
+			}
+			switch n := node.(type) {
+			case *ast.Link:
+				n.Destination = []byte(rewrite(string(n.Destination), false))
+			case *ast.Image:
+				n.Destination = []byte(rewrite(string(n.Destination), true))
+			}
+			return ast.GoToNext
+		})
+	}
 
 	htmlFlags := mdhtml.CommonFlags |
 		mdhtml.SkipHTML |
@@ -136,7 +180,7 @@ func RenderMarkdown(data []byte) template.HTML {
 		mdhtml.NoopenerLinks |
 		mdhtml.HrefTargetBlank
 	renderer := mdhtml.NewRenderer(mdhtml.RendererOptions{Flags: htmlFlags})
-	rendered := markdown.ToHTML(data, p, renderer)
+	rendered := markdown.Render(doc, renderer)
 
 	// #nosec G203 -- raw HTML is skipped and unsafe links are stripped before marking the rendered output trusted.
 	return template.HTML(rendered)
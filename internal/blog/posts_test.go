@@ -40,6 +40,47 @@ func TestRenderMarkdownSkipsRawHTMLAndHardensExternalLinks(t *testing.T) {
 	}
 }
 
+func TestRenderUserMarkdownMarksLinksAsUserContent(t *testing.T) {
+	rendered := string(RenderUserMarkdown([]byte(`<b>raw</b> [site](https://example.com), https://example.org and [bad](javascript:alert(1))`)))
+
+	for _, blocked := range []string{"<b>", `href="javascript:alert(1)"`} {
+		if strings.Contains(rendered, blocked) {
+			t.Fatalf("expected user markdown to skip %q, got %q", blocked, rendered)
+		}
+	}
+	if n := strings.Count(rendered, `rel="nofollow ugc noopener"`); n != 2 || strings.Count(rendered, "rel=") != 2 {
+		t.Fatalf("expected both links to carry one nofollow ugc rel, got %q", rendered)
+	}
+}
+
+func TestRenderMarkdownWithRewritesLinksAndImages(t *testing.T) {
+	rewrite := func(dest string, image bool) string {
+		switch {
+		case strings.Contains(dest, ":"):
+			return dest
+		case image:
+			return "https://img.example.com/" + dest
+		default:
+			return "https://docs.example.com/" + dest
+		}
+	}
+	rendered := string(RenderMarkdownWith([]byte(`
+[guide](docs/guide.md)
+![logo](assets/logo.png)
+[bad](javascript:alert(1))
+`), rewrite))
+
+	if !strings.Contains(rendered, `href="https://docs.example.com/docs/guide.md"`) {
+		t.Fatalf("expected link destination to be rewritten: %q", rendered)
+	}
+	if !strings.Contains(rendered, `src="https://img.example.com/assets/logo.png"`) {
+		t.Fatalf("expected image destination to be rewritten: %q", rendered)
+	}
+	if strings.Contains(rendered, "javascript:") {
+		t.Fatalf("expected unsafe links to stay stripped after rewriting: %q", rendered)
+	}
+}
+
 func TestLoadPostsFiltersDraftsAndSortsPublishedPosts(t *testing.T) {
 	postsDir := t.TempDir()
 
@@ -0,0 +1,96 @@
+// Package charts renders small, dependency-free SVG charts for embedding
+// directly in pages, so they need neither JavaScript nor extra requests.
+package charts
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// HeatmapCell is one day of a calendar heatmap.
+type HeatmapCell struct {
+	Date  time.Time
+	Count int
+	// Level picks the shade, from 0 (none) to 4 (most).
+	Level int
+}
+
+// Heatmap geometry, in SVG user units.
+const (
+	cellSize    = 10
+	cellStep    = 13
+	heatmapLeft = 28
+	heatmapTop  = 16
+)
+
+// heatmapStyle shades cells like GitHub's contribution graph, in both the
+// light and dark themes.
+const heatmapStyle = `<style>
+.heatmap text { font-size: 9px; fill: currentColor; opacity: 0.6; }
+.heatmap .l0 { fill: #ebedf0; } .heatmap .l1 { fill: #9be9a8; } .heatmap .l2 { fill: #40c463; }
+.heatmap .l3 { fill: #30a14e; } .heatmap .l4 { fill: #216e39; }
+.dark .heatmap .l0 { fill: #161b22; } .dark .heatmap .l1 { fill: #0e4429; } .dark .heatmap .l2 { fill: #006d32; }
+.dark .heatmap .l3 { fill: #26a641; } .dark .heatmap .l4 { fill: #39d353; }
+</style>`
+
+// Heatmap renders cells as a week-per-column calendar, Sunday at the top,
+// with month and weekday labels. Each cell has a tooltip such as
+// "3 contributions on Jan 2, 2026", using unit for the noun. Cells must
+// be in date order.
+func Heatmap(cells []HeatmapCell, unit string) template.HTML {
+	if len(cells) == 0 {
+		return ""
+	}
+	first := cells[0].Date
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	weeks := int(cells[len(cells)-1].Date.Sub(start).Hours()/24)/7 + 1
+	width := heatmapLeft + weeks*cellStep
+	height := heatmapTop + 7*cellStep
+
+	total := 0
+	for _, c := range cells {
+		total += c.Count
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="heatmap" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="100%%" role="img" aria-label="%s">`,
+		width, height, html.EscapeString(fmt.Sprintf("%s in the last year", plural(total, unit))))
+	b.WriteString(heatmapStyle)
+
+	for row, label := range []string{1: "Mon", 3: "Wed", 5: "Fri"} {
+		if label != "" {
+			fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, heatmapTop+row*cellStep+cellSize-1, label)
+		}
+	}
+
+	lastMonth, lastLabelCol := -1, -2
+	for _, c := range cells {
+		col := int(c.Date.Sub(start).Hours()/24) / 7
+		row := int(c.Date.Weekday())
+		// Label a month over its first full week, leaving room for the text.
+		if m := int(c.Date.Month()); m != lastMonth && row == 0 {
+			if col-lastLabelCol >= 3 {
+				fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`, heatmapLeft+col*cellStep, heatmapTop-5, c.Date.Format("Jan"))
+				lastLabelCol = col
+			}
+			lastMonth = m
+		}
+		level := min(max(c.Level, 0), 4)
+		fmt.Fprintf(&b, `<rect class="l%d" x="%d" y="%d" width="%d" height="%d" rx="2"><title>%s on %s</title></rect>`,
+			level, heatmapLeft+col*cellStep, heatmapTop+row*cellStep, cellSize, cellSize,
+			html.EscapeString(plural(c.Count, unit)), c.Date.Format("Jan 2, 2006"))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()) // #nosec G203 -- built from numbers, dates and escaped text.
+}
+
+// plural formats n with unit, e.g. "1 contribution" or "3 contributions".
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,37 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeatmap(t *testing.T) {
+	// Wednesday Jan 28 to Tuesday Feb 10, 2026: three week columns.
+	start := time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC)
+	var cells []HeatmapCell
+	for i := range 14 {
+		cells = append(cells, HeatmapCell{Date: start.AddDate(0, 0, i), Count: i % 3, Level: i % 3})
+	}
+
+	svg := string(Heatmap(cells, "contribution"))
+	for _, want := range []string{
+		`viewBox="0 0 67 107"`,
+		`aria-label="13 contributions in the last year"`,
+		// Wednesday of the first week, Sunday Feb 1 starting the second.
+		`<rect class="l0" x="28" y="55" width="10" height="10" rx="2"><title>0 contributions on Jan 28, 2026</title></rect>`,
+		`<rect class="l1" x="41" y="16" width="10" height="10" rx="2"><title>1 contribution on Feb 1, 2026</title></rect>`,
+		`<text x="41" y="11">Feb</text>`,
+		`>Mon</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("expected heatmap to contain %q, got %s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "<script") {
+		t.Fatalf("heatmap must not need JavaScript")
+	}
+	if Heatmap(nil, "contribution") != "" {
+		t.Fatalf("expected no markup for an empty calendar")
+	}
+}
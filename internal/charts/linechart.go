@@ -0,0 +1,125 @@
+package charts
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// Series is one line of a line chart.
+type Series struct {
+	// Unit is the singular noun for the values, e.g. "view".
+	Unit   string
+	Values []int
+}
+
+// Line chart geometry, in SVG user units.
+const (
+	lineChartWidth  = 640
+	lineChartHeight = 200
+	lineChartLeft   = 40
+	lineChartTop    = 8
+	lineChartBottom = 20
+)
+
+// lineChartStyle draws the first series solid and the second dashed, both
+// in the current text colour, and highlights the hovered day.
+const lineChartStyle = `<style>
+.linechart text { font-size: 10px; fill: currentColor; opacity: 0.6; }
+.linechart .grid { stroke: currentColor; opacity: 0.15; }
+.linechart .s0 { stroke: currentColor; fill: none; stroke-width: 2; }
+.linechart .s1 { stroke: currentColor; fill: none; stroke-width: 1.5; stroke-dasharray: 4 3; opacity: 0.6; }
+.linechart circle { fill: currentColor; }
+.linechart .day { fill: transparent; }
+.linechart .day:hover { fill: currentColor; opacity: 0.08; }
+</style>`
+
+// LineChart renders series over shared x-axis labels, such as dates, with
+// a y-axis starting at zero, the first, middle and last labels under the
+// axis, and a tooltip per label listing each series' value. Every series
+// must have a value per label. label is the accessible description.
+func LineChart(labels []string, series []Series, label string) template.HTML {
+	if len(labels) == 0 || len(series) == 0 {
+		return ""
+	}
+	top := 1
+	for _, s := range series {
+		for _, v := range s.Values {
+			top = max(top, v)
+		}
+	}
+	top = niceCeiling(top)
+
+	plotWidth := float64(lineChartWidth - lineChartLeft)
+	plotHeight := float64(lineChartHeight - lineChartTop - lineChartBottom)
+	step := plotWidth / float64(len(labels))
+	x := func(i int) float64 { return lineChartLeft + step*(float64(i)+0.5) }
+	y := func(v int) float64 { return lineChartTop + plotHeight*float64(top-v)/float64(top) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="linechart" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="100%%" role="img" aria-label="%s">`,
+		lineChartWidth, lineChartHeight, html.EscapeString(label))
+	b.WriteString(lineChartStyle)
+
+	for _, v := range uniqueInts(0, top/2, top) {
+		fmt.Fprintf(&b, `<line class="grid" x1="%d" y1="%.1f" x2="%d" y2="%.1f"/>`, lineChartLeft, y(v), lineChartWidth, y(v))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%d</text>`, lineChartLeft-6, y(v)+3, v)
+	}
+	for _, i := range uniqueInts(0, len(labels)/2, len(labels)-1) {
+		anchor := "middle"
+		switch {
+		case len(labels) > 1 && i == 0:
+			anchor = "start"
+		case len(labels) > 1 && i == len(labels)-1:
+			anchor = "end"
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="%s">%s</text>`,
+			x(i), lineChartHeight-6, anchor, html.EscapeString(labels[i]))
+	}
+
+	for n, s := range series {
+		points := make([]string, len(s.Values))
+		for i, v := range s.Values {
+			points[i] = fmt.Sprintf("%.1f,%.1f", x(i), y(v))
+		}
+		if len(points) == 1 {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3"/>`, x(0), y(s.Values[0]))
+			continue
+		}
+		fmt.Fprintf(&b, `<polyline class="s%d" points="%s" stroke-linejoin="round"/>`, n%2, strings.Join(points, " "))
+	}
+
+	for i, l := range labels {
+		parts := make([]string, len(series))
+		for n, s := range series {
+			parts[n] = plural(s.Values[i], s.Unit)
+		}
+		fmt.Fprintf(&b, `<rect class="day" x="%.1f" y="%d" width="%.1f" height="%.1f"><title>%s: %s</title></rect>`,
+			x(i)-step/2, lineChartTop, step, plotHeight, html.EscapeString(l), html.EscapeString(strings.Join(parts, ", ")))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()) // #nosec G203 -- built from numbers and escaped text.
+}
+
+// niceCeiling rounds n up to 1, 2 or 5 times a power of ten, so the axis
+// labels are round numbers.
+func niceCeiling(n int) int {
+	for scale := 1; ; scale *= 10 {
+		for _, m := range []int{1, 2, 5} {
+			if n <= m*scale {
+				return m * scale
+			}
+		}
+	}
+}
+
+func uniqueInts(values ...int) []int {
+	var out []int
+	for _, v := range values {
+		if len(out) == 0 || out[len(out)-1] != v {
+			out = append(out, v)
+		}
+	}
+	return out
+}
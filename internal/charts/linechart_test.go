@@ -0,0 +1,41 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineChart(t *testing.T) {
+	svg := string(LineChart(
+		[]string{"May 1", "May 2", "May <3>", "May 4"},
+		[]Series{{Unit: "view", Values: []int{3, 7, 0, 4}}, {Unit: "visitor", Values: []int{1, 2, 0, 2}}},
+		"Views & visitors",
+	))
+	for _, want := range []string{
+		`viewBox="0 0 640 200"`,
+		`aria-label="Views &amp; visitors"`,
+		// The axis tops out at the next round number above 7.
+		`<text x="34" y="11.0" text-anchor="end">10</text>`,
+		`<text x="34" y="97.0" text-anchor="end">5</text>`,
+		`<polyline class="s0" points="115.0,128.4 265.0,59.6 415.0,180.0 565.0,111.2"`,
+		`<polyline class="s1" points=`,
+		`<text x="115.0" y="194" text-anchor="start">May 1</text>`,
+		`<text x="415.0" y="194" text-anchor="middle">May &lt;3&gt;</text>`,
+		`<title>May 1: 3 views, 1 visitor</title>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("expected line chart to contain %q, got %s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "<script") {
+		t.Fatalf("line chart must not need JavaScript")
+	}
+
+	single := string(LineChart([]string{"May 1"}, []Series{{Unit: "view", Values: []int{0}}}, ""))
+	if !strings.Contains(single, `<circle cx="340.0" cy="180.0" r="3"/>`) || strings.Contains(single, "<polyline") {
+		t.Fatalf("expected a single day to be drawn as a point, got %s", single)
+	}
+	if LineChart(nil, nil, "") != "" {
+		t.Fatalf("expected no markup without data")
+	}
+}
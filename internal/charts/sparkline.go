@@ -0,0 +1,48 @@
+package charts
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// Sparkline geometry, in SVG user units.
+const (
+	sparklineWidth  = 80
+	sparklineHeight = 16
+	// sparklinePad keeps the stroke inside the viewBox at the extremes.
+	sparklinePad = 1
+)
+
+// Sparkline renders values as a small line chart with no axes, sized to sit
+// inline with text and drawn in the current text colour. label is the
+// accessible description. Fewer than two values render nothing.
+func Sparkline(values []int, label string) template.HTML {
+	if len(values) < 2 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := float64(i) * sparklineWidth / float64(len(values)-1)
+		// A flat series is drawn through the middle.
+		y := float64(sparklineHeight) / 2
+		if hi > lo {
+			y = sparklinePad + float64(hi-v)*(sparklineHeight-2*sparklinePad)/float64(hi-lo)
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="sparkline" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="%s">`,
+		sparklineWidth, sparklineHeight, sparklineWidth, sparklineHeight, html.EscapeString(label))
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="currentColor" stroke-width="1.5" stroke-linejoin="round" stroke-linecap="round"/>`,
+		strings.Join(points, " "))
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String()) // #nosec G203 -- built from numbers and escaped text.
+}
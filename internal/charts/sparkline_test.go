@@ -0,0 +1,25 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	svg := string(Sparkline([]int{1, 3, 2}, "Stars <90 days>"))
+	for _, want := range []string{
+		`viewBox="0 0 80 16"`,
+		`aria-label="Stars &lt;90 days&gt;"`,
+		`points="0.0,15.0 40.0,1.0 80.0,8.0"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Fatalf("expected sparkline to contain %q, got %s", want, svg)
+		}
+	}
+	if flat := string(Sparkline([]int{5, 5}, "")); !strings.Contains(flat, `points="0.0,8.0 80.0,8.0"`) {
+		t.Fatalf("expected a flat series through the middle, got %s", flat)
+	}
+	if Sparkline([]int{4}, "") != "" {
+		t.Fatalf("expected no markup for a single value")
+	}
+}
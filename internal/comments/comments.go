@@ -0,0 +1,192 @@
+// Package comments threads and renders reader comments on blog posts, and
+// guards the form that submits them against the simplest spam bots.
+package comments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/blog"
+)
+
+// Limits on a submitted comment, in characters.
+const (
+	MaxAuthorLength = 80
+	MaxBodyLength   = 5000
+)
+
+// A form must be submitted at least MinSubmitDelay after it was rendered,
+// which people filling it in never notice but bots posting straight away
+// do, and at most MaxFormAge after.
+const (
+	MinSubmitDelay = 3 * time.Second
+	MaxFormAge     = 24 * time.Hour
+)
+
+var (
+	// ErrTooFast is returned by CheckToken for a form submitted within
+	// MinSubmitDelay.
+	ErrTooFast = errors.New("comment form submitted too quickly")
+	// ErrFormExpired is returned by CheckToken for a form older than
+	// MaxFormAge, or one signed with another key, as after a restart.
+	ErrFormExpired = errors.New("comment form expired")
+)
+
+// Comment is an approved comment ready to show.
+type Comment struct {
+	ID       int64
+	PostSlug string
+	// ParentID is the comment this one replies to, or 0.
+	ParentID  int64
+	Author    string
+	Body      template.HTML
+	CreatedAt time.Time
+	Replies   []*Comment
+	// ReplyURL links to the form for replying to this comment. It is
+	// empty where comments are closed, as in the static build.
+	ReplyURL string
+}
+
+// DisplayDate formats the comment's date like the blog's post dates.
+func (c *Comment) DisplayDate() string {
+	return c.CreatedAt.UTC().Format(time.DateOnly)
+}
+
+// FromRow renders a stored comment's Markdown as visitor content, so raw
+// HTML and unsafe links never reach the page and links are not followed.
+func FromRow(row dbgen.Comment) *Comment {
+	c := &Comment{
+		ID:        row.ID,
+		PostSlug:  row.PostSlug,
+		Author:    row.Author,
+		Body:      blog.RenderUserMarkdown([]byte(row.Body)),
+		CreatedAt: row.CreatedAt,
+	}
+	if row.ParentID != nil {
+		c.ParentID = *row.ParentID
+	}
+	return c
+}
+
+// Thread nests replies under the comments they answer, keeping the order of
+// rows within each level. A reply whose parent is not in rows is shown at
+// the top level rather than dropped.
+func Thread(rows []dbgen.Comment) []*Comment {
+	byID := make(map[int64]*Comment, len(rows))
+	all := make([]*Comment, 0, len(rows))
+	for _, row := range rows {
+		c := FromRow(row)
+		byID[c.ID] = c
+		all = append(all, c)
+	}
+	var top []*Comment
+	for _, c := range all {
+		if parent, ok := byID[c.ParentID]; ok && c.ParentID != c.ID {
+			parent.Replies = append(parent.Replies, c)
+			continue
+		}
+		top = append(top, c)
+	}
+	return top
+}
+
+// ThreadByPost threads rows from several posts, keyed by post slug.
+func ThreadByPost(rows []dbgen.Comment) map[string][]*Comment {
+	byPost := make(map[string][]dbgen.Comment)
+	for _, row := range rows {
+		byPost[row.PostSlug] = append(byPost[row.PostSlug], row)
+	}
+	out := make(map[string][]*Comment, len(byPost))
+	for slug, rows := range byPost {
+		out[slug] = Thread(rows)
+	}
+	return out
+}
+
+// Walk calls fn for each comment in threads, parents before replies.
+func Walk(threads []*Comment, fn func(*Comment)) {
+	for _, c := range threads {
+		fn(c)
+		Walk(c.Replies, fn)
+	}
+}
+
+// Count returns the number of comments in threads, replies included.
+func Count(threads []*Comment) int {
+	n := 0
+	Walk(threads, func(*Comment) { n++ })
+	return n
+}
+
+// Find returns the comment with the given ID, or nil.
+func Find(threads []*Comment, id int64) *Comment {
+	var found *Comment
+	Walk(threads, func(c *Comment) {
+		if c.ID == id && found == nil {
+			found = c
+		}
+	})
+	return found
+}
+
+// Validate trims a submitted author and body and checks their lengths.
+func Validate(author, body string) (string, string, error) {
+	author = strings.TrimSpace(author)
+	body = strings.TrimSpace(body)
+	switch {
+	case author == "":
+		return "", "", errors.New("a name is required")
+	case utf8.RuneCountInString(author) > MaxAuthorLength:
+		return "", "", fmt.Errorf("the name is longer than %d characters", MaxAuthorLength)
+	case body == "":
+		return "", "", errors.New("the comment is empty")
+	case utf8.RuneCountInString(body) > MaxBodyLength:
+		return "", "", fmt.Errorf("the comment is longer than %d characters", MaxBodyLength)
+	}
+	return author, body, nil
+}
+
+// NewToken returns the value of the form's hidden timestamp field: when the
+// form was rendered for the post, signed with key so it cannot be
+// backdated.
+func NewToken(key []byte, slug string, now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	return ts + "." + tokenMAC(key, slug, ts)
+}
+
+// CheckToken verifies a token from NewToken for the post and that the form
+// was submitted between MinSubmitDelay and MaxFormAge after it was
+// rendered.
+func CheckToken(key []byte, slug, token string, now time.Time) error {
+	ts, mac, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(mac), []byte(tokenMAC(key, slug, ts))) {
+		return ErrFormExpired
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrFormExpired
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	switch {
+	case age < MinSubmitDelay:
+		return ErrTooFast
+	case age > MaxFormAge:
+		return ErrFormExpired
+	}
+	return nil
+}
+
+func tokenMAC(key []byte, slug, ts string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(slug + "\x00" + ts))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,95 @@
+package comments
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+)
+
+func TestThreadNestsRepliesAndRendersSafely(t *testing.T) {
+	parent := func(id int64) *int64 { return &id }
+	rows := []dbgen.Comment{
+		{ID: 1, PostSlug: "hello", Author: "ada", Body: "First! <script>alert(1)</script> [x](javascript:alert(1))"},
+		{ID: 2, PostSlug: "hello", Author: "bob", Body: "Second, see [my site](https://bob.example) or https://bob.example/also"},
+		{ID: 3, PostSlug: "hello", ParentID: parent(1), Author: "cy", Body: "Reply to *ada*"},
+		{ID: 4, PostSlug: "hello", ParentID: parent(3), Author: "ada", Body: "Reply to cy"},
+		{ID: 5, PostSlug: "hello", ParentID: parent(99), Author: "dee", Body: "Orphan"},
+	}
+	threads := Thread(rows)
+	if len(threads) != 3 || threads[0].ID != 1 || threads[1].ID != 2 || threads[2].ID != 5 {
+		t.Fatalf("unexpected top level %+v", threads)
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != 3 || threads[0].Replies[0].Replies[0].ID != 4 {
+		t.Fatalf("expected 1 > 3 > 4, got %+v", threads[0].Replies)
+	}
+	if n := Count(threads); n != 5 {
+		t.Fatalf("Count = %d, want 5", n)
+	}
+	if c := Find(threads, 4); c == nil || c.Author != "ada" {
+		t.Fatalf("Find(4) = %+v", c)
+	}
+	if Find(threads, 42) != nil {
+		t.Fatal("expected Find to miss an unknown ID")
+	}
+
+	body := string(threads[0].Body)
+	if strings.Contains(body, "<script>") || strings.Contains(body, "javascript:") {
+		t.Fatalf("expected raw HTML and unsafe links to be dropped, got %q", body)
+	}
+	if got := string(threads[1].Body); strings.Count(got, `rel="nofollow ugc noopener"`) != 2 {
+		t.Fatalf("expected every link to be marked as user content, got %q", got)
+	}
+	if got := string(threads[0].Replies[0].Body); !strings.Contains(got, "<em>ada</em>") {
+		t.Fatalf("expected Markdown to be rendered, got %q", got)
+	}
+
+	byPost := ThreadByPost(append(rows, dbgen.Comment{ID: 6, PostSlug: "other", Author: "eve", Body: "Hi"}))
+	if len(byPost["hello"]) != 3 || len(byPost["other"]) != 1 {
+		t.Fatalf("unexpected threads by post %+v", byPost)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if author, body, err := Validate("  ada ", "\nhello\n"); err != nil || author != "ada" || body != "hello" {
+		t.Fatalf("Validate = %q, %q, %v", author, body, err)
+	}
+	for _, tc := range []struct{ author, body string }{
+		{"", "hello"},
+		{"ada", "   "},
+		{strings.Repeat("a", MaxAuthorLength+1), "hello"},
+		{"ada", strings.Repeat("é", MaxBodyLength+1)},
+	} {
+		if _, _, err := Validate(tc.author, tc.body); err == nil {
+			t.Errorf("expected Validate(%.10q, %.10q) to fail", tc.author, tc.body)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	key := []byte("key")
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	token := NewToken(key, "hello", now)
+
+	if err := CheckToken(key, "hello", token, now.Add(MinSubmitDelay)); err != nil {
+		t.Fatalf("expected a timely submission to pass, got %v", err)
+	}
+	if err := CheckToken(key, "hello", token, now.Add(time.Second)); !errors.Is(err, ErrTooFast) {
+		t.Fatalf("expected ErrTooFast, got %v", err)
+	}
+	if err := CheckToken(key, "hello", token, now.Add(MaxFormAge+time.Second)); !errors.Is(err, ErrFormExpired) {
+		t.Fatalf("expected an old form to expire, got %v", err)
+	}
+	for name, check := range map[string]error{
+		"other post": CheckToken(key, "other", token, now.Add(time.Minute)),
+		"other key":  CheckToken([]byte("rotated"), "hello", token, now.Add(time.Minute)),
+		"backdated":  CheckToken(key, "hello", "1."+strings.SplitN(token, ".", 2)[1], now.Add(time.Minute)),
+		"missing":    CheckToken(key, "hello", "", now),
+	} {
+		if !errors.Is(check, ErrFormExpired) {
+			t.Errorf("%s: expected ErrFormExpired, got %v", name, check)
+		}
+	}
+}
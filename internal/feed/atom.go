@@ -0,0 +1,112 @@
+// Package feed builds the site's Atom feeds, which both the live server
+// and the static site generator serve.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"time"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// Atom is an Atom 1.0 feed document (RFC 4287).
+type Atom struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Links   []Link   `xml:"link"`
+	Author  *Person  `xml:"author,omitempty"`
+	Entries []Entry  `xml:"entry"`
+}
+
+// Link is an atom:link element.
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// Person is an atom:author element.
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// Text is an Atom text construct.
+type Text struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+// Entry is an atom:entry element.
+type Entry struct {
+	Title    string    `xml:"title"`
+	ID       string    `xml:"id"`
+	Updated  string    `xml:"updated"`
+	Links    []Link    `xml:"link"`
+	Author   *Person   `xml:"author,omitempty"`
+	Category *Category `xml:"category,omitempty"`
+	Content  *Text     `xml:"content,omitempty"`
+}
+
+// Category is an atom:category element.
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+// Encode renders the feed with an XML declaration.
+func (a *Atom) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(a); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// ProjectActivity builds the feed served at /projects/activity.xml from
+// items, which should be newest first. siteURL is the absolute URL of the
+// site root, without a trailing slash.
+func ProjectActivity(siteURL string, items []githubapi.ActivityItem) *Atom {
+	feedURL := siteURL + "/projects/activity.xml"
+	updated := time.Unix(0, 0)
+	if len(items) > 0 {
+		updated = items[0].Date
+	}
+	a := &Atom{
+		Title:   "Jacob LeCoq — Project activity",
+		ID:      feedURL,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []Link{
+			{Href: feedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL + "/projects", Rel: "alternate", Type: "text/html"},
+		},
+		Author: &Person{Name: "Jacob LeCoq", URI: siteURL},
+	}
+	for _, item := range items {
+		title := item.Repo + ": " + item.Title
+		if item.Kind == githubapi.ActivityRelease {
+			title = item.Repo + " released " + item.Title
+		}
+		e := Entry{
+			Title:    title,
+			ID:       item.URL,
+			Updated:  item.Date.UTC().Format(time.RFC3339),
+			Links:    []Link{{Href: item.URL, Rel: "alternate"}},
+			Category: &Category{Term: string(item.Kind)},
+		}
+		if item.Author != "" {
+			e.Author = &Person{Name: item.Author}
+		}
+		if item.Body != "" {
+			e.Content = &Text{Type: "text", Body: item.Body}
+		}
+		a.Entries = append(a.Entries, e)
+	}
+	return a
+}
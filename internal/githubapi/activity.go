@@ -0,0 +1,303 @@
+package githubapi
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultCommitCount is how many recent commits FetchActivity reads per
+// repository when FetchOptions.CommitCount is zero.
+const DefaultCommitCount = 3
+
+// excerptRunes bounds ActivityItem.Excerpt.
+const excerptRunes = 200
+
+// activityFields selects a repository's latest release and recent
+// default-branch commits. $commits must be declared by the enclosing
+// operation.
+const activityFields = `
+fragment activityFields on Repository {
+  name
+  owner { login }
+  latestRelease { name tagName url publishedAt description }
+  defaultBranchRef {
+    target {
+      ... on Commit {
+        history(first: $commits) {
+          nodes {
+            oid
+            messageHeadline
+            messageBody
+            url
+            committedDate
+            author { name user { login } }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// ActivityKind tells releases and commits apart.
+type ActivityKind string
+
+const (
+	ActivityRelease ActivityKind = "release"
+	ActivityCommit  ActivityKind = "commit"
+)
+
+// ActivityItem is a release or commit in one of the showcased repositories.
+type ActivityItem struct {
+	Kind  ActivityKind `json:"kind"`
+	Repo  string       `json:"repo"`
+	Owner string       `json:"owner"`
+	// Ref is the release tag or the commit SHA.
+	Ref string `json:"ref"`
+	// Title is the release name or the commit's first line.
+	Title string `json:"title"`
+	// Body holds the release notes (Markdown) or the rest of the commit
+	// message.
+	Body   string    `json:"body,omitempty"`
+	URL    string    `json:"url"`
+	Author string    `json:"author,omitempty"`
+	Date   time.Time `json:"date"`
+}
+
+// ProjectKey is the Project.Key of the item's repository.
+func (a ActivityItem) ProjectKey() string {
+	return Project{Owner: a.Owner, Name: a.Repo}.Key()
+}
+
+// ShortRef abbreviates commit SHAs to seven characters, as GitHub does.
+func (a ActivityItem) ShortRef() string {
+	if a.Kind == ActivityCommit && len(a.Ref) > 7 {
+		return a.Ref[:7]
+	}
+	return a.Ref
+}
+
+// Excerpt returns the first paragraph of Body, cut to a couple of lines.
+func (a ActivityItem) Excerpt() string {
+	para, _, _ := strings.Cut(strings.TrimSpace(strings.ReplaceAll(a.Body, "\r\n", "\n")), "\n\n")
+	para = strings.Join(strings.Fields(para), " ")
+	if utf8.RuneCountInString(para) <= excerptRunes {
+		return para
+	}
+	runes := []rune(para)
+	return strings.TrimSpace(string(runes[:excerptRunes])) + "…"
+}
+
+// DisplayDate renders the date, e.g. "Jan 2, 2006".
+func (a ActivityItem) DisplayDate() string {
+	return a.Date.Format("Jan 2, 2006")
+}
+
+type graphqlActivityRepo struct {
+	Name          string       `json:"name"`
+	Owner         graphqlOwner `json:"owner"`
+	LatestRelease *struct {
+		graphqlRelease
+		Description string `json:"description"`
+	} `json:"latestRelease"`
+	DefaultBranchRef *struct {
+		Target struct {
+			History struct {
+				Nodes []struct {
+					OID             string `json:"oid"`
+					MessageHeadline string `json:"messageHeadline"`
+					MessageBody     string `json:"messageBody"`
+					URL             string `json:"url"`
+					CommittedDate   string `json:"committedDate"`
+					Author          struct {
+						Name string `json:"name"`
+						User *struct {
+							Login string `json:"login"`
+						} `json:"user"`
+					} `json:"author"`
+				} `json:"nodes"`
+			} `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+// FetchActivity returns the latest release and the most recent
+// default-branch commits of each GitHub project, newest first.
+func (c *Client) FetchActivity(ctx context.Context, projects []Project, opts FetchOptions) ([]ActivityItem, error) {
+	opts = opts.withDefaults()
+	refs := make([]repoRef, 0, len(projects))
+	for _, p := range projects {
+		if p.Owner != "" && p.OnGitHub() {
+			refs = append(refs, repoRef{owner: p.Owner, name: p.Name})
+		}
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	var items []ActivityItem
+	var err error
+	if c.tokens != nil {
+		items, err = c.fetchGraphQLActivity(ctx, refs, opts)
+	} else {
+		items, err = c.fetchRESTActivity(ctx, refs, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(items, func(a, b ActivityItem) int {
+		return b.Date.Compare(a.Date)
+	})
+	return items, nil
+}
+
+// fetchGraphQLActivity reads every repository's activity in one query, one
+// aliased repository field each.
+func (c *Client) fetchGraphQLActivity(ctx context.Context, refs []repoRef, opts FetchOptions) ([]ActivityItem, error) {
+	var decls, fields strings.Builder
+	vars := map[string]any{"commits": opts.CommitCount}
+	for i, ref := range refs {
+		fmt.Fprintf(&decls, ", $o%d: String!, $n%d: String!", i, i)
+		fmt.Fprintf(&fields, "  r%d: repository(owner: $o%d, name: $n%d) { ...activityFields }\n", i, i, i)
+		vars[fmt.Sprintf("o%d", i)] = ref.owner
+		vars[fmt.Sprintf("n%d", i)] = ref.name
+	}
+	query := fmt.Sprintf("query($commits: Int!%s) {\n%s}\n%s", decls.String(), fields.String(), activityFields)
+
+	var data map[string]*graphqlActivityRepo
+	gqlErrs, err := c.postGraphQL(ctx, query, vars, &data)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range gqlErrs {
+		if e.Type != "NOT_FOUND" {
+			return nil, e.err()
+		}
+	}
+
+	var items []ActivityItem
+	for i := range refs {
+		n := data[fmt.Sprintf("r%d", i)]
+		if n == nil {
+			continue
+		}
+		if r := n.LatestRelease; r != nil {
+			items = append(items, releaseActivity(n.Owner.Login, n.Name, r.Name, r.TagName, r.URL, r.Description, r.PublishedAt))
+		}
+		if n.DefaultBranchRef == nil {
+			continue
+		}
+		for _, cm := range n.DefaultBranchRef.Target.History.Nodes {
+			author := cm.Author.Name
+			if cm.Author.User != nil {
+				author = cm.Author.User.Login
+			}
+			date, _ := time.Parse(time.RFC3339, cm.CommittedDate)
+			items = append(items, ActivityItem{
+				Kind:   ActivityCommit,
+				Repo:   n.Name,
+				Owner:  n.Owner.Login,
+				Ref:    cm.OID,
+				Title:  cm.MessageHeadline,
+				Body:   cm.MessageBody,
+				URL:    cm.URL,
+				Author: author,
+				Date:   date,
+			})
+		}
+	}
+	return items, nil
+}
+
+// restCommit is the raw commit shape returned by the GitHub REST API.
+type restCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// fetchRESTActivity is the unauthenticated fallback. It costs two requests
+// per repository, though unchanged responses come back as 304s, which
+// GitHub does not count against the rate limit.
+func (c *Client) fetchRESTActivity(ctx context.Context, refs []repoRef, opts FetchOptions) ([]ActivityItem, error) {
+	var items []ActivityItem
+	for _, ref := range refs {
+		repoURL := fmt.Sprintf("%s/repos/%s/%s", restURL, url.PathEscape(ref.owner), url.PathEscape(ref.name))
+
+		var release restRelease
+		_, err := c.getREST(ctx, repoURL+"/releases/latest", "application/vnd.github+json", func(body []byte) error {
+			return json.Unmarshal(body, &release)
+		})
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return nil, err
+		default:
+			items = append(items, releaseActivity(ref.owner, ref.name, release.Name, release.TagName, release.HTMLURL, release.Body, release.PublishedAt))
+		}
+
+		var commits []restCommit
+		_, err = c.getREST(ctx, fmt.Sprintf("%s/commits?per_page=%d", repoURL, opts.CommitCount), "application/vnd.github+json", func(body []byte) error {
+			return json.Unmarshal(body, &commits)
+		})
+		// An empty repository answers 409 Conflict; skip it like a missing one.
+		var apiErr *APIError
+		if errors.Is(err, ErrNotFound) || (errors.As(err, &apiErr) && apiErr.StatusCode == 409) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range commits {
+			title, body, _ := strings.Cut(cm.Commit.Message, "\n")
+			author := cm.Commit.Author.Name
+			if cm.Author != nil {
+				author = cm.Author.Login
+			}
+			date, _ := time.Parse(time.RFC3339, cm.Commit.Author.Date)
+			items = append(items, ActivityItem{
+				Kind:   ActivityCommit,
+				Repo:   ref.name,
+				Owner:  ref.owner,
+				Ref:    cm.SHA,
+				Title:  title,
+				Body:   strings.TrimSpace(body),
+				URL:    cm.HTMLURL,
+				Author: author,
+				Date:   date,
+			})
+		}
+	}
+	return items, nil
+}
+
+func releaseActivity(owner, repo, name, tag, link, notes, published string) ActivityItem {
+	date, _ := time.Parse(time.RFC3339, published)
+	return ActivityItem{
+		Kind:  ActivityRelease,
+		Repo:  repo,
+		Owner: owner,
+		Ref:   tag,
+		Title: cmp.Or(name, tag),
+		Body:  notes,
+		URL:   link,
+		Date:  date,
+	}
+}
@@ -0,0 +1,100 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFetchActivityREST(t *testing.T) {
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/u/lib/releases/latest":
+			_, _ = w.Write([]byte(`{"name":"","tag_name":"v2.0.0","html_url":"https://github.com/u/lib/releases/tag/v2.0.0","published_at":"2026-03-05T00:00:00Z","body":"Big release."}`))
+		case "/repos/u/lib/commits":
+			if r.URL.Query().Get("per_page") != "2" {
+				t.Errorf("unexpected per_page %q", r.URL.Query().Get("per_page"))
+			}
+			_, _ = w.Write([]byte(`[
+				{"sha":"aaaaaaaaaa","html_url":"https://github.com/u/lib/commit/aaaaaaaaaa","commit":{"message":"Add feature\n\nLonger explanation.","author":{"name":"U Ser","date":"2026-03-06T00:00:00Z"}},"author":{"login":"u"}},
+				{"sha":"bbbbbbbbbb","html_url":"https://github.com/u/lib/commit/bbbbbbbbbb","commit":{"message":"Tidy","author":{"name":"Someone","date":"2026-03-01T00:00:00Z"}},"author":null}
+			]`))
+		case "/repos/u/empty/commits":
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	items, err := NewClient(client, "").FetchActivity(context.Background(), []Project{
+		{Name: "lib", Owner: "u"},
+		{Name: "empty", Owner: "u"},
+	}, FetchOptions{CommitCount: 2})
+	if err != nil {
+		t.Fatalf("FetchActivity returned error: %v", err)
+	}
+	var got []string
+	for _, it := range items {
+		got = append(got, string(it.Kind)+":"+it.Title+":"+it.Author)
+	}
+	want := "commit:Add feature:u release:v2.0.0: commit:Tidy:Someone"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("items = %q, want %q", strings.Join(got, " "), want)
+	}
+	if items[0].Body != "Longer explanation." || items[0].ShortRef() != "aaaaaaa" || items[1].ShortRef() != "v2.0.0" {
+		t.Fatalf("unexpected first items %+v", items[:2])
+	}
+}
+
+func TestFetchActivityGraphQL(t *testing.T) {
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["commits"] != float64(DefaultCommitCount) || req.Variables["o0"] != "u" || req.Variables["n1"] != "gone" {
+			t.Errorf("unexpected variables %v", req.Variables)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{
+				"r0": map[string]any{
+					"name":          "lib",
+					"owner":         map[string]any{"login": "u"},
+					"latestRelease": map[string]any{"name": "Lib 1", "tagName": "v1", "url": "https://r", "publishedAt": "2026-01-01T00:00:00Z", "description": "Notes"},
+					"defaultBranchRef": map[string]any{"target": map[string]any{"history": map[string]any{"nodes": []any{
+						map[string]any{"oid": "c0ffee00", "messageHeadline": "Ship it", "url": "https://c", "committedDate": "2026-02-01T00:00:00Z", "author": map[string]any{"name": "U", "user": nil}},
+					}}}},
+				},
+				"r1": nil,
+			},
+			"errors": []any{map[string]any{"type": "NOT_FOUND", "message": "gone"}},
+		})
+	})
+
+	items, err := NewClient(client, "token").FetchActivity(context.Background(), []Project{
+		{Name: "lib", Owner: "u"},
+		{Name: "gone", Owner: "u"},
+	}, FetchOptions{})
+	if err != nil {
+		t.Fatalf("FetchActivity returned error: %v", err)
+	}
+	if len(items) != 2 || items[0].Title != "Ship it" || items[0].Author != "U" || items[1].Title != "Lib 1" || items[1].Body != "Notes" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+}
+
+func TestActivityExcerpt(t *testing.T) {
+	long := strings.Repeat("word ", 60)
+	tests := []struct {
+		body, want string
+	}{
+		{"First paragraph\nwraps.\n\nSecond.", "First paragraph wraps."},
+		{"", ""},
+		{long, strings.TrimSpace(long[:excerptRunes]) + "…"},
+	}
+	for _, tt := range tests {
+		if got := (ActivityItem{Body: tt.body}).Excerpt(); got != tt.want {
+			t.Fatalf("Excerpt(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package githubapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the bearer token for each request.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a fixed token, such as a personal access token.
+type StaticToken string
+
+// Token returns the token itself.
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// appJWTLifetime is how long the app's own JWT is valid. GitHub allows at
+// most ten minutes.
+const appJWTLifetime = 9 * time.Minute
+
+// appTokenRefreshMargin is how long before expiry an installation token is
+// replaced, so a token never expires mid-request.
+const appTokenRefreshMargin = 5 * time.Minute
+
+// AppTokenSource authenticates as a GitHub App installation. It signs an
+// RS256 JWT with the app's private key, exchanges it for an installation
+// token and reuses that token until shortly before it expires. It is safe
+// for concurrent use.
+type AppTokenSource struct {
+	// BaseURL is the REST API root, "https://api.github.com" by default.
+	BaseURL string
+
+	httpClient     *http.Client
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	now            func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewAppTokenSource returns a token source for the app's installation,
+// given the app's private key in PEM form as GitHub issues it. With an
+// installationID of zero the app's only installation is used.
+func NewAppTokenSource(httpClient *http.Client, appID, installationID int64, privateKeyPEM []byte) (*AppTokenSource, error) {
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &AppTokenSource{
+		BaseURL:        restURL,
+		httpClient:     httpClient,
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		now:            time.Now,
+	}, nil
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse github app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Token returns a cached installation token, exchanging a new JWT for one
+// when it is missing or about to expire.
+func (a *AppTokenSource) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && a.now().Add(appTokenRefreshMargin).Before(a.expires) {
+		return a.token, nil
+	}
+
+	jwt, err := a.jwt()
+	if err != nil {
+		return "", err
+	}
+	if a.installationID == 0 {
+		if a.installationID, err = a.findInstallation(ctx, jwt); err != nil {
+			return "", err
+		}
+	}
+	var out struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", strings.TrimSuffix(a.BaseURL, "/"), a.installationID)
+	if err := a.call(ctx, http.MethodPost, url, jwt, &out); err != nil {
+		return "", fmt.Errorf("create installation token: %w", err)
+	}
+	if out.Token == "" {
+		return "", errors.New("create installation token: empty token in response")
+	}
+	a.token, a.expires = out.Token, out.ExpiresAt
+	return a.token, nil
+}
+
+// findInstallation returns the ID of the app's only installation.
+func (a *AppTokenSource) findInstallation(ctx context.Context, jwt string) (int64, error) {
+	var installations []struct {
+		ID int64 `json:"id"`
+	}
+	if err := a.call(ctx, http.MethodGet, strings.TrimSuffix(a.BaseURL, "/")+"/app/installations", jwt, &installations); err != nil {
+		return 0, fmt.Errorf("list app installations: %w", err)
+	}
+	if len(installations) != 1 {
+		return 0, fmt.Errorf("github app has %d installations; set the installation ID", len(installations))
+	}
+	return installations[0].ID, nil
+}
+
+// jwt returns the app's RS256-signed JWT. iat is backdated a minute to
+// allow for clock drift, as GitHub recommends.
+func (a *AppTokenSource) jwt() (string, error) {
+	now := a.now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	signing := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign github app jwt: %w", err)
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// call sends an app-authenticated request and decodes the JSON response.
+func (a *AppTokenSource) call(ctx context.Context, method, url, jwt string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("perform request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+		return json.Unmarshal(body, out)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &APIError{StatusCode: resp.StatusCode, Message: githubMessage(body), Err: ErrUnauthorized}
+	case resp.StatusCode == http.StatusNotFound:
+		return &APIError{StatusCode: resp.StatusCode, Message: githubMessage(body), Err: ErrNotFound}
+	default:
+		return &APIError{StatusCode: resp.StatusCode, Message: githubMessage(body), Err: errUnexpectedStatus}
+	}
+}
+
+// TokenSourceFromEnv configures authentication from the environment:
+// GitHub App credentials when GITHUB_APP_ID is set, otherwise GITHUB_TOKEN.
+// The app's private key comes from GITHUB_APP_PRIVATE_KEY (PEM text) or the
+// file named by GITHUB_APP_PRIVATE_KEY_FILE, and GITHUB_APP_INSTALLATION_ID
+// is optional for an app installed once. It returns nil when neither is
+// configured.
+func TokenSourceFromEnv(httpClient *http.Client) (TokenSource, error) {
+	appID := os.Getenv("GITHUB_APP_ID")
+	if appID == "" {
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			return StaticToken(token), nil
+		}
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse GITHUB_APP_ID: %w", err)
+	}
+	var installation int64
+	if v := os.Getenv("GITHUB_APP_INSTALLATION_ID"); v != "" {
+		if installation, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("parse GITHUB_APP_INSTALLATION_ID: %w", err)
+		}
+	}
+	key := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"); len(key) == 0 && path != "" {
+		if key, err = os.ReadFile(path); err != nil { // #nosec G304 -- path comes from server configuration.
+			return nil, fmt.Errorf("read GITHUB_APP_PRIVATE_KEY_FILE: %w", err)
+		}
+	}
+	if len(key) == 0 {
+		return nil, errors.New("GITHUB_APP_ID is set without GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_FILE")
+	}
+	return NewAppTokenSource(httpClient, id, installation, key)
+}
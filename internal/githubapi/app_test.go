@@ -0,0 +1,159 @@
+package githubapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// verifyAppJWT checks an RS256 JWT against the app's public key and
+// returns its claims.
+func verifyAppJWT(t *testing.T, pub *rsa.PublicKey, authorization string) map[string]any {
+	t.Helper()
+	jwt, ok := strings.CutPrefix(authorization, "Bearer ")
+	parts := strings.Split(jwt, ".")
+	if !ok || len(parts) != 3 {
+		t.Fatalf("Authorization = %q, want a bearer JWT", authorization)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("JWT signature does not verify: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("unmarshal claims: %v", err)
+	}
+	return claims
+}
+
+func TestAppTokenSourceExchangesAndCachesTokens(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	exchanges := 0
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := verifyAppJWT(t, &key.PublicKey, r.Header.Get("Authorization"))
+		if claims["iss"] != "1234" || claims["exp"].(float64) > float64(now.Add(10*time.Minute).Unix()) {
+			t.Errorf("unexpected claims %v", claims)
+		}
+		switch r.URL.Path {
+		case "/app/installations":
+			_, _ = w.Write([]byte(`[{"id":42}]`))
+		case "/app/installations/42/access_tokens":
+			if r.Method != http.MethodPost {
+				t.Errorf("token exchange used %s", r.Method)
+			}
+			exchanges++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":%q}`, exchanges, now.Add(time.Hour).Format(time.RFC3339))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer stand.Close()
+
+	src, err := NewAppTokenSource(stand.Client(), 1234, 0, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppTokenSource returned error: %v", err)
+	}
+	src.BaseURL = stand.URL
+	src.now = func() time.Time { return now }
+
+	for range 2 {
+		token, err := src.Token(context.Background())
+		if err != nil || token != "ghs_1" {
+			t.Fatalf("Token = %q, %v; want the cached ghs_1", token, err)
+		}
+	}
+
+	// Within the refresh margin of expiry, a new token is exchanged.
+	now = now.Add(56 * time.Minute)
+	if token, err := src.Token(context.Background()); err != nil || token != "ghs_2" {
+		t.Fatalf("Token = %q, %v; want a refreshed ghs_2", token, err)
+	}
+
+	var auth string
+	client := NewClientWithTokenSource(testClient(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"data":{"user":{"pinnedItems":{"nodes":[]}}}}`))
+	}), src)
+	if _, err := client.FetchProjects(context.Background(), "u", FetchOptions{}); err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	if auth != "Bearer ghs_2" {
+		t.Fatalf("Authorization = %q, want the installation token", auth)
+	}
+}
+
+func TestAppTokenSourceErrors(t *testing.T) {
+	if _, err := NewAppTokenSource(http.DefaultClient, 1, 1, []byte("not a key")); err == nil {
+		t.Fatal("expected a malformed key to be rejected")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"A JSON web token could not be decoded"}`))
+	}))
+	defer stand.Close()
+
+	src, err := NewAppTokenSource(stand.Client(), 1, 7, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("expected a PKCS#8 key to be accepted: %v", err)
+	}
+	src.BaseURL = stand.URL
+	if _, err := src.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "could not be decoded") {
+		t.Fatalf("Token error = %v, want GitHub's message", err)
+	}
+}
+
+func TestTokenSourceFromEnv(t *testing.T) {
+	t.Setenv("GITHUB_APP_ID", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	if src, err := TokenSourceFromEnv(http.DefaultClient); src != nil || err != nil {
+		t.Fatalf("TokenSourceFromEnv = %v, %v; want no source", src, err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "ghp_x")
+	if src, err := TokenSourceFromEnv(http.DefaultClient); err != nil || src != StaticToken("ghp_x") {
+		t.Fatalf("TokenSourceFromEnv = %v, %v; want the personal token", src, err)
+	}
+
+	t.Setenv("GITHUB_APP_ID", "12")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY", "")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY_FILE", "")
+	if _, err := TokenSourceFromEnv(http.DefaultClient); err == nil {
+		t.Fatal("expected an app ID without a key to be rejected")
+	}
+}
@@ -0,0 +1,396 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Errors returned (wrapped in an *APIError) by Client methods. Use
+// errors.Is to tell them apart.
+var (
+	// ErrRateLimited means GitHub's rate limit is exhausted; the client
+	// refuses further calls until APIError.RetryAt.
+	ErrRateLimited = errors.New("github rate limit exceeded")
+	// ErrNotFound means the user, repository or file does not exist.
+	ErrNotFound = errors.New("github resource not found")
+	// ErrUnauthorized means GitHub rejected the token or denied access.
+	ErrUnauthorized = errors.New("github rejected the credentials")
+	// ErrUnavailable means the circuit breaker is open after repeated
+	// failures; no request was sent.
+	ErrUnavailable = errors.New("github temporarily unavailable")
+
+	errUnexpectedStatus = errors.New("unexpected github response")
+)
+
+// APIError describes a failed GitHub call.
+type APIError struct {
+	// StatusCode is the HTTP status, or zero when no response was received.
+	StatusCode int
+	// Message is GitHub's explanation, when it gave one.
+	Message string
+	// RetryAt is when a rate limit resets or the circuit breaker closes.
+	RetryAt time.Time
+	// Err is one of the package's sentinel errors.
+	Err error
+}
+
+func (e *APIError) Error() string {
+	msg := e.Err.Error()
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error { return e.Err }
+
+// RateLimit is the rate-limit state GitHub last reported for a resource.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// maxResponseBytes bounds how much of a response body the client reads.
+const maxResponseBytes = 8 << 20
+
+// maxCachedETags bounds the conditional-request cache.
+const maxCachedETags = 256
+
+// Client calls the GitHub APIs on behalf of the portfolio. It is safe for
+// concurrent use and should be shared, since it remembers ETags, rate-limit
+// state and recent failures across calls.
+type Client struct {
+	httpClient *http.Client
+	// tokens authenticates requests; nil means unauthenticated REST.
+	tokens TokenSource
+
+	// MaxRetries is how many times a call failing with a network error or
+	// a 5xx status is retried.
+	MaxRetries int
+	// BaseBackoff is the delay before the first retry. It doubles on each
+	// attempt, with jitter so concurrent callers don't retry in lockstep.
+	BaseBackoff time.Duration
+	// FailureThreshold consecutive failed calls open the circuit breaker,
+	// which then rejects calls for Cooldown.
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+
+	mu        sync.Mutex
+	etags     map[string]cachedResponse
+	limits    map[string]RateLimit
+	blocked   map[string]time.Time
+	failures  int
+	openUntil time.Time
+}
+
+type cachedResponse struct {
+	etag   string
+	body   []byte
+	header http.Header
+}
+
+// response is a fully read HTTP response.
+type response struct {
+	header http.Header
+	body   []byte
+}
+
+// NewClient returns a Client using httpClient and a personal access token.
+// With an empty token the client uses the unauthenticated REST API.
+func NewClient(httpClient *http.Client, token string) *Client {
+	if token == "" {
+		return NewClientWithTokenSource(httpClient, nil)
+	}
+	return NewClientWithTokenSource(httpClient, StaticToken(token))
+}
+
+// NewClientWithTokenSource returns a Client that asks tokens for a token
+// before each request, such as an AppTokenSource. A nil source means the
+// unauthenticated REST API.
+func NewClientWithTokenSource(httpClient *http.Client, tokens TokenSource) *Client {
+	return &Client{
+		httpClient:       httpClient,
+		tokens:           tokens,
+		MaxRetries:       2,
+		BaseBackoff:      500 * time.Millisecond,
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+		now:              time.Now,
+		sleep:            sleepContext,
+		etags:            make(map[string]cachedResponse),
+		limits:           make(map[string]RateLimit),
+		blocked:          make(map[string]time.Time),
+	}
+}
+
+// authorize sets the Authorization header, when the client has a token
+// source.
+func (c *Client) authorize(req *http.Request) error {
+	if c.tokens == nil {
+		return nil
+	}
+	token, err := c.tokens.Token(req.Context())
+	if err != nil {
+		return fmt.Errorf("get github token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// RateLimit returns the last reported limits for resource, which is "core"
+// for REST calls and "graphql" for GraphQL calls.
+func (c *Client) RateLimit(resource string) RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limits[resource]
+}
+
+// do sends the request built by newReq, which is called again for each
+// retry. GET requests are made conditional on a cached ETag, and a 304
+// returns the cached body.
+func (c *Client) do(ctx context.Context, newReq func() (*http.Request, error)) (*response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		resource := rateLimitResource(req)
+		if err := c.admit(resource); err != nil {
+			return nil, err
+		}
+
+		cacheKey := ""
+		if req.Method == http.MethodGet {
+			cacheKey = req.Header.Get("Accept") + " " + req.URL.String()
+			if cached, ok := c.cached(cacheKey); ok {
+				req.Header.Set("If-None-Match", cached.etag)
+			}
+		}
+
+		res, status, err := c.send(req)
+		if err == nil {
+			c.observe(resource, res.header)
+			err = c.classify(resource, status, res)
+		}
+		if err == nil {
+			c.recordSuccess()
+			return c.remember(cacheKey, status, res), nil
+		}
+		if !retryable(err) {
+			// GitHub answered, so it is up even though the call failed.
+			c.recordSuccess()
+			return nil, err
+		}
+		if attempt >= c.MaxRetries || ctx.Err() != nil {
+			c.recordFailure()
+			return nil, err
+		}
+		if err := c.sleep(ctx, c.backoff(attempt)); err != nil {
+			c.recordFailure()
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(req *http.Request) (*response, int, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("perform %s request: %w", req.Method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response: %w", err)
+	}
+	return &response{header: resp.Header, body: body}, resp.StatusCode, nil
+}
+
+// admit rejects a call up front while the circuit is open or the
+// resource's rate limit is exhausted.
+func (c *Client) admit(resource string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if now.Before(c.openUntil) {
+		return &APIError{Err: ErrUnavailable, RetryAt: c.openUntil}
+	}
+	if until := c.blocked[resource]; now.Before(until) {
+		return &APIError{Err: ErrRateLimited, RetryAt: until, Message: "waiting for the rate limit to reset"}
+	}
+	return nil
+}
+
+// observe records X-RateLimit-* headers. When the remaining quota reaches
+// zero, calls are held back until the reset time.
+func (c *Client) observe(resource string, h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	reset := parseUnix(h.Get("X-RateLimit-Reset"))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limits[resource] = RateLimit{Limit: limit, Remaining: remaining, Reset: reset}
+	if remaining == 0 && !reset.IsZero() {
+		c.blocked[resource] = reset
+	}
+}
+
+// classify turns an HTTP status into one of the package's errors.
+func (c *Client) classify(resource string, status int, res *response) error {
+	switch {
+	case status >= 200 && status < 300, status == http.StatusNotModified:
+		return nil
+	case status == http.StatusUnauthorized:
+		return &APIError{StatusCode: status, Message: githubMessage(res.body), Err: ErrUnauthorized}
+	case status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		if until, limited := c.rateLimitedUntil(res.header); limited {
+			c.mu.Lock()
+			c.blocked[resource] = until
+			c.mu.Unlock()
+			return &APIError{StatusCode: status, Message: githubMessage(res.body), RetryAt: until, Err: ErrRateLimited}
+		}
+		return &APIError{StatusCode: status, Message: githubMessage(res.body), Err: ErrUnauthorized}
+	case status == http.StatusNotFound:
+		return &APIError{StatusCode: status, Message: githubMessage(res.body), Err: ErrNotFound}
+	default:
+		return &APIError{StatusCode: status, Message: githubMessage(res.body), Err: errUnexpectedStatus}
+	}
+}
+
+// rateLimitedUntil reports whether a 403/429 is a primary or secondary
+// rate limit and when calls may resume.
+func (c *Client) rateLimitedUntil(h http.Header) (time.Time, bool) {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return c.now().Add(time.Duration(secs) * time.Second), true
+	}
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if reset := parseUnix(h.Get("X-RateLimit-Reset")); !reset.IsZero() {
+			return reset, true
+		}
+		return c.now().Add(time.Minute), true
+	}
+	return time.Time{}, false
+}
+
+// remember stores the ETag of a fresh response, or substitutes the cached
+// body for a 304.
+func (c *Client) remember(key string, status int, res *response) *response {
+	if key == "" {
+		return res
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status == http.StatusNotModified {
+		if cached, ok := c.etags[key]; ok {
+			return &response{header: cached.header, body: cached.body}
+		}
+		return res
+	}
+	if etag := res.header.Get("ETag"); etag != "" {
+		if len(c.etags) >= maxCachedETags {
+			c.etags = make(map[string]cachedResponse)
+		}
+		c.etags[key] = cachedResponse{etag: etag, body: res.body, header: res.header}
+	}
+	return res
+}
+
+func (c *Client) cached(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.etags[key]
+	return cached, ok
+}
+
+func (c *Client) recordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = 0
+}
+
+// recordFailure counts a call that failed after its retries. Once the
+// threshold is reached the circuit opens; after the cooldown the next call
+// is let through, and a further failure reopens it straight away.
+func (c *Client) recordFailure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures++
+	if c.FailureThreshold > 0 && c.failures >= c.FailureThreshold {
+		c.openUntil = c.now().Add(c.Cooldown)
+	}
+}
+
+// backoff returns the delay before retry attempt+1: the exponential step
+// with "equal jitter", i.e. uniformly between half and all of it.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.BaseBackoff << attempt
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+// retryable reports whether err is a transport failure or a server error.
+// Cancellation of the caller's context is checked separately.
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+func rateLimitResource(req *http.Request) string {
+	if req.URL.String() == graphqlURL {
+		return "graphql"
+	}
+	return "core"
+}
+
+func parseUnix(s string) time.Time {
+	secs, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// githubMessage extracts the "message" field GitHub includes in error bodies.
+func githubMessage(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return ""
+	}
+	return payload.Message
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
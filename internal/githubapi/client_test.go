@@ -0,0 +1,191 @@
+package githubapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a Client with a fixed clock and a sleep that only
+// records the requested delays.
+func newTestClient(h http.HandlerFunc, token string) (*Client, *[]time.Duration) {
+	c := NewClient(testClient(h), token)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	var slept []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return c, &slept
+}
+
+func TestClientSendsIfNoneMatchAndReusesCachedBody(t *testing.T) {
+	var conditional []string
+	c, _ := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`[{"name":"repo","owner":{"login":"u"}}]`))
+	}, "")
+
+	for i := range 2 {
+		projects, err := c.FetchProjects(context.Background(), "u", FetchOptions{})
+		if err != nil {
+			t.Fatalf("call %d: FetchProjects returned error: %v", i, err)
+		}
+		if len(projects) != 1 || projects[0].Name != "repo" {
+			t.Fatalf("call %d: projects = %+v, want the cached repo", i, projects)
+		}
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != `"v1"` {
+		t.Fatalf("If-None-Match headers = %q, want none then \"v1\"", conditional)
+	}
+}
+
+func TestClientStopsCallingUntilRateLimitResets(t *testing.T) {
+	calls := 0
+	var reset time.Time
+	c, _ := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}, "")
+	reset = c.now().Add(30 * time.Minute)
+
+	for range 2 {
+		_, err := c.FetchProjects(context.Background(), "u", FetchOptions{})
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("error = %v, want ErrRateLimited", err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.RetryAt.Equal(reset) {
+			t.Fatalf("error = %#v, want RetryAt %v", err, reset)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("GitHub was called %d times, want 1", calls)
+	}
+	if got := c.RateLimit("core"); got.Limit != 60 || got.Remaining != 0 {
+		t.Fatalf("RateLimit(core) = %+v", got)
+	}
+}
+
+func TestClientRetryAfterHeader(t *testing.T) {
+	c, _ := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "90")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, "")
+	_, err := c.FetchProjects(context.Background(), "u", FetchOptions{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("error = %v, want ErrRateLimited", err)
+	}
+	if want := c.now().Add(90 * time.Second); !apiErr.RetryAt.Equal(want) {
+		t.Fatalf("RetryAt = %v, want %v", apiErr.RetryAt, want)
+	}
+}
+
+func TestClientRetriesServerErrorsWithBackoff(t *testing.T) {
+	calls := 0
+	c, slept := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}, "")
+	c.BaseBackoff = time.Second
+
+	if _, err := c.FetchProjects(context.Background(), "u", FetchOptions{}); err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	if calls != 3 || len(*slept) != 2 {
+		t.Fatalf("calls = %d, sleeps = %v; want 3 calls and 2 sleeps", calls, *slept)
+	}
+	for i, d := range *slept {
+		step := time.Second << i
+		if d < step/2 || d > step {
+			t.Fatalf("sleep %d = %v, want between %v and %v", i, d, step/2, step)
+		}
+	}
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{"unauthorized", http.StatusUnauthorized, ErrUnauthorized},
+		{"forbidden", http.StatusForbidden, ErrUnauthorized},
+		{"not found", http.StatusNotFound, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			c, _ := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+			}, "")
+			_, err := c.FetchProjects(context.Background(), "u", FetchOptions{})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			if calls != 1 {
+				t.Fatalf("GitHub was called %d times, want no retries", calls)
+			}
+		})
+	}
+}
+
+func TestClientMapsGraphQLErrors(t *testing.T) {
+	c, _ := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a User"}]}`))
+	}, "token")
+	_, err := c.FetchProjects(context.Background(), "nobody", FetchOptions{})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	calls := 0
+	c, _ := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}, "")
+	c.MaxRetries = 0
+	c.FailureThreshold = 2
+
+	for range 2 {
+		if _, err := c.FetchProjects(context.Background(), "u", FetchOptions{}); err == nil {
+			t.Fatal("FetchProjects succeeded, want an error")
+		}
+	}
+	_, err := c.FetchProjects(context.Background(), "u", FetchOptions{})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("error = %v, want ErrUnavailable", err)
+	}
+	if calls != 2 {
+		t.Fatalf("GitHub was called %d times, want 2", calls)
+	}
+
+	// After the cooldown a call is let through again.
+	later := c.now().Add(c.Cooldown)
+	c.now = func() time.Time { return later }
+	_, _ = c.FetchProjects(context.Background(), "u", FetchOptions{})
+	if calls != 3 {
+		t.Fatalf("GitHub was called %d times after the cooldown, want 3", calls)
+	}
+}
@@ -0,0 +1,143 @@
+package githubapi
+
+import (
+	"context"
+	"time"
+)
+
+const contributionsQuery = `
+query($login: String!, $from: DateTime!, $to: DateTime!) {
+  user(login: $login) {
+    contributionsCollection(from: $from, to: $to) {
+      contributionCalendar {
+        totalContributions
+        weeks {
+          contributionDays { date contributionCount contributionLevel }
+        }
+      }
+    }
+  }
+}
+`
+
+// ContributionCalendar is a user's daily contribution counts over a year,
+// as shown on their GitHub profile.
+type ContributionCalendar struct {
+	Total int               `json:"total"`
+	Days  []ContributionDay `json:"days"`
+}
+
+// ContributionDay is one day of the calendar.
+type ContributionDay struct {
+	// Date is formatted as "2006-01-02".
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	// Level is GitHub's shading bucket, from 0 (none) to 4 (most).
+	Level int `json:"level"`
+}
+
+// DisplayDate renders the date, e.g. "Jan 2, 2006", or "" when unknown.
+func (d ContributionDay) DisplayDate() string {
+	t, err := time.Parse(time.DateOnly, d.Date)
+	if err != nil {
+		return ""
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// ContributionStats summarises a calendar.
+type ContributionStats struct {
+	Total      int
+	ActiveDays int
+	// CurrentStreak counts consecutive active days up to the last day of
+	// the calendar, or the day before when nothing has happened yet today.
+	CurrentStreak int
+	LongestStreak int
+	BusiestDay    ContributionDay
+}
+
+// contributionLevels maps GitHub's ContributionLevel enum to 0–4.
+var contributionLevels = map[string]int{
+	"NONE":            0,
+	"FIRST_QUARTILE":  1,
+	"SECOND_QUARTILE": 2,
+	"THIRD_QUARTILE":  3,
+	"FOURTH_QUARTILE": 4,
+}
+
+type graphqlContributions struct {
+	ContributionCalendar struct {
+		TotalContributions int `json:"totalContributions"`
+		Weeks              []struct {
+			ContributionDays []struct {
+				Date              string `json:"date"`
+				ContributionCount int    `json:"contributionCount"`
+				ContributionLevel string `json:"contributionLevel"`
+			} `json:"contributionDays"`
+		} `json:"weeks"`
+	} `json:"contributionCalendar"`
+}
+
+// FetchContributions returns the user's contribution calendar for the year
+// up to now. The calendar is only available over GraphQL, so without a
+// token it returns nil.
+func (c *Client) FetchContributions(ctx context.Context, username string) (*ContributionCalendar, error) {
+	if c.tokens == nil {
+		return nil, nil
+	}
+	to := c.now().UTC()
+	data, err := c.queryGraphQL(ctx, contributionsQuery, map[string]any{
+		"login": username,
+		"from":  to.AddDate(-1, 0, 0).Format(time.RFC3339),
+		"to":    to.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.User == nil {
+		return nil, &APIError{Err: ErrNotFound, Message: "user " + username}
+	}
+
+	raw := data.User.ContributionsCollection.ContributionCalendar
+	cal := &ContributionCalendar{Total: raw.TotalContributions}
+	for _, w := range raw.Weeks {
+		for _, d := range w.ContributionDays {
+			cal.Days = append(cal.Days, ContributionDay{
+				Date:  d.Date,
+				Count: d.ContributionCount,
+				Level: contributionLevels[d.ContributionLevel],
+			})
+		}
+	}
+	return cal, nil
+}
+
+// Stats computes totals and streaks. Days are expected in date order, as
+// GitHub returns them.
+func (c *ContributionCalendar) Stats() ContributionStats {
+	s := ContributionStats{Total: c.Total}
+	run := 0
+	for _, d := range c.Days {
+		if d.Count == 0 {
+			run = 0
+			continue
+		}
+		s.ActiveDays++
+		run++
+		s.LongestStreak = max(s.LongestStreak, run)
+		if d.Count > s.BusiestDay.Count {
+			s.BusiestDay = d
+		}
+	}
+
+	// Walk back from the last day; an empty last day (today, so far)
+	// doesn't break the streak.
+	for i := len(c.Days) - 1; i >= 0; i-- {
+		if c.Days[i].Count > 0 {
+			s.CurrentStreak++
+		} else if i < len(c.Days)-1 {
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestFetchContributions(t *testing.T) {
+	var vars map[string]any
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		vars = req.Variables
+		day := func(date string, count int, level string) map[string]any {
+			return map[string]any{"date": date, "contributionCount": count, "contributionLevel": level}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": map[string]any{
+			"contributionsCollection": map[string]any{"contributionCalendar": map[string]any{
+				"totalContributions": 7,
+				"weeks": []any{
+					map[string]any{"contributionDays": []any{day("2026-03-01", 2, "SECOND_QUARTILE"), day("2026-03-02", 0, "NONE")}},
+					map[string]any{"contributionDays": []any{day("2026-03-08", 5, "FOURTH_QUARTILE")}},
+				},
+			}},
+		}}})
+	})
+
+	cal, err := NewClient(client, "token").FetchContributions(context.Background(), "u")
+	if err != nil {
+		t.Fatalf("FetchContributions returned error: %v", err)
+	}
+	if vars["login"] != "u" || vars["from"] == "" || vars["to"] == "" {
+		t.Fatalf("unexpected variables %v", vars)
+	}
+	if cal.Total != 7 || len(cal.Days) != 3 || cal.Days[2] != (ContributionDay{Date: "2026-03-08", Count: 5, Level: 4}) {
+		t.Fatalf("unexpected calendar %+v", cal)
+	}
+
+	cal, err = NewClient(client, "").FetchContributions(context.Background(), "u")
+	if cal != nil || err != nil {
+		t.Fatalf("expected no calendar without a token, got %+v, %v", cal, err)
+	}
+}
+
+func TestContributionStats(t *testing.T) {
+	counts := func(cs ...int) *ContributionCalendar {
+		cal := &ContributionCalendar{}
+		for i, c := range cs {
+			cal.Days = append(cal.Days, ContributionDay{Date: "2026-01-0" + string(rune('1'+i)), Count: c})
+			cal.Total += c
+		}
+		return cal
+	}
+	tests := []struct {
+		name             string
+		cal              *ContributionCalendar
+		current, longest int
+		active           int
+		busiest          string
+	}{
+		{"active today", counts(1, 1, 0, 2, 3), 2, 2, 4, "2026-01-05"},
+		{"nothing yet today", counts(4, 0, 1, 1, 1, 0), 3, 3, 4, "2026-01-01"},
+		{"broken streak", counts(1, 1, 1, 0, 0), 0, 3, 3, "2026-01-01"},
+		{"empty", counts(), 0, 0, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.cal.Stats()
+			if s.CurrentStreak != tt.current || s.LongestStreak != tt.longest || s.ActiveDays != tt.active || s.BusiestDay.Date != tt.busiest {
+				t.Fatalf("Stats() = %+v", s)
+			}
+		})
+	}
+	if got := (ContributionDay{Date: "2026-03-08"}).DisplayDate(); got != "Mar 8, 2026" {
+		t.Fatalf("DisplayDate() = %q", got)
+	}
+}
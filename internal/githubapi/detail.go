@@ -0,0 +1,190 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const detailQuery = `
+query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    name
+    owner { login }
+    description
+    url
+    primaryLanguage { name }
+    stargazerCount
+    forkCount
+    updatedAt
+    homepageUrl
+    openGraphImageUrl
+    usesCustomOpenGraphImage
+    repositoryTopics(first: 20) {
+      nodes { topic { name } }
+    }
+    languages(first: 10, orderBy: {field: SIZE, direction: DESC}) {
+      edges { size node { name color } }
+    }
+    defaultBranchRef { name }
+    readme: object(expression: "HEAD:README.md") { ... on Blob { text } }
+    readmeLower: object(expression: "HEAD:readme.md") { ... on Blob { text } }
+    latestRelease { name tagName url publishedAt }
+  }
+}
+`
+
+// maxReadmeBytes caps how much of a README is fetched over REST.
+const maxReadmeBytes = 1 << 20
+
+// ProjectDetail is a repository with the extra data shown on its detail page.
+type ProjectDetail struct {
+	Project
+	// README is the Markdown source of the repository's README, if any.
+	README string `json:"readme,omitempty"`
+	// DefaultBranch is used to resolve relative links in the README.
+	DefaultBranch string   `json:"defaultBranch,omitempty"`
+	LatestRelease *Release `json:"latestRelease,omitempty"`
+}
+
+// Release is a published GitHub release.
+type Release struct {
+	Name        string `json:"name"`
+	TagName     string `json:"tagName"`
+	URL         string `json:"url"`
+	PublishedAt string `json:"publishedAt"`
+}
+
+// Date renders the publish date, e.g. "Jan 2, 2006", or "" when unknown.
+func (r Release) Date() string {
+	t, err := time.Parse(time.RFC3339, r.PublishedAt)
+	if err != nil {
+		return ""
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// FetchProjectDetail retrieves a repository together with its README and
+// latest release. Like FetchProjects, it uses GraphQL when the client has a
+// token and falls back to the unauthenticated REST API otherwise.
+func (c *Client) FetchProjectDetail(ctx context.Context, owner, name string) (*ProjectDetail, error) {
+	if c.tokens != nil {
+		return c.fetchGraphQLDetail(ctx, owner, name)
+	}
+	return c.fetchRESTDetail(ctx, owner, name)
+}
+
+func (c *Client) fetchGraphQLDetail(ctx context.Context, owner, name string) (*ProjectDetail, error) {
+	data, err := c.queryGraphQL(ctx, detailQuery, map[string]any{"owner": owner, "name": name})
+	if err != nil {
+		return nil, err
+	}
+	n := data.Repository
+	if n == nil {
+		return nil, &APIError{Err: ErrNotFound, Message: fmt.Sprintf("repository %s/%s", owner, name)}
+	}
+
+	d := &ProjectDetail{Project: n.project()}
+	if n.DefaultBranchRef != nil {
+		d.DefaultBranch = n.DefaultBranchRef.Name
+	}
+	switch {
+	case n.Readme != nil:
+		d.README = n.Readme.Text
+	case n.ReadmeLower != nil:
+		d.README = n.ReadmeLower.Text
+	}
+	if r := n.LatestRelease; r != nil {
+		d.LatestRelease = &Release{Name: r.Name, TagName: r.TagName, URL: r.URL, PublishedAt: r.PublishedAt}
+	}
+	return d, nil
+}
+
+// restRelease is the raw release shape returned by the GitHub REST API.
+type restRelease struct {
+	Name        string `json:"name"`
+	TagName     string `json:"tag_name"`
+	HTMLURL     string `json:"html_url"`
+	PublishedAt string `json:"published_at"`
+	Body        string `json:"body"`
+}
+
+// fetchRESTDetail is the unauthenticated fallback. It costs three requests
+// against the 60 req/hr limit; a missing README or release is not an error.
+func (c *Client) fetchRESTDetail(ctx context.Context, owner, name string) (*ProjectDetail, error) {
+	repoURL := fmt.Sprintf("%s/repos/%s/%s", restURL, url.PathEscape(owner), url.PathEscape(name))
+
+	var repo restProject
+	if _, err := c.getREST(ctx, repoURL, "application/vnd.github+json", func(body []byte) error {
+		return json.Unmarshal(body, &repo)
+	}); err != nil {
+		return nil, err
+	}
+	d := &ProjectDetail{Project: repo.project(), DefaultBranch: repo.DefaultBranch}
+
+	_, err := c.getREST(ctx, repoURL+"/readme", "application/vnd.github.raw", func(body []byte) error {
+		d.README = string(body[:min(len(body), maxReadmeBytes)])
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	var release restRelease
+	_, err = c.getREST(ctx, repoURL+"/releases/latest", "application/vnd.github+json", func(body []byte) error {
+		return json.Unmarshal(body, &release)
+	})
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		d.LatestRelease = &Release{
+			Name:        release.Name,
+			TagName:     release.TagName,
+			URL:         release.HTMLURL,
+			PublishedAt: release.PublishedAt,
+		}
+	}
+	return d, nil
+}
+
+// ResolveReadmeLink rewrites a link or image destination found in the
+// README so it works outside GitHub: relative links point at the file on
+// github.com and relative images at raw.githubusercontent.com. Absolute
+// URLs and in-page anchors are returned unchanged.
+func (d *ProjectDetail) ResolveReadmeLink(dest string, image bool) string {
+	u, err := url.Parse(dest)
+	if err != nil || u.IsAbs() || u.Host != "" || dest == "" || strings.HasPrefix(dest, "#") {
+		return dest
+	}
+	owner, name := d.Owner, d.Name
+	if owner == "" || name == "" {
+		return dest
+	}
+	branch := d.DefaultBranch
+	if branch == "" {
+		branch = "HEAD"
+	}
+
+	// GitHub resolves README links against the repository root, and a
+	// leading slash also means the root.
+	p := strings.TrimPrefix(path.Clean("/"+u.EscapedPath()), "/")
+	base := fmt.Sprintf("https://github.com/%s/%s/blob/%s/", owner, name, branch)
+	if image {
+		base = fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/", owner, name, branch)
+	}
+	out := base + p
+	if u.RawQuery != "" {
+		out += "?" + u.RawQuery
+	}
+	if u.Fragment != "" {
+		out += "#" + u.Fragment
+	}
+	return out
+}
@@ -0,0 +1,32 @@
+package githubapi
+
+import "testing"
+
+func TestResolveReadmeLink(t *testing.T) {
+	d := &ProjectDetail{
+		Project:       Project{Name: "runeforge", Owner: "HexSleeves"},
+		DefaultBranch: "main",
+	}
+
+	tests := []struct {
+		dest  string
+		image bool
+		want  string
+	}{
+		{"docs/usage.md", false, "https://github.com/HexSleeves/runeforge/blob/main/docs/usage.md"},
+		{"./docs/usage.md#install", false, "https://github.com/HexSleeves/runeforge/blob/main/docs/usage.md#install"},
+		{"/LICENSE", false, "https://github.com/HexSleeves/runeforge/blob/main/LICENSE"},
+		{"../escape.md", false, "https://github.com/HexSleeves/runeforge/blob/main/escape.md"},
+		{"assets/demo.gif", true, "https://raw.githubusercontent.com/HexSleeves/runeforge/main/assets/demo.gif"},
+		{"docs/my notes.md", false, "https://github.com/HexSleeves/runeforge/blob/main/docs/my%20notes.md"},
+		{"#usage", false, "#usage"},
+		{"https://example.com/x.png", true, "https://example.com/x.png"},
+		{"mailto:hi@example.com", false, "mailto:hi@example.com"},
+		{"//cdn.example.com/x.png", true, "//cdn.example.com/x.png"},
+	}
+	for _, tt := range tests {
+		if got := d.ResolveReadmeLink(tt.dest, tt.image); got != tt.want {
+			t.Fatalf("ResolveReadmeLink(%q, %v) = %q, want %q", tt.dest, tt.image, got, tt.want)
+		}
+	}
+}
@@ -9,39 +9,74 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 const (
-	userAgent   = "portfolio-site"
-	graphqlURL  = "https://api.github.com/graphql"
+	userAgent  = "portfolio-site"
+	graphqlURL = "https://api.github.com/graphql"
+	restURL    = "https://api.github.com"
+
+	// repoFields is the Repository selection shared by every project query.
+	// $topics and $languages must be declared by the enclosing operation.
+	repoFields = `
+fragment repoFields on Repository {
+  name
+  owner { login }
+  description
+  url
+  primaryLanguage { name }
+  stargazerCount
+  forkCount
+  isFork
+  isArchived
+  updatedAt
+  homepageUrl
+  openGraphImageUrl
+  usesCustomOpenGraphImage
+  repositoryTopics(first: $topics) {
+    nodes { topic { name } }
+  }
+  languages(first: $languages, orderBy: {field: SIZE, direction: DESC}) {
+    edges { size node { name color } }
+  }
+}
+`
 	pinnedQuery = `
-query($login: String!) {
+query($login: String!, $first: Int!, $after: String, $topics: Int!, $languages: Int!) {
   user(login: $login) {
-    pinnedItems(first: 6, types: [REPOSITORY]) {
+    pinnedItems(first: $first, after: $after, types: [REPOSITORY]) {
+      pageInfo { hasNextPage endCursor }
       nodes {
-        ... on Repository {
-          name
-          description
-          url
-          primaryLanguage { name }
-          stargazerCount
-          forkCount
-          updatedAt
-          homepageUrl
-          repositoryTopics(first: 5) {
-            nodes { topic { name } }
-          }
-        }
+        ... on Repository { ...repoFields }
       }
     }
   }
 }
-`
+` + repoFields
 )
 
-// Project represents a GitHub repository for display on the portfolio.
+// SourceGitHub is the Project.Source of repositories fetched by this
+// package.
+const SourceGitHub = "GitHub"
+
+// ForgeGitHub is the Project.Forge of repositories fetched by this
+// package.
+const ForgeGitHub = "github"
+
+// Project represents a repository for display on the portfolio. Other
+// forges are mapped onto the same shape; see the sources package.
 type Project struct {
+	// Source names the forge hosting the repository, e.g. "GitHub". It is
+	// only a label for the card; Forge says which API the repository
+	// comes from.
+	Source string `json:"source"`
+	// Forge is the kind of forge hosting the repository, e.g. ForgeGitHub
+	// or "gitlab". Empty means GitHub, unless the project is Manual.
+	Forge       string   `json:"forge,omitempty"`
 	Name        string   `json:"name"`
+	Owner       string   `json:"owner"`
 	Description string   `json:"description"`
 	URL         string   `json:"url"`
 	Language    string   `json:"language"`
@@ -50,6 +85,53 @@ type Project struct {
 	UpdatedAt   string   `json:"updatedAt"`
 	HomepageURL string   `json:"homepageUrl"`
 	Topics      []string `json:"topics"`
+	// Languages is the language breakdown, largest first. It is only
+	// available over GraphQL.
+	Languages []Language `json:"languages,omitempty"`
+	Fork      bool       `json:"fork,omitempty"`
+	Archived  bool       `json:"archived,omitempty"`
+	// Image is a screenshot or logo shown on the project's card.
+	Image string        `json:"image,omitempty"`
+	Links []ProjectLink `json:"links,omitempty"`
+	// Manual marks entries written by hand in projects.yaml rather than
+	// fetched from a forge.
+	Manual bool `json:"manual,omitempty"`
+
+	// OpenGraphImage is the repository's social preview image on GitHub,
+	// and CustomOpenGraphImage reports whether it was uploaded rather than
+	// generated by GitHub. Only GraphQL returns them.
+	OpenGraphImage       string `json:"openGraphImage,omitempty"`
+	CustomOpenGraphImage bool   `json:"customOpenGraphImage,omitempty"`
+}
+
+// ProjectLink is an extra link shown on a project's card, such as a case
+// study or an app store listing.
+type ProjectLink struct {
+	Label string `json:"label" yaml:"label"`
+	URL   string `json:"url" yaml:"url"`
+}
+
+// OnGitHub reports whether the project is hosted on GitHub, so that
+// GitHub-only data such as READMEs and activity can be fetched for it.
+func (p Project) OnGitHub() bool {
+	return !p.Manual && (p.Forge == "" || p.Forge == ForgeGitHub)
+}
+
+// Key identifies the project among those of every forge and owner, e.g.
+// "github/HexSleeves/runeforge", or "manual/{name}" for a manual entry.
+// Detail pages live at /projects/{Key}.
+func (p Project) Key() string {
+	forge := p.Forge
+	switch {
+	case p.Manual:
+		forge = "manual"
+	case forge == "":
+		forge = ForgeGitHub
+	}
+	if p.Owner == "" {
+		return forge + "/" + p.Name
+	}
+	return forge + "/" + p.Owner + "/" + p.Name
 }
 
 // graphQL request/response types
@@ -59,42 +141,93 @@ type graphqlRequest struct {
 }
 
 type graphqlResponse struct {
-	Data   graphqlData    `json:"data"`
-	Errors []graphqlError `json:"errors"`
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
 }
 
 type graphqlError struct {
+	Type    string `json:"type"`
 	Message string `json:"message"`
 }
 
 type graphqlData struct {
-	User graphqlUser `json:"user"`
+	User       *graphqlUser `json:"user"`
+	Repository *graphqlRepo `json:"repository"`
 }
 
 type graphqlUser struct {
-	PinnedItems graphqlPinnedItems `json:"pinnedItems"`
+	PinnedItems  graphqlRepoConnection `json:"pinnedItems"`
+	Repositories graphqlRepoConnection `json:"repositories"`
+
+	ContributionsCollection graphqlContributions `json:"contributionsCollection"`
+}
+
+type graphqlRepoConnection struct {
+	PageInfo graphqlPageInfo `json:"pageInfo"`
+	Nodes    []graphqlRepo   `json:"nodes"`
 }
 
-type graphqlPinnedItems struct {
-	Nodes []graphqlRepo `json:"nodes"`
+type graphqlPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
 }
 
 type graphqlRepo struct {
-	Name            string              `json:"name"`
-	Description     string              `json:"description"`
-	URL             string              `json:"url"`
-	PrimaryLanguage *graphqlLanguage    `json:"primaryLanguage"`
-	StargazerCount  int                 `json:"stargazerCount"`
-	ForkCount       int                 `json:"forkCount"`
-	UpdatedAt       string              `json:"updatedAt"`
-	HomepageURL     string              `json:"homepageUrl"`
-	RepositoryTopics graphqlTopics      `json:"repositoryTopics"`
+	Name             string           `json:"name"`
+	Owner            graphqlOwner     `json:"owner"`
+	Description      string           `json:"description"`
+	URL              string           `json:"url"`
+	PrimaryLanguage  *graphqlLanguage `json:"primaryLanguage"`
+	StargazerCount   int              `json:"stargazerCount"`
+	ForkCount        int              `json:"forkCount"`
+	IsFork           bool             `json:"isFork"`
+	IsArchived       bool             `json:"isArchived"`
+	UpdatedAt        string           `json:"updatedAt"`
+	HomepageURL      string           `json:"homepageUrl"`
+	RepositoryTopics graphqlTopics    `json:"repositoryTopics"`
+	Languages        graphqlLanguages `json:"languages"`
+	DefaultBranchRef *graphqlRef      `json:"defaultBranchRef"`
+	Readme           *graphqlBlob     `json:"readme"`
+	ReadmeLower      *graphqlBlob     `json:"readmeLower"`
+	LatestRelease    *graphqlRelease  `json:"latestRelease"`
+
+	OpenGraphImageURL        string `json:"openGraphImageUrl"`
+	UsesCustomOpenGraphImage bool   `json:"usesCustomOpenGraphImage"`
 }
 
-type graphqlLanguage struct {
+type graphqlOwner struct {
+	Login string `json:"login"`
+}
+
+type graphqlRef struct {
 	Name string `json:"name"`
 }
 
+type graphqlBlob struct {
+	Text string `json:"text"`
+}
+
+type graphqlRelease struct {
+	Name        string `json:"name"`
+	TagName     string `json:"tagName"`
+	URL         string `json:"url"`
+	PublishedAt string `json:"publishedAt"`
+}
+
+type graphqlLanguage struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type graphqlLanguages struct {
+	Edges []graphqlLanguageEdge `json:"edges"`
+}
+
+type graphqlLanguageEdge struct {
+	Size int64           `json:"size"`
+	Node graphqlLanguage `json:"node"`
+}
+
 type graphqlTopics struct {
 	Nodes []graphqlTopicNode `json:"nodes"`
 }
@@ -107,129 +240,278 @@ type graphqlTopic struct {
 	Name string `json:"name"`
 }
 
-// FetchProjects retrieves the user's pinned repositories via the GitHub GraphQL API.
-// If no token is provided, it falls back to the REST API (unauthenticated, 60 req/hr limit).
-func FetchProjects(ctx context.Context, client *http.Client, username, token string) ([]Project, error) {
-	if token != "" {
-		return fetchPinnedProjects(ctx, client, username, token)
+// FetchProjects retrieves the user's pinned repositories via the GitHub GraphQL API,
+// followed by any extra repositories listed in opts.
+// If the client has no token, it falls back to the REST API (unauthenticated, 60 req/hr limit),
+// which has no notion of pinning and returns the most recently updated repositories instead.
+func (c *Client) FetchProjects(ctx context.Context, username string, opts FetchOptions) ([]Project, error) {
+	opts = opts.withDefaults()
+	extras, err := parseRepoRefs(opts.ExtraRepos)
+	if err != nil {
+		return nil, err
 	}
-	// Fallback to REST API when no token is available (e.g., local dev without token)
-	return fetchRESTProjects(ctx, client, username)
-}
 
-// fetchPinnedProjects uses the GraphQL API to get the user's pinned repositories.
-func fetchPinnedProjects(ctx context.Context, client *http.Client, username, token string) ([]Project, error) {
-	body, err := json.Marshal(graphqlRequest{
-		Query:     pinnedQuery,
-		Variables: map[string]any{"login": username},
-	})
+	var projects, extraProjects []Project
+	if c.tokens != nil {
+		if projects, err = c.fetchPinnedProjects(ctx, username, opts); err != nil {
+			return nil, err
+		}
+		extraProjects, err = c.fetchGraphQLRepos(ctx, extras, opts)
+	} else {
+		// Fallback to REST API when no token is available (e.g., local dev without token)
+		if projects, err = c.fetchRESTProjects(ctx, username, opts); err != nil {
+			return nil, err
+		}
+		extraProjects, err = c.fetchRESTRepos(ctx, extras)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("marshal graphql request: %w", err)
+		return nil, err
 	}
+	return mergeProjects(projects, extraProjects, opts), nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, graphqlURL, bytes.NewReader(body))
+// fetchPinnedProjects uses the GraphQL API to get the user's pinned repositories,
+// following the pinnedItems cursor until opts.PinnedCount projects are collected.
+func (c *Client) fetchPinnedProjects(ctx context.Context, username string, opts FetchOptions) ([]Project, error) {
+	var projects []Project
+	var after *string
+	for page := 0; page < opts.MaxPages && len(projects) < opts.PinnedCount; page++ {
+		data, err := c.queryGraphQL(ctx, pinnedQuery, map[string]any{
+			"login":     username,
+			"first":     opts.pageSize(opts.PinnedCount - len(projects)),
+			"after":     after,
+			"topics":    opts.TopicCount,
+			"languages": opts.LanguageCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if data.User == nil {
+			return nil, &APIError{Err: ErrNotFound, Message: fmt.Sprintf("user %q", username)}
+		}
+
+		items := data.User.PinnedItems
+		for _, n := range items.Nodes {
+			if p := n.project(); !opts.Excluded(p) && len(projects) < opts.PinnedCount {
+				projects = append(projects, p)
+			}
+		}
+		if !items.PageInfo.HasNextPage {
+			break
+		}
+		cursor := items.PageInfo.EndCursor
+		after = &cursor
+	}
+	return projects, nil
+}
+
+// queryGraphQL runs a GraphQL query and returns its data, turning
+// transport failures and GraphQL errors into Go errors.
+func (c *Client) queryGraphQL(ctx context.Context, query string, variables map[string]any) (graphqlData, error) {
+	var data graphqlData
+	gqlErrs, err := c.postGraphQL(ctx, query, variables, &data)
 	if err != nil {
-		return nil, fmt.Errorf("create graphql request: %w", err)
+		return graphqlData{}, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Authorization", "Bearer "+token)
+	if len(gqlErrs) > 0 {
+		return graphqlData{}, gqlErrs[0].err()
+	}
+	return data, nil
+}
 
-	resp, err := client.Do(req)
+// postGraphQL sends a GraphQL request and decodes its data into out. Errors
+// reported by GraphQL are returned alongside any partial data so callers can
+// decide which are fatal; a rate-limit error always is.
+func (c *Client) postGraphQL(ctx context.Context, query string, variables map[string]any, out any) ([]graphqlError, error) {
+	body, err := json.Marshal(graphqlRequest{
+		Query:     query,
+		Variables: variables,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("perform graphql request: %w", err)
+		return nil, fmt.Errorf("marshal graphql request: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected github graphql status: %s", resp.Status)
+	res, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, graphqlURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("create graphql request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("User-Agent", userAgent)
+		if err := c.authorize(req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	var gqlResp graphqlResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
+	if err := json.Unmarshal(res.body, &gqlResp); err != nil {
 		return nil, fmt.Errorf("decode graphql response: %w", err)
 	}
-	if len(gqlResp.Errors) > 0 {
-		return nil, fmt.Errorf("graphql error: %s", gqlResp.Errors[0].Message)
-	}
-
-	nodes := gqlResp.Data.User.PinnedItems.Nodes
-	projects := make([]Project, 0, len(nodes))
-	for _, n := range nodes {
-		p := Project{
-			Name:        n.Name,
-			Description: n.Description,
-			URL:         n.URL,
-			Stars:       n.StargazerCount,
-			Forks:       n.ForkCount,
-			UpdatedAt:   n.UpdatedAt,
-			HomepageURL: n.HomepageURL,
-		}
-		if n.PrimaryLanguage != nil {
-			p.Language = n.PrimaryLanguage.Name
+	for _, e := range gqlResp.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return nil, e.err()
 		}
-		for _, tn := range n.RepositoryTopics.Nodes {
-			p.Topics = append(p.Topics, tn.Topic.Name)
+	}
+	if len(gqlResp.Data) > 0 && string(gqlResp.Data) != "null" {
+		if err := json.Unmarshal(gqlResp.Data, out); err != nil {
+			return nil, fmt.Errorf("decode graphql response: %w", err)
 		}
-		projects = append(projects, p)
 	}
-	return projects, nil
+	return gqlResp.Errors, nil
+}
+
+// err converts a GraphQL error to an *APIError.
+func (e graphqlError) err() error {
+	sentinel := errUnexpectedStatus
+	switch e.Type {
+	case "NOT_FOUND":
+		sentinel = ErrNotFound
+	case "RATE_LIMITED":
+		sentinel = ErrRateLimited
+	case "FORBIDDEN":
+		sentinel = ErrUnauthorized
+	}
+	return &APIError{Message: e.Message, Err: sentinel}
+}
+
+// project converts a GraphQL repository node to a Project.
+func (n graphqlRepo) project() Project {
+	p := Project{
+		Source:      SourceGitHub,
+		Forge:       ForgeGitHub,
+		Name:        n.Name,
+		Owner:       n.Owner.Login,
+		Description: n.Description,
+		URL:         n.URL,
+		Stars:       n.StargazerCount,
+		Forks:       n.ForkCount,
+		UpdatedAt:   n.UpdatedAt,
+		HomepageURL: n.HomepageURL,
+		Fork:        n.IsFork,
+		Archived:    n.IsArchived,
+
+		OpenGraphImage:       n.OpenGraphImageURL,
+		CustomOpenGraphImage: n.UsesCustomOpenGraphImage,
+	}
+	if n.PrimaryLanguage != nil {
+		p.Language = n.PrimaryLanguage.Name
+	}
+	for _, tn := range n.RepositoryTopics.Nodes {
+		p.Topics = append(p.Topics, tn.Topic.Name)
+	}
+	for _, e := range n.Languages.Edges {
+		p.Languages = append(p.Languages, Language{Name: e.Node.Name, Color: e.Node.Color, Bytes: e.Size})
+	}
+	return p
 }
 
 // restProject is the raw shape returned by the GitHub REST API.
 type restProject struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	HTMLURL     string `json:"html_url"`
-	Language    string `json:"language"`
-	Stars       int    `json:"stargazers_count"`
-	Forks       int    `json:"forks_count"`
-	UpdatedAt   string `json:"updated_at"`
-	Homepage    string `json:"homepage"`
-}
-
-// fetchRESTProjects is the unauthenticated fallback using the REST API.
-func fetchRESTProjects(ctx context.Context, client *http.Client, username string) ([]Project, error) {
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		fmt.Sprintf("https://api.github.com/users/%s/repos?sort=updated&per_page=12", username),
-		nil,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("create rest request: %w", err)
+	Name  string `json:"name"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Description   string   `json:"description"`
+	HTMLURL       string   `json:"html_url"`
+	Language      string   `json:"language"`
+	Stars         int      `json:"stargazers_count"`
+	Forks         int      `json:"forks_count"`
+	UpdatedAt     string   `json:"updated_at"`
+	Homepage      string   `json:"homepage"`
+	Topics        []string `json:"topics"`
+	DefaultBranch string   `json:"default_branch"`
+	Fork          bool     `json:"fork"`
+	Archived      bool     `json:"archived"`
+}
+
+// fetchRESTProjects is the unauthenticated fallback using the REST API. It
+// follows the Link header's rel="next" URL until opts.RecentCount projects
+// are collected or opts.MaxPages pages have been read.
+func (c *Client) fetchRESTProjects(ctx context.Context, username string, opts FetchOptions) ([]Project, error) {
+	next := fmt.Sprintf("%s/users/%s/repos?sort=updated&per_page=%d",
+		restURL, url.PathEscape(username), opts.pageSize(opts.RecentCount))
+
+	var projects []Project
+	for page := 0; next != "" && page < opts.MaxPages && len(projects) < opts.RecentCount; page++ {
+		var raw []restProject
+		header, err := c.getREST(ctx, next, "application/vnd.github+json", func(body []byte) error {
+			return json.Unmarshal(body, &raw)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range raw {
+			if p := r.project(); !opts.Excluded(p) && len(projects) < opts.RecentCount {
+				projects = append(projects, p)
+			}
+		}
+		next = nextPageURL(header)
 	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("User-Agent", userAgent)
+	return projects, nil
+}
 
-	resp, err := client.Do(req)
+// getREST performs a GET against the REST API and hands the body to
+// decode, returning the response headers for pagination.
+func (c *Client) getREST(ctx context.Context, rawURL, accept string, decode func([]byte) error) (http.Header, error) {
+	res, err := c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create rest request: %w", err)
+		}
+		req.Header.Set("Accept", accept)
+		req.Header.Set("User-Agent", userAgent)
+		if err := c.authorize(req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("perform rest request: %w", err)
+		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected github rest status: %s", resp.Status)
+	if err := decode(res.body); err != nil {
+		return nil, fmt.Errorf("decode rest response: %w", err)
 	}
+	return res.header, nil
+}
 
-	var raw []restProject
-	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
-		return nil, fmt.Errorf("decode rest response: %w", err)
+// nextPageURL returns the rel="next" target of a REST Link header, e.g.
+// `<https://api.github.com/user/1/repos?page=2>; rel="next", <…>; rel="last"`.
+func nextPageURL(header http.Header) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
 	}
+	return ""
+}
 
-	projects := make([]Project, 0, len(raw))
-	for _, r := range raw {
-		projects = append(projects, Project{
-			Name:        r.Name,
-			Description: r.Description,
-			URL:         r.HTMLURL,
-			Language:    r.Language,
-			Stars:       r.Stars,
-			Forks:       r.Forks,
-			UpdatedAt:   r.UpdatedAt,
-			HomepageURL: r.Homepage,
-		})
+// project converts a REST repository to a Project.
+func (r restProject) project() Project {
+	return Project{
+		Source:      SourceGitHub,
+		Forge:       ForgeGitHub,
+		Name:        r.Name,
+		Owner:       r.Owner.Login,
+		Description: r.Description,
+		URL:         r.HTMLURL,
+		Language:    r.Language,
+		Stars:       r.Stars,
+		Forks:       r.Forks,
+		UpdatedAt:   r.UpdatedAt,
+		HomepageURL: r.Homepage,
+		Topics:      r.Topics,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
 	}
-	return projects, nil
 }
@@ -0,0 +1,180 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// handlerTransport serves requests from an in-process handler so tests
+// exercise the real API URLs without touching the network.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	t.handler.ServeHTTP(w, req)
+	return w.Result(), nil
+}
+
+func testClient(h http.HandlerFunc) *http.Client {
+	return &http.Client{Transport: handlerTransport{h}}
+}
+
+func gqlRepo(owner, name string, fork bool) map[string]any {
+	return map[string]any{
+		"name":             name,
+		"owner":            map[string]any{"login": owner},
+		"url":              "https://github.com/" + owner + "/" + name,
+		"isFork":           fork,
+		"repositoryTopics": map[string]any{"nodes": []any{}},
+	}
+}
+
+func TestFetchProjectsPaginatesPinnedItemsAndAddsExtras(t *testing.T) {
+	var pinnedRequests []map[string]any
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		switch {
+		case strings.Contains(req.Query, "pinnedItems"):
+			pinnedRequests = append(pinnedRequests, req.Variables)
+			one := gqlRepo("HexSleeves", "one", false)
+			one["openGraphImageUrl"] = "https://repository-images.githubusercontent.com/1/preview"
+			one["usesCustomOpenGraphImage"] = true
+			page := map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": true, "endCursor": "c1"},
+				"nodes":    []any{one, gqlRepo("HexSleeves", "forked", true)},
+			}
+			if req.Variables["after"] == "c1" {
+				page = map[string]any{
+					"pageInfo": map[string]any{"hasNextPage": false},
+					"nodes":    []any{gqlRepo("HexSleeves", "two", false), gqlRepo("HexSleeves", "dotfiles", false)},
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": map[string]any{"pinnedItems": page}}})
+		default:
+			if req.Variables["o0"] != "some-org" || req.Variables["n1"] != "gone" {
+				t.Fatalf("unexpected extra repo variables: %v", req.Variables)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data":   map[string]any{"r0": gqlRepo("some-org", "tool", false), "r1": nil, "r2": gqlRepo("HexSleeves", "one", false)},
+				"errors": []any{map[string]any{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}},
+			})
+		}
+	})
+
+	projects, err := NewClient(client, "token").FetchProjects(context.Background(), "HexSleeves", FetchOptions{
+		PinnedCount:  3,
+		PageSize:     2,
+		ExtraRepos:   []string{"some-org/tool", "some-org/gone", "HexSleeves/one"},
+		Exclude:      []string{"dot*"},
+		ExcludeForks: true,
+	})
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Owner+"/"+p.Name)
+	}
+	if got, want := strings.Join(names, ","), "HexSleeves/one,HexSleeves/two,some-org/tool"; got != want {
+		t.Fatalf("expected projects %s, got %s", want, got)
+	}
+	if p := projects[0]; !p.CustomOpenGraphImage || p.OpenGraphImage != "https://repository-images.githubusercontent.com/1/preview" {
+		t.Fatalf("expected the social preview image, got %+v", p)
+	}
+	if len(pinnedRequests) != 2 {
+		t.Fatalf("expected two pinned pages, got %d", len(pinnedRequests))
+	}
+	if pinnedRequests[0]["first"] != float64(2) || pinnedRequests[0]["topics"] != float64(DefaultTopicCount) {
+		t.Fatalf("unexpected first page variables: %v", pinnedRequests[0])
+	}
+}
+
+func TestFetchProjectsFollowsRESTLinkHeader(t *testing.T) {
+	pages := 0
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			if got := r.URL.Query().Get("per_page"); got != "2" {
+				t.Fatalf("expected per_page=2, got %q", got)
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/user/1/repos?page=2&per_page=2>; rel="next", <%s/user/1/repos?page=9>; rel="last"`, restURL, restURL))
+			_, _ = w.Write([]byte(`[{"name":"a","owner":{"login":"u"}},{"name":"b","owner":{"login":"u"},"archived":true}]`))
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/user/1/repos?page=3>; rel="next"`, restURL))
+		_, _ = w.Write([]byte(`[{"name":"c","owner":{"login":"u"}},{"name":"d","owner":{"login":"u"}}]`))
+	})
+
+	projects, err := NewClient(client, "").FetchProjects(context.Background(), "u", FetchOptions{
+		RecentCount:     3,
+		PageSize:        2,
+		ExcludeArchived: true,
+	})
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	if len(projects) != 3 || projects[0].Name != "a" || projects[1].Name != "c" || projects[2].Name != "d" {
+		t.Fatalf("unexpected projects: %+v", projects)
+	}
+	if pages != 2 {
+		t.Fatalf("expected pagination to stop once enough projects were collected, made %d requests", pages)
+	}
+}
+
+func TestNextPageURL(t *testing.T) {
+	h := http.Header{}
+	h.Set("Link", `<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=3>; rel="next"`)
+	if got := nextPageURL(h); got != "https://api.github.com/x?page=3" {
+		t.Fatalf("unexpected next URL %q", got)
+	}
+	if got := nextPageURL(http.Header{}); got != "" {
+		t.Fatalf("expected no next URL without a Link header, got %q", got)
+	}
+}
+
+func TestValidateFetchOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    FetchOptions
+		wantErr bool
+	}{
+		{"defaults", FetchOptions{}, false},
+		{"extra repo", FetchOptions{ExtraRepos: []string{"org/repo"}}, false},
+		{"missing owner", FetchOptions{ExtraRepos: []string{"repo"}}, true},
+		{"bad pattern", FetchOptions{Exclude: []string{"[abc"}}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateFetchOptions(tt.opts); (err != nil) != tt.wantErr {
+			t.Fatalf("%s: ValidateFetchOptions error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestProjectKey(t *testing.T) {
+	tests := []struct {
+		p    Project
+		want string
+	}{
+		{Project{Forge: ForgeGitHub, Owner: "HexSleeves", Name: "runeforge"}, "github/HexSleeves/runeforge"},
+		{Project{Owner: "acme", Name: "runeforge"}, "github/acme/runeforge"},
+		{Project{Forge: "gitlab", Source: "GitHub", Owner: "hex", Name: "runeforge"}, "gitlab/hex/runeforge"},
+		{Project{Manual: true, Name: "portal"}, "manual/portal"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Key(); got != tt.want {
+			t.Fatalf("Key() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package githubapi
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// languageStatsQuery lists the user's own, non-fork repositories with their
+// language breakdown, for AggregateLanguages.
+const languageStatsQuery = `
+query($login: String!, $first: Int!, $after: String, $languages: Int!) {
+  user(login: $login) {
+    repositories(first: $first, after: $after, ownerAffiliations: OWNER, isFork: false, orderBy: {field: PUSHED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        owner { login }
+        isFork
+        isArchived
+        languages(first: $languages, orderBy: {field: SIZE, direction: DESC}) {
+          edges { size node { name color } }
+        }
+      }
+    }
+  }
+}
+`
+
+// defaultLanguageColor is used for languages GitHub assigns no colour.
+const defaultLanguageColor = "#8b8b8b"
+
+// Language is the amount of code in one language, as measured by GitHub's
+// linguist.
+type Language struct {
+	Name string `json:"name"`
+	// Color is GitHub's colour for the language, e.g. "#00ADD8" for Go.
+	Color string `json:"color"`
+	Bytes int64  `json:"bytes"`
+}
+
+// LanguageShare is a language with its share of a total, for drawing bars.
+type LanguageShare struct {
+	Language
+	// Percent is the share of the total, from 0 to 100.
+	Percent float64 `json:"percent"`
+}
+
+// LanguageShares returns the project's languages with their share of the
+// listed total, largest first. It is empty when the breakdown is unknown,
+// as it is for projects fetched over REST.
+func (p Project) LanguageShares() []LanguageShare {
+	return shares(p.Languages)
+}
+
+// AggregateLanguages sums the language breakdown of projects, largest
+// first.
+func AggregateLanguages(projects []Project) []LanguageShare {
+	totals := make(map[string]*Language)
+	var order []*Language
+	for _, p := range projects {
+		for _, l := range p.Languages {
+			t, ok := totals[l.Name]
+			if !ok {
+				t = &Language{Name: l.Name, Color: l.Color}
+				totals[l.Name] = t
+				order = append(order, t)
+			}
+			t.Bytes += l.Bytes
+		}
+	}
+	langs := make([]Language, len(order))
+	for i, t := range order {
+		langs[i] = *t
+	}
+	return shares(langs)
+}
+
+func shares(langs []Language) []LanguageShare {
+	var total int64
+	for _, l := range langs {
+		total += l.Bytes
+	}
+	if total <= 0 {
+		return nil
+	}
+	out := make([]LanguageShare, 0, len(langs))
+	for _, l := range langs {
+		if l.Color == "" {
+			l.Color = defaultLanguageColor
+		}
+		out = append(out, LanguageShare{Language: l, Percent: float64(l.Bytes) * 100 / float64(total)})
+	}
+	slices.SortStableFunc(out, func(a, b LanguageShare) int {
+		return cmp.Compare(b.Bytes, a.Bytes)
+	})
+	return out
+}
+
+// FetchLanguageStats returns the bytes-by-language breakdown across the
+// user's own repositories, skipping forks and anything opts excludes. The
+// breakdown is only available over GraphQL, so without a token it returns
+// nil rather than spending a REST request per repository.
+func (c *Client) FetchLanguageStats(ctx context.Context, username string, opts FetchOptions) ([]LanguageShare, error) {
+	if c.tokens == nil {
+		return nil, nil
+	}
+	opts = opts.withDefaults()
+
+	var projects []Project
+	var after *string
+	for page := 0; page < opts.MaxPages; page++ {
+		data, err := c.queryGraphQL(ctx, languageStatsQuery, map[string]any{
+			"login":     username,
+			"first":     opts.pageSize(maxPageSize),
+			"after":     after,
+			"languages": opts.LanguageCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if data.User == nil {
+			return nil, &APIError{Err: ErrNotFound, Message: "user " + username}
+		}
+
+		repos := data.User.Repositories
+		for _, n := range repos.Nodes {
+			if p := n.project(); !opts.Excluded(p) {
+				projects = append(projects, p)
+			}
+		}
+		if !repos.PageInfo.HasNextPage {
+			break
+		}
+		cursor := repos.PageInfo.EndCursor
+		after = &cursor
+	}
+	return AggregateLanguages(projects), nil
+}
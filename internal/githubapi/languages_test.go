@@ -0,0 +1,85 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestAggregateLanguages(t *testing.T) {
+	projects := []Project{
+		{Name: "a", Languages: []Language{{Name: "Go", Color: "#00ADD8", Bytes: 600}, {Name: "Shell", Bytes: 100}}},
+		{Name: "b", Languages: []Language{{Name: "TypeScript", Color: "#3178c6", Bytes: 200}, {Name: "Go", Color: "#00ADD8", Bytes: 100}}},
+		{Name: "rest-only", Language: "Python"},
+	}
+
+	got := AggregateLanguages(projects)
+	want := []struct {
+		name    string
+		color   string
+		bytes   int64
+		percent float64
+	}{
+		{"Go", "#00ADD8", 700, 70},
+		{"TypeScript", "#3178c6", 200, 20},
+		{"Shell", defaultLanguageColor, 100, 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AggregateLanguages returned %+v", got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Name != w.name || g.Color != w.color || g.Bytes != w.bytes || math.Abs(g.Percent-w.percent) > 1e-9 {
+			t.Fatalf("language %d = %+v, want %+v", i, g, w)
+		}
+	}
+
+	if shares := (Project{Language: "Go"}).LanguageShares(); shares != nil {
+		t.Fatalf("expected no shares without a breakdown, got %+v", shares)
+	}
+}
+
+func TestFetchLanguageStatsPaginatesAndSkipsExcluded(t *testing.T) {
+	page := 0
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		page++
+		repo := func(name string, archived bool, size int64) map[string]any {
+			return map[string]any{
+				"name":       name,
+				"owner":      map[string]any{"login": "u"},
+				"isArchived": archived,
+				"languages": map[string]any{"edges": []any{
+					map[string]any{"size": size, "node": map[string]any{"name": "Go", "color": "#00ADD8"}},
+				}},
+			}
+		}
+		nodes := []any{repo("one", false, 10), repo("old", true, 1000)}
+		if page == 2 {
+			nodes = []any{repo("two", false, 30)}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": map[string]any{
+			"repositories": map[string]any{
+				"pageInfo": map[string]any{"hasNextPage": page == 1, "endCursor": "c1"},
+				"nodes":    nodes,
+			},
+		}}})
+	})
+
+	stats, err := NewClient(client, "token").FetchLanguageStats(context.Background(), "u", FetchOptions{ExcludeArchived: true})
+	if err != nil {
+		t.Fatalf("FetchLanguageStats returned error: %v", err)
+	}
+	if page != 2 {
+		t.Fatalf("expected 2 pages to be requested, got %d", page)
+	}
+	if len(stats) != 1 || stats[0].Bytes != 40 || stats[0].Percent != 100 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	stats, err = NewClient(client, "").FetchLanguageStats(context.Background(), "u", FetchOptions{})
+	if err != nil || stats != nil {
+		t.Fatalf("expected no stats without a token, got %+v, %v", stats, err)
+	}
+}
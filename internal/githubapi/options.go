@@ -0,0 +1,215 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Defaults used when the corresponding FetchOptions field is zero. They
+// match what the showcase has always shown.
+const (
+	DefaultPinnedCount   = 6
+	DefaultRecentCount   = 12
+	DefaultTopicCount    = 5
+	DefaultLanguageCount = 6
+	DefaultMaxPages      = 5
+
+	// maxPageSize is the largest page GitHub returns on either API.
+	maxPageSize = 100
+)
+
+// FetchOptions controls which repositories FetchProjects returns.
+// The zero value is valid and selects the defaults.
+type FetchOptions struct {
+	// PinnedCount is how many pinned repositories to return (GraphQL).
+	PinnedCount int
+	// RecentCount is how many recently updated repositories the REST
+	// fallback returns, since pinned items are only available over GraphQL.
+	RecentCount int
+	// TopicCount is how many topics to fetch per repository (GraphQL).
+	TopicCount int
+	// LanguageCount is how many languages to fetch per repository (GraphQL).
+	LanguageCount int
+	// CommitCount is how many recent commits FetchActivity reads per
+	// repository.
+	CommitCount int
+
+	// ExtraRepos lists "owner/repo" entries to include after the pinned
+	// ones, e.g. repositories owned by an organisation.
+	ExtraRepos []string
+
+	// Exclude lists path.Match patterns checked against both "repo" and
+	// "owner/repo", e.g. "dotfiles" or "HexSleeves/*-archive".
+	Exclude []string
+	// ExcludeForks and ExcludeArchived drop forks and archived repositories.
+	ExcludeForks    bool
+	ExcludeArchived bool
+
+	// PageSize is how many items to request per page; zero requests as
+	// many as are needed, up to GitHub's limit of 100.
+	PageSize int
+	// MaxPages caps the requests made while paginating.
+	MaxPages int
+}
+
+func (o FetchOptions) withDefaults() FetchOptions {
+	if o.PinnedCount <= 0 {
+		o.PinnedCount = DefaultPinnedCount
+	}
+	if o.RecentCount <= 0 {
+		o.RecentCount = DefaultRecentCount
+	}
+	if o.TopicCount <= 0 {
+		o.TopicCount = DefaultTopicCount
+	}
+	if o.LanguageCount <= 0 {
+		o.LanguageCount = DefaultLanguageCount
+	}
+	if o.CommitCount <= 0 {
+		o.CommitCount = DefaultCommitCount
+	}
+	if o.MaxPages <= 0 {
+		o.MaxPages = DefaultMaxPages
+	}
+	return o
+}
+
+// pageSize returns the page size to request when remaining items are wanted.
+func (o FetchOptions) pageSize(remaining int) int {
+	n := remaining
+	if o.PageSize > 0 && o.PageSize < n {
+		n = o.PageSize
+	}
+	return max(1, min(n, maxPageSize))
+}
+
+// Excluded reports whether p matches one of the exclusion rules.
+func (o FetchOptions) Excluded(p Project) bool {
+	if (o.ExcludeForks && p.Fork) || (o.ExcludeArchived && p.Archived) {
+		return true
+	}
+	full := p.Owner + "/" + p.Name
+	for _, pattern := range o.Exclude {
+		if matchFold(pattern, p.Name) || matchFold(pattern, full) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFold is path.Match ignoring case, as GitHub names are.
+func matchFold(pattern, name string) bool {
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(name))
+	return err == nil && ok
+}
+
+// ValidateFetchOptions reports malformed extra repositories or exclusion
+// patterns, so configuration mistakes surface at load time.
+func ValidateFetchOptions(o FetchOptions) error {
+	if _, err := parseRepoRefs(o.ExtraRepos); err != nil {
+		return err
+	}
+	for _, pattern := range o.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+type repoRef struct {
+	owner, name string
+}
+
+func parseRepoRefs(refs []string) ([]repoRef, error) {
+	out := make([]repoRef, 0, len(refs))
+	for _, ref := range refs {
+		owner, name, ok := strings.Cut(strings.TrimSpace(ref), "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid extra repo %q: want owner/repo", ref)
+		}
+		out = append(out, repoRef{owner: owner, name: name})
+	}
+	return out, nil
+}
+
+// fetchGraphQLRepos fetches the extra repositories in a single query, one
+// aliased repository field each. Repositories that no longer exist are
+// skipped rather than failing the whole showcase.
+func (c *Client) fetchGraphQLRepos(ctx context.Context, refs []repoRef, opts FetchOptions) ([]Project, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	var decls, fields strings.Builder
+	vars := map[string]any{"topics": opts.TopicCount, "languages": opts.LanguageCount}
+	for i, ref := range refs {
+		fmt.Fprintf(&decls, ", $o%d: String!, $n%d: String!", i, i)
+		fmt.Fprintf(&fields, "  r%d: repository(owner: $o%d, name: $n%d) { ...repoFields }\n", i, i, i)
+		vars[fmt.Sprintf("o%d", i)] = ref.owner
+		vars[fmt.Sprintf("n%d", i)] = ref.name
+	}
+	query := fmt.Sprintf("query($topics: Int!, $languages: Int!%s) {\n%s}\n%s", decls.String(), fields.String(), repoFields)
+
+	var data map[string]*graphqlRepo
+	gqlErrs, err := c.postGraphQL(ctx, query, vars, &data)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range gqlErrs {
+		if e.Type != "NOT_FOUND" {
+			return nil, e.err()
+		}
+	}
+
+	var projects []Project
+	for i := range refs {
+		if n := data[fmt.Sprintf("r%d", i)]; n != nil {
+			projects = append(projects, n.project())
+		}
+	}
+	return projects, nil
+}
+
+// fetchRESTRepos fetches the extra repositories one request at a time,
+// skipping any that no longer exist.
+func (c *Client) fetchRESTRepos(ctx context.Context, refs []repoRef) ([]Project, error) {
+	var projects []Project
+	for _, ref := range refs {
+		var raw restProject
+		repoURL := fmt.Sprintf("%s/repos/%s/%s", restURL, url.PathEscape(ref.owner), url.PathEscape(ref.name))
+		_, err := c.getREST(ctx, repoURL, "application/vnd.github+json", func(body []byte) error {
+			return json.Unmarshal(body, &raw)
+		})
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, raw.project())
+	}
+	return projects, nil
+}
+
+// mergeProjects appends extras to the base list, dropping excluded
+// repositories and any already present.
+func mergeProjects(base, extras []Project, opts FetchOptions) []Project {
+	seen := make(map[string]bool, len(base)+len(extras))
+	for _, p := range base {
+		seen[strings.ToLower(p.Owner+"/"+p.Name)] = true
+	}
+	for _, p := range extras {
+		key := strings.ToLower(p.Owner + "/" + p.Name)
+		if seen[key] || opts.Excluded(p) {
+			continue
+		}
+		seen[key] = true
+		base = append(base, p)
+	}
+	return base
+}
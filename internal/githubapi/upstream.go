@@ -0,0 +1,138 @@
+package githubapi
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxUpstreamPullRequests is how many merged pull requests are read, the
+// most recently updated first. It is one page of search results.
+const maxUpstreamPullRequests = 100
+
+const upstreamQuery = `
+query($q: String!, $first: Int!) {
+  search(query: $q, type: ISSUE, first: $first) {
+    nodes {
+      ... on PullRequest {
+        title
+        number
+        url
+        mergedAt
+        repository {
+          nameWithOwner
+          url
+          description
+          stargazerCount
+          isPrivate
+          owner { login }
+        }
+      }
+    }
+  }
+}
+`
+
+// UpstreamRepo is a repository owned by someone else that the user has had
+// pull requests merged into.
+type UpstreamRepo struct {
+	// Name is "owner/name".
+	Name         string        `json:"name"`
+	URL          string        `json:"url"`
+	Description  string        `json:"description,omitempty"`
+	Stars        int           `json:"stars"`
+	PullRequests []PullRequest `json:"pullRequests"`
+}
+
+// PullRequest is a merged pull request.
+type PullRequest struct {
+	Title    string    `json:"title"`
+	Number   int       `json:"number"`
+	URL      string    `json:"url"`
+	MergedAt time.Time `json:"mergedAt"`
+}
+
+// DisplayDate renders the merge date, e.g. "Jan 2, 2006".
+func (p PullRequest) DisplayDate() string {
+	return p.MergedAt.Format("Jan 2, 2006")
+}
+
+type graphqlUpstream struct {
+	Search struct {
+		Nodes []struct {
+			Title      string    `json:"title"`
+			Number     int       `json:"number"`
+			URL        string    `json:"url"`
+			MergedAt   time.Time `json:"mergedAt"`
+			Repository *struct {
+				NameWithOwner  string       `json:"nameWithOwner"`
+				URL            string       `json:"url"`
+				Description    string       `json:"description"`
+				StargazerCount int          `json:"stargazerCount"`
+				IsPrivate      bool         `json:"isPrivate"`
+				Owner          graphqlOwner `json:"owner"`
+			} `json:"repository"`
+		} `json:"nodes"`
+	} `json:"search"`
+}
+
+// FetchUpstreamContributions returns the public repositories outside the
+// user's account that they have had pull requests merged into, grouped by
+// repository, with the most starred first and each repository's pull
+// requests newest first. Search is only available over GraphQL, so without
+// a token it returns nil.
+func (c *Client) FetchUpstreamContributions(ctx context.Context, username string) ([]UpstreamRepo, error) {
+	if c.tokens == nil {
+		return nil, nil
+	}
+	var data graphqlUpstream
+	gqlErrs, err := c.postGraphQL(ctx, upstreamQuery, map[string]any{
+		"q":     "is:pr is:merged is:public author:" + username + " -user:" + username + " sort:updated-desc",
+		"first": maxUpstreamPullRequests,
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+	if len(gqlErrs) > 0 {
+		return nil, gqlErrs[0].err()
+	}
+
+	var repos []UpstreamRepo
+	index := make(map[string]int)
+	for _, n := range data.Search.Nodes {
+		r := n.Repository
+		// Non-PR results decode empty. The owner check backs up -user:.
+		if r == nil || n.MergedAt.IsZero() || r.IsPrivate || strings.EqualFold(r.Owner.Login, username) {
+			continue
+		}
+		i, ok := index[r.NameWithOwner]
+		if !ok {
+			i = len(repos)
+			index[r.NameWithOwner] = i
+			repos = append(repos, UpstreamRepo{
+				Name:        r.NameWithOwner,
+				URL:         r.URL,
+				Description: r.Description,
+				Stars:       r.StargazerCount,
+			})
+		}
+		repos[i].PullRequests = append(repos[i].PullRequests, PullRequest{
+			Title:    n.Title,
+			Number:   n.Number,
+			URL:      n.URL,
+			MergedAt: n.MergedAt,
+		})
+	}
+
+	for _, r := range repos {
+		slices.SortFunc(r.PullRequests, func(a, b PullRequest) int {
+			return b.MergedAt.Compare(a.MergedAt)
+		})
+	}
+	slices.SortStableFunc(repos, func(a, b UpstreamRepo) int {
+		return cmp.Or(cmp.Compare(b.Stars, a.Stars), strings.Compare(a.Name, b.Name))
+	})
+	return repos, nil
+}
@@ -0,0 +1,55 @@
+package githubapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFetchUpstreamContributions(t *testing.T) {
+	var vars map[string]any
+	client := testClient(func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		vars = req.Variables
+		pr := func(repo, owner string, stars, number int, merged string) map[string]any {
+			return map[string]any{
+				"title": fmt.Sprintf("PR %d", number), "number": number,
+				"url": fmt.Sprintf("https://github.com/%s/pull/%d", repo, number), "mergedAt": merged,
+				"repository": map[string]any{
+					"nameWithOwner": repo, "url": "https://github.com/" + repo,
+					"stargazerCount": stars, "owner": map[string]any{"login": owner},
+				},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"search": map[string]any{"nodes": []any{
+			pr("golang/go", "golang", 120000, 1, "2026-01-05T10:00:00Z"),
+			pr("charmbracelet/bubbletea", "charmbracelet", 30000, 7, "2026-02-01T10:00:00Z"),
+			pr("golang/go", "golang", 120000, 2, "2026-03-05T10:00:00Z"),
+			pr("u/mine", "u", 5, 3, "2026-03-06T10:00:00Z"),
+			map[string]any{},
+		}}}})
+	})
+
+	repos, err := NewClient(client, "token").FetchUpstreamContributions(context.Background(), "u")
+	if err != nil {
+		t.Fatalf("FetchUpstreamContributions returned error: %v", err)
+	}
+	if q, _ := vars["q"].(string); !strings.Contains(q, "is:merged") || !strings.Contains(q, "author:u") || !strings.Contains(q, "-user:u") {
+		t.Fatalf("unexpected search query %q", q)
+	}
+	if len(repos) != 2 || repos[0].Name != "golang/go" || repos[1].Name != "charmbracelet/bubbletea" {
+		t.Fatalf("expected upstream repos by stars without the user's own, got %+v", repos)
+	}
+	if prs := repos[0].PullRequests; len(prs) != 2 || prs[0].Number != 2 || prs[0].DisplayDate() != "Mar 5, 2026" {
+		t.Fatalf("expected pull requests newest first, got %+v", prs)
+	}
+
+	repos, err = NewClient(client, "").FetchUpstreamContributions(context.Background(), "u")
+	if repos != nil || err != nil {
+		t.Fatalf("expected nothing without a token, got %+v, %v", repos, err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package imagecache downloads remote images into a local directory under
+// content-hash names, so pages can serve them without hot-linking the
+// original host.
+package imagecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// MaxImageBytes caps the size of a downloaded image.
+const MaxImageBytes = 5 << 20
+
+// indexFile maps source URLs to file names, so images survive restarts
+// and offline builds without being downloaded again.
+const indexFile = "index.json"
+
+// extensions lists the accepted content types. SVG is left out because
+// it can carry scripts.
+var extensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// namePattern matches the file names Get returns.
+var namePattern = regexp.MustCompile(`^[0-9a-f]{64}\.(png|jpg|gif|webp)$`)
+
+// Cache is a directory of downloaded images. It is safe for concurrent
+// use.
+type Cache struct {
+	dir    string
+	client *http.Client
+
+	mu    sync.Mutex
+	index map[string]string
+}
+
+// New opens the cache in dir, creating the directory if needed. With a nil
+// client the cache never downloads and Get only finds cached images.
+func New(dir string, client *http.Client) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	c := &Cache{dir: dir, client: client, index: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(dir, indexFile)) // #nosec G304 -- dir comes from server configuration or a flag.
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &c.index); err != nil {
+			return nil, fmt.Errorf("decode image cache index: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// Dir returns the cache directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// ValidName reports whether name is a file name Get could have returned,
+// so it can be served without exposing anything else in the directory.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Get returns the file name of the image at url, downloading it first if
+// it is not cached. Names are the SHA-256 of the content, so an image
+// changes name whenever it changes and can be cached forever by clients.
+func (c *Cache) Get(ctx context.Context, url string) (string, error) {
+	c.mu.Lock()
+	name, ok := c.index[url]
+	c.mu.Unlock()
+	if ok {
+		if _, err := os.Stat(filepath.Join(c.dir, name)); err == nil {
+			return name, nil
+		}
+	}
+
+	if c.client == nil {
+		return "", fmt.Errorf("image %s is not cached", url)
+	}
+	data, ext, err := c.download(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	name = hex.EncodeToString(sum[:]) + ext
+	if err := writeFile(filepath.Join(c.dir, name), data); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.index[url] = name
+	index, err := json.MarshalIndent(c.index, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(filepath.Join(c.dir, indexFile), index); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// LocalizeProject points p.Image at the cached copy of its custom social
+// preview image, served under prefix (e.g. "/static/projects/"). GitHub's
+// generated previews only repeat the card's text and are skipped, as are
+// projects that already have an image from projects.yaml.
+func (c *Cache) LocalizeProject(ctx context.Context, p *githubapi.Project, prefix string) error {
+	if p.Image != "" || !p.CustomOpenGraphImage || p.OpenGraphImage == "" {
+		return nil
+	}
+	name, err := c.Get(ctx, p.OpenGraphImage)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.Name, err)
+	}
+	p.Image = prefix + name
+	return nil
+}
+
+// LocalizeProjects calls LocalizeProject for each project, returning the
+// failures joined. Projects whose image failed are left without one.
+func (c *Cache) LocalizeProjects(ctx context.Context, projects []githubapi.Project, prefix string) error {
+	var errs []error
+	for i := range projects {
+		if err := c.LocalizeProject(ctx, &projects[i], prefix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// download fetches url and returns its body and the file extension for its
+// content type.
+func (c *Cache) download(ctx context.Context, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("download image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("download image %s: unexpected status %s", url, resp.Status)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	ext, ok := extensions[mediaType]
+	if !ok {
+		return nil, "", fmt.Errorf("download image %s: unsupported content type %q", url, mediaType)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("download image %s: %w", url, err)
+	}
+	if len(data) > MaxImageBytes {
+		return nil, "", fmt.Errorf("download image %s: larger than %d bytes", url, MaxImageBytes)
+	}
+	return data, ext, nil
+}
+
+// writeFile writes data to path through a temporary file, so readers never
+// see a partial file.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
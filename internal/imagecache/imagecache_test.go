@@ -0,0 +1,131 @@
+package imagecache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// pngHeader is enough of a PNG for the content-type checks here.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestGetDownloadsOnceAndSurvivesReopen(t *testing.T) {
+	downloads := 0
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/preview.png":
+			downloads++
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(pngHeader)
+		case "/logo.svg":
+			w.Header().Set("Content-Type", "image/svg+xml")
+			_, _ = w.Write([]byte("<svg/>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer images.Close()
+
+	dir := t.TempDir()
+	cache, err := New(dir, images.Client())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	name, err := cache.Get(context.Background(), images.URL+"/preview.png")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !ValidName(name) || !strings.HasSuffix(name, ".png") {
+		t.Fatalf("Get = %q, want a content-hash .png name", name)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(data) != string(pngHeader) {
+		t.Fatalf("cached file = %q, %v", data, err)
+	}
+
+	// A reopened cache finds the image through its index.
+	cache, err = New(dir, images.Client())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if again, err := cache.Get(context.Background(), images.URL+"/preview.png"); err != nil || again != name || downloads != 1 {
+		t.Fatalf("Get = %q, %v after %d downloads; want the cached %q", again, err, downloads, name)
+	}
+
+	if _, err := cache.Get(context.Background(), images.URL+"/logo.svg"); err == nil {
+		t.Fatal("expected SVG images to be rejected")
+	}
+	if _, err := cache.Get(context.Background(), images.URL+"/missing.png"); err == nil {
+		t.Fatal("expected a 404 to be an error")
+	}
+	if ValidName("index.json") || ValidName("../"+name) {
+		t.Fatal("expected only content-hash names to be valid")
+	}
+}
+
+func TestLocalizeProjects(t *testing.T) {
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/custom.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("jpeg"))
+	}))
+	defer images.Close()
+
+	cache, err := New(t.TempDir(), images.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects := []githubapi.Project{
+		{Name: "custom", OpenGraphImage: images.URL + "/custom.jpg", CustomOpenGraphImage: true},
+		{Name: "generated", OpenGraphImage: images.URL + "/generated.png"},
+		{Name: "override", Image: "/static/images/override.png", OpenGraphImage: images.URL + "/custom.jpg", CustomOpenGraphImage: true},
+		{Name: "broken", OpenGraphImage: images.URL + "/broken.png", CustomOpenGraphImage: true},
+	}
+	err = cache.LocalizeProjects(context.Background(), projects, "/static/projects/")
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("LocalizeProjects error = %v, want the broken image reported", err)
+	}
+	if img := projects[0].Image; !strings.HasPrefix(img, "/static/projects/") || !strings.HasSuffix(img, ".jpg") {
+		t.Fatalf("custom preview Image = %q, want a local path", img)
+	}
+	if projects[1].Image != "" || projects[2].Image != "/static/images/override.png" || projects[3].Image != "" {
+		t.Fatalf("unexpected images %+v", projects)
+	}
+}
+
+func TestGetWithoutClientOnlyReadsCache(t *testing.T) {
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/gif")
+		_, _ = w.Write([]byte("GIF89a"))
+	}))
+	defer images.Close()
+
+	dir := t.TempDir()
+	online, err := New(dir, images.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := online.Get(context.Background(), images.URL+"/a.gif")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offline, err := New(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := offline.Get(context.Background(), images.URL+"/a.gif"); err != nil || got != name {
+		t.Fatalf("Get = %q, %v; want the cached %q", got, err, name)
+	}
+	if _, err := offline.Get(context.Background(), images.URL+"/b.gif"); err == nil {
+		t.Fatal("expected an uncached image to fail without a client")
+	}
+}
@@ -3,10 +3,19 @@
 package pagedata
 
 import (
+	"fmt"
+	"html/template"
+	"strings"
 	"time"
 
+	"srv.exe.dev/internal/analytics"
 	"srv.exe.dev/internal/blog"
+	"srv.exe.dev/internal/charts"
+	"srv.exe.dev/internal/comments"
 	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/resume"
+	"srv.exe.dev/internal/showcase"
+	"srv.exe.dev/internal/webmention"
 )
 
 // PageData holds template variables common to every page.
@@ -17,7 +26,15 @@ type PageData struct {
 	BasePath    string
 
 	// GitHub projects (showcase page)
-	Projects []githubapi.Project
+	Projects      []githubapi.Project
+	Contributions *Contributions
+	Activity      []githubapi.ActivityItem
+	// Upstream lists merged pull requests to other people's repositories.
+	Upstream []githubapi.UpstreamRepo
+	// Filters are the language, topic and sort chips above the projects.
+	Filters *ShowcaseFilters
+	// Trends holds each project's star history, keyed by Project.Key.
+	Trends map[string]*Trend
 
 	// User-facing status messages
 	Info  string
@@ -28,16 +45,362 @@ type PageData struct {
 	OGTitle         string
 	OGType          string // "website" | "article"
 	OGPath          string // page-specific path suffix for og:url
+	NoIndex         bool   // ask search engines not to index the page
+	// WebmentionURL is the endpoint advertised for Webmentions of the
+	// page; only blog posts accept them.
+	WebmentionURL string
 
 	// Footer
 	CopyrightYear int
 }
 
+// RecentActivityCount is how many activity items the showcase page lists;
+// the Atom feed carries them all.
+const RecentActivityCount = 10
+
+// RecentActivity returns the first RecentActivityCount items.
+func RecentActivity(items []githubapi.ActivityItem) []githubapi.ActivityItem {
+	return items[:min(len(items), RecentActivityCount)]
+}
+
+// Contributions is the contribution heatmap shown on the showcase page.
+type Contributions struct {
+	Stats githubapi.ContributionStats
+	SVG   template.HTML
+}
+
+// NewContributions renders cal as a heatmap, or returns nil when there is
+// no calendar to show.
+func NewContributions(cal *githubapi.ContributionCalendar) *Contributions {
+	if cal == nil || len(cal.Days) == 0 {
+		return nil
+	}
+	cells := make([]charts.HeatmapCell, 0, len(cal.Days))
+	for _, d := range cal.Days {
+		date, err := time.Parse(time.DateOnly, d.Date)
+		if err != nil {
+			continue
+		}
+		cells = append(cells, charts.HeatmapCell{Date: date, Count: d.Count, Level: d.Level})
+	}
+	return &Contributions{Stats: cal.Stats(), SVG: charts.Heatmap(cells, "contribution")}
+}
+
+// FilterChip is a link that narrows or reorders the showcase.
+type FilterChip struct {
+	Label string
+	// Count is how many projects the chip matches; zero for sort chips.
+	Count  int
+	URL    string
+	Active bool
+}
+
+// ShowcaseFilters holds the showcase's filter chips.
+type ShowcaseFilters struct {
+	Languages []FilterChip
+	Topics    []FilterChip
+	Sorts     []FilterChip
+	// Active is set when the projects are narrowed or reordered, and
+	// ClearURL then shows them all as fetched.
+	Active   bool
+	ClearURL string
+}
+
+// sortLabels names the showcase sort orders on their chips.
+var sortLabels = map[string]string{
+	"":                   "pinned",
+	showcase.SortStars:   "stars",
+	showcase.SortUpdated: "recently updated",
+	showcase.SortName:    "name",
+}
+
+// NewShowcaseFilters builds chips from the languages and topics of all
+// projects, with f active. link turns a filter into a URL: a query string
+// on the server, a pre-rendered page in the static build. Choosing a
+// language clears the topic and vice versa, so the static build only has
+// to render one of each, and choosing the active one clears it.
+func NewShowcaseFilters(projects []githubapi.Project, f showcase.Filter, link func(showcase.Filter) string) *ShowcaseFilters {
+	languages, topics := showcase.Facets(projects)
+	out := &ShowcaseFilters{Active: !f.IsZero(), ClearURL: link(showcase.Filter{})}
+	for _, l := range languages {
+		active := strings.EqualFold(l.Value, f.Language)
+		next := showcase.Filter{Language: l.Value, Sort: f.Sort}
+		if active {
+			next.Language = ""
+		}
+		out.Languages = append(out.Languages, FilterChip{Label: l.Value, Count: l.Count, URL: link(next), Active: active})
+	}
+	for _, t := range topics {
+		active := strings.EqualFold(t.Value, f.Topic)
+		next := showcase.Filter{Topic: t.Value, Sort: f.Sort}
+		if active {
+			next.Topic = ""
+		}
+		out.Topics = append(out.Topics, FilterChip{Label: t.Value, Count: t.Count, URL: link(next), Active: active})
+	}
+	for _, sort := range showcase.Sorts {
+		next := f
+		next.Sort = sort
+		out.Sorts = append(out.Sorts, FilterChip{Label: sortLabels[sort], URL: link(next), Active: sort == f.Sort})
+	}
+	return out
+}
+
+// HistoryPoint is a project's star and fork count on one day.
+type HistoryPoint struct {
+	Date  string `json:"date"` // YYYY-MM-DD, UTC
+	Stars int    `json:"stars"`
+	Forks int    `json:"forks"`
+}
+
+// Trend is the star and fork movement shown on a showcase card.
+type Trend struct {
+	Stars30, Stars90 int
+	Forks30, Forks90 int
+	SVG              template.HTML
+}
+
+// NewTrend summarises points, in date order, as deltas over the 30 and 90
+// days up to today and a sparkline of stars. Deltas are measured from the
+// first point inside each window, so history younger than the window counts
+// from when recording began. It returns nil with fewer than two points.
+func NewTrend(points []HistoryPoint, today time.Time) *Trend {
+	if len(points) < 2 {
+		return nil
+	}
+	last := points[len(points)-1]
+	delta := func(days int) (stars, forks int) {
+		since := today.AddDate(0, 0, -days).Format(time.DateOnly)
+		for _, p := range points {
+			if p.Date >= since {
+				return last.Stars - p.Stars, last.Forks - p.Forks
+			}
+		}
+		return 0, 0
+	}
+	t := &Trend{}
+	t.Stars30, t.Forks30 = delta(30)
+	t.Stars90, t.Forks90 = delta(90)
+
+	since := today.AddDate(0, 0, -90).Format(time.DateOnly)
+	var stars []int
+	for _, p := range points {
+		if p.Date >= since {
+			stars = append(stars, p.Stars)
+		}
+	}
+	t.SVG = charts.Sparkline(stars, "Stars over the last 90 days")
+	return t
+}
+
 // BlogPageData extends PageData with blog-specific fields.
 type BlogPageData struct {
 	PageData
 	Posts []blog.Post
 	Post  *blog.Post
+	// Comments is nil when the post's comments are not shown at all.
+	Comments *CommentSection
+	// Mentions is nil when the post has no Webmentions.
+	Mentions *MentionSection
+}
+
+// MentionSection is the Webmentions of a post, grouped by type.
+type MentionSection struct {
+	Likes    []webmention.Mention
+	Reposts  []webmention.Mention
+	Replies  []webmention.Mention
+	Mentions []webmention.Mention
+}
+
+// NewMentionSection groups mentions by type, or returns nil when there are
+// none.
+func NewMentionSection(mentions []webmention.Mention) *MentionSection {
+	if len(mentions) == 0 {
+		return nil
+	}
+	var s MentionSection
+	for _, m := range mentions {
+		switch m.Type {
+		case webmention.TypeLike:
+			s.Likes = append(s.Likes, m)
+		case webmention.TypeRepost:
+			s.Reposts = append(s.Reposts, m)
+		case webmention.TypeReply:
+			s.Replies = append(s.Replies, m)
+		default:
+			s.Mentions = append(s.Mentions, m)
+		}
+	}
+	return &s
+}
+
+// CommentSection is the threaded comments under a post.
+type CommentSection struct {
+	Threads []*comments.Comment
+	Count   int
+	// Form is nil where comments are closed, as in the static build.
+	Form *CommentForm
+}
+
+// CommentForm is the form for posting a comment or a reply.
+type CommentForm struct {
+	Action string
+	// Token is the signed time the form was rendered.
+	Token string
+	// ReplyTo is the comment being answered, if any.
+	ReplyTo *comments.Comment
+	// Author and Body refill the form after a rejected submission.
+	Author string
+	Body   string
+	Notice string
+	Error  string
+}
+
+// NewCommentSection wraps threads with form, pointing each comment's
+// ReplyURL at the form when there is one.
+func NewCommentSection(threads []*comments.Comment, form *CommentForm) *CommentSection {
+	if form != nil {
+		comments.Walk(threads, func(c *comments.Comment) {
+			c.ReplyURL = fmt.Sprintf("?reply=%d#comment-form", c.ID)
+		})
+	}
+	return &CommentSection{Threads: threads, Count: comments.Count(threads), Form: form}
+}
+
+// ModerationPageData extends PageData with the comments awaiting approval.
+type ModerationPageData struct {
+	PageData
+	Pending []PendingComment
+}
+
+// PendingComment is a comment in the moderation queue.
+type PendingComment struct {
+	*comments.Comment
+	// IP is the address the comment was posted from.
+	IP string
+}
+
+// StatsPageData extends PageData with the analytics dashboard.
+type StatsPageData struct {
+	PageData
+	Range analytics.Range
+	// Presets link to common ranges ending today.
+	Presets   []StatsPreset
+	Traffic   Traffic
+	Pages     []StatsRow
+	Referrers []StatsRow
+	Campaigns []CampaignRow
+	Live      LiveStats
+	// RetentionDays is how long campaigns are kept before views are
+	// rolled up into daily totals.
+	RetentionDays int
+}
+
+// StatsPreset links to a common date range.
+type StatsPreset struct {
+	Label  string
+	URL    string
+	Active bool
+}
+
+// DailyStats is one day of page views.
+type DailyStats struct {
+	Date     string // YYYY-MM-DD, UTC
+	Views    int
+	Visitors int
+}
+
+// Traffic summarises page views over a range. Visitor IDs change daily,
+// so Visitors is the sum of each day's unique visitors.
+type Traffic struct {
+	Views    int
+	Visitors int
+	SVG      template.HTML
+}
+
+// NewTraffic totals days, in date order, and charts views and visitors
+// for every day of r, counting days without views as zero.
+func NewTraffic(days []DailyStats, r analytics.Range) Traffic {
+	byDate := make(map[string]DailyStats, len(days))
+	var t Traffic
+	for _, d := range days {
+		byDate[d.Date] = d
+		t.Views += d.Views
+		t.Visitors += d.Visitors
+	}
+	dates := r.Dates()
+	labels := make([]string, len(dates))
+	views := make([]int, len(dates))
+	visitors := make([]int, len(dates))
+	for i, date := range dates {
+		if d, err := time.Parse(time.DateOnly, date); err == nil {
+			labels[i] = d.Format("Jan 2, 2006")
+		}
+		views[i] = byDate[date].Views
+		visitors[i] = byDate[date].Visitors
+	}
+	t.SVG = charts.LineChart(labels, []charts.Series{
+		{Unit: "view", Values: views},
+		{Unit: "visitor", Values: visitors},
+	}, fmt.Sprintf("Page views and visitors per day, %s to %s",
+		r.From.Format("Jan 2, 2006"), r.To.Format("Jan 2, 2006")))
+	return t
+}
+
+// StatsRow is a page or referrer with its view and visitor counts.
+type StatsRow struct {
+	Label    string `json:"label"`
+	Views    int    `json:"views"`
+	Visitors int    `json:"visitors"`
+}
+
+// CampaignRow is a combination of UTM parameters with its view and
+// visitor counts.
+type CampaignRow struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Views    int
+	Visitors int
+}
+
+// LiveStats is the realtime panel: visitors in the last few minutes and
+// the pages they viewed.
+type LiveStats struct {
+	Visitors int        `json:"visitors"`
+	Views    int        `json:"views"`
+	Pages    []StatsRow `json:"pages"`
+}
+
+// ResumePageData extends PageData with the structured resume.
+type ResumePageData struct {
+	PageData
+	Resume *resume.Resume
+	// Variant is set when rendering a role-focused variant.
+	Variant *resume.Variant
+	// ExportBase is the path, without extension, of the matching
+	// downloadable exports, e.g. "/resume" for /resume.pdf.
+	ExportBase string
+	// Languages is the optional bytes-by-language breakdown of the
+	// user's GitHub repositories, shown with the skills.
+	Languages []githubapi.LanguageShare
+}
+
+// ProjectPageData extends PageData with a single project and its README.
+type ProjectPageData struct {
+	PageData
+	Project *githubapi.ProjectDetail
+	README  template.HTML
+}
+
+// NewProjectPageData renders the project's README, with relative links
+// pointed back at GitHub, and wraps it with pd.
+func NewProjectPageData(pd PageData, project *githubapi.ProjectDetail) ProjectPageData {
+	data := ProjectPageData{PageData: pd, Project: project}
+	if project != nil && project.README != "" {
+		data.README = blog.RenderMarkdownWith([]byte(project.README), project.ResolveReadmeLink)
+	}
+	return data
 }
 
 // NewPageData returns a PageData with sensible defaults applied.
@@ -0,0 +1,190 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font is a parsed TrueType font that can be measured and embedded.
+// A Font is safe for concurrent use and may be shared between documents.
+type Font struct {
+	src  []byte
+	sfnt *sfnt.Font
+	name string
+	upem float64
+
+	// Font-wide metrics in PDF glyph space (1/1000 of the font size).
+	ascent, descent, capHeight float64
+	bbox                       [4]float64
+	italicAngle                float64
+	fixedPitch                 bool
+
+	mu       sync.Mutex
+	buf      sfnt.Buffer
+	glyphs   map[rune]sfnt.GlyphIndex
+	advances map[sfnt.GlyphIndex]float64
+}
+
+// ParseFont parses TrueType data for use in documents.
+func ParseFont(ttf []byte) (*Font, error) {
+	f, err := sfnt.Parse(ttf)
+	if err != nil {
+		return nil, fmt.Errorf("parse font: %w", err)
+	}
+
+	fnt := &Font{
+		src:      ttf,
+		sfnt:     f,
+		upem:     float64(f.UnitsPerEm()),
+		glyphs:   make(map[rune]sfnt.GlyphIndex),
+		advances: make(map[sfnt.GlyphIndex]float64),
+	}
+
+	name, err := f.Name(&fnt.buf, sfnt.NameIDPostScript)
+	if err != nil || name == "" {
+		name = "EmbeddedFont"
+	}
+	fnt.name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	ppem := fixed.Int26_6(f.UnitsPerEm()) << 6
+	m, err := f.Metrics(&fnt.buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("read font metrics: %w", err)
+	}
+	fnt.ascent = fnt.glyphSpace(m.Ascent)
+	fnt.descent = -fnt.glyphSpace(m.Descent)
+	fnt.capHeight = fnt.glyphSpace(m.CapHeight)
+	if fnt.capHeight == 0 {
+		fnt.capHeight = fnt.ascent
+	}
+
+	b, err := f.Bounds(&fnt.buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("read font bounds: %w", err)
+	}
+	// sfnt's Y axis increases down; PDF's increases up.
+	fnt.bbox = [4]float64{
+		fnt.glyphSpace(b.Min.X), -fnt.glyphSpace(b.Max.Y),
+		fnt.glyphSpace(b.Max.X), -fnt.glyphSpace(b.Min.Y),
+	}
+	if post := f.PostTable(); post != nil {
+		fnt.italicAngle = post.ItalicAngle
+		fnt.fixedPitch = post.IsFixedPitch
+	}
+	return fnt, nil
+}
+
+// MustParseFont is like ParseFont but panics on error. It is intended for
+// package-level variables initialised from embedded font data.
+func MustParseFont(ttf []byte) *Font {
+	f, err := ParseFont(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Name returns the font's PostScript name.
+func (f *Font) Name() string {
+	return f.name
+}
+
+// Width returns the advance width of s, in points, when set at size.
+func (f *Font) Width(s string, size float64) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var total float64
+	for _, r := range s {
+		_, g := f.drawableLocked(r)
+		total += f.advanceLocked(g)
+	}
+	return total * size / 1000
+}
+
+// Ascent returns the distance from the baseline to the top of the tallest
+// glyphs, in points, when set at size.
+func (f *Font) Ascent(size float64) float64 {
+	return f.ascent * size / 1000
+}
+
+// Descent returns the (negative) distance from the baseline to the bottom of
+// the lowest glyphs, in points, when set at size.
+func (f *Font) Descent(size float64) float64 {
+	return f.descent * size / 1000
+}
+
+// encode maps s to glyph indices, substituting '?' for runes the font
+// cannot draw, and reports which rune each glyph stands for.
+func (f *Font) encode(s string, used map[sfnt.GlyphIndex]rune) []sfnt.GlyphIndex {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := make([]sfnt.GlyphIndex, 0, len(s))
+	for _, r := range s {
+		r, g := f.drawableLocked(r)
+		if _, ok := used[g]; !ok {
+			used[g] = r
+		}
+		f.advanceLocked(g)
+		out = append(out, g)
+	}
+	return out
+}
+
+// advance returns a glyph's cached advance width in glyph space.
+func (f *Font) advance(g sfnt.GlyphIndex) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.advanceLocked(g)
+}
+
+// drawableLocked returns the glyph for r, falling back to '?' when the font
+// has no glyph for it.
+func (f *Font) drawableLocked(r rune) (rune, sfnt.GlyphIndex) {
+	if g := f.glyphLocked(r); g != 0 || r == '?' {
+		return r, g
+	}
+	return '?', f.glyphLocked('?')
+}
+
+func (f *Font) glyphLocked(r rune) sfnt.GlyphIndex {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	g, err := f.sfnt.GlyphIndex(&f.buf, r)
+	if err != nil {
+		g = 0
+	}
+	f.glyphs[r] = g
+	return g
+}
+
+func (f *Font) advanceLocked(g sfnt.GlyphIndex) float64 {
+	if adv, ok := f.advances[g]; ok {
+		return adv
+	}
+	ppem := fixed.Int26_6(f.sfnt.UnitsPerEm()) << 6
+	adv, err := f.sfnt.GlyphAdvance(&f.buf, g, ppem, font.HintingNone)
+	w := 0.0
+	if err == nil {
+		w = f.glyphSpace(adv)
+	}
+	f.advances[g] = w
+	return w
+}
+
+// glyphSpace converts a 26.6 value measured in font units to PDF glyph space.
+func (f *Font) glyphSpace(v fixed.Int26_6) float64 {
+	return float64(v) / 64 * 1000 / f.upem
+}
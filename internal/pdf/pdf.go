@@ -0,0 +1,414 @@
+// Package pdf is a small, pure-Go PDF 1.7 writer. It supports
+// embedded TrueType fonts (so text is selectable and searchable), solid
+// lines and rectangles, fill colours and clickable URI links, which is all
+// the resume layout needs.
+//
+// Coordinates are in points measured from the top-left corner of the page,
+// and text is positioned by its baseline.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// Common page sizes, in points.
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+)
+
+// Document is an in-memory PDF document.
+type Document struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords string
+	// Created is written to the document info when non-zero. Leave it unset
+	// for byte-for-byte reproducible output.
+	Created time.Time
+
+	width, height float64
+	pages         []*Page
+	fonts         []*fontUse
+}
+
+// fontUse tracks which glyphs of a font a document draws, for its widths
+// array and ToUnicode map.
+type fontUse struct {
+	font   *Font
+	name   string
+	glyphs map[sfnt.GlyphIndex]rune
+}
+
+// Page is a single page of a Document.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	links   []link
+}
+
+type link struct {
+	x, y, w, h float64
+	uri        string
+}
+
+// New returns an empty document whose pages are width × height points.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Width returns the page width in points.
+func (d *Document) Width() float64 { return d.width }
+
+// Height returns the page height in points.
+func (d *Document) Height() float64 { return d.height }
+
+// Pages returns the document's pages in order.
+func (d *Document) Pages() []*Page { return d.pages }
+
+// AddPage appends a blank page and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+func (d *Document) use(f *Font) *fontUse {
+	for _, u := range d.fonts {
+		if u.font == f {
+			return u
+		}
+	}
+	u := &fontUse{
+		font:   f,
+		name:   fmt.Sprintf("F%d", len(d.fonts)+1),
+		glyphs: make(map[sfnt.GlyphIndex]rune),
+	}
+	d.fonts = append(d.fonts, u)
+	return u
+}
+
+// SetFillColor sets the colour used for subsequent text and rectangles.
+// Components range from 0 to 1.
+func (p *Page) SetFillColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", num(r), num(g), num(b))
+}
+
+// SetStrokeColor sets the colour used for subsequent lines.
+func (p *Page) SetStrokeColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s RG\n", num(r), num(g), num(b))
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (p *Page) Text(f *Font, size, x, y float64, s string) {
+	if s == "" {
+		return
+	}
+	u := p.doc.use(f)
+	glyphs := f.encode(s, u.glyphs)
+
+	var hex strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&hex, "%04X", uint16(g))
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td <%s> Tj ET\n",
+		u.name, num(size), num(x), num(p.doc.height-y), hex.String())
+}
+
+// Line strokes a straight line from (x1, y1) to (x2, y2).
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(p.doc.height-y1), num(x2), num(p.doc.height-y2))
+}
+
+// Rect fills the rectangle whose top-left corner is (x, y).
+func (p *Page) Rect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(p.doc.height-y-h), num(w), num(h))
+}
+
+// Link makes the rectangle whose top-left corner is (x, y) a clickable link
+// to uri.
+func (p *Page) Link(x, y, w, h float64, uri string) {
+	p.links = append(p.links, link{x: x, y: y, w: w, h: h, uri: uri})
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo renders the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// Object numbers: catalog, page tree and info come first, then five
+	// objects per font, then two per page (page dictionary and content).
+	const (
+		catalogID = 1
+		pagesID   = 2
+		infoID    = 3
+		firstFont = 4
+	)
+	firstPage := firstFont + 5*len(d.fonts)
+	pageID := func(i int) int { return firstPage + 2*i }
+
+	ow := &objectWriter{}
+	ow.printf("%%PDF-1.7\n%%\xe2\xe3\xcf\xd3\n")
+
+	ow.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageID(i))
+	}
+	ow.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), num(d.width), num(d.height)))
+
+	ow.object(infoID, d.info())
+
+	var fontRefs strings.Builder
+	for i, u := range d.fonts {
+		id := firstFont + 5*i
+		fmt.Fprintf(&fontRefs, " /%s %d 0 R", u.name, id)
+		if err := writeFont(ow, id, u); err != nil {
+			return 0, err
+		}
+	}
+
+	for i, p := range d.pages {
+		id := pageID(i)
+		var annots strings.Builder
+		for _, l := range p.links {
+			fmt.Fprintf(&annots, "<< /Type /Annot /Subtype /Link /Border [0 0 0] /Rect [%s %s %s %s] /A << /Type /Action /S /URI /URI %s >> >> ",
+				num(l.x), num(d.height-l.y-l.h), num(l.x+l.w), num(d.height-l.y), literal(l.uri))
+		}
+		dict := fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /Font <<%s >> >> /Contents %d 0 R",
+			pagesID, fontRefs.String(), id+1)
+		if annots.Len() > 0 {
+			dict += " /Annots [" + strings.TrimSpace(annots.String()) + "]"
+		}
+		ow.object(id, dict+" >>")
+		if err := ow.stream(id+1, "", p.content.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+
+	ow.trailer(catalogID, infoID)
+	n, err := w.Write(ow.buf.Bytes())
+	return int64(n), err
+}
+
+func (d *Document) info() string {
+	var b strings.Builder
+	b.WriteString("<< /Producer (srv.exe.dev/internal/pdf)")
+	for _, kv := range []struct{ key, value string }{
+		{"Title", d.Title},
+		{"Author", d.Author},
+		{"Subject", d.Subject},
+		{"Keywords", d.Keywords},
+	} {
+		if kv.value != "" {
+			fmt.Fprintf(&b, " /%s %s", kv.key, textString(kv.value))
+		}
+	}
+	if !d.Created.IsZero() {
+		fmt.Fprintf(&b, " /CreationDate (D:%s)", d.Created.UTC().Format("20060102150405Z"))
+	}
+	b.WriteString(" >>")
+	return b.String()
+}
+
+// writeFont writes a Type 0 font with an Identity-H encoding so that text
+// can address every glyph by index, plus the descendant CID font, its
+// descriptor, the embedded TrueType program and a ToUnicode map that keeps
+// the text extractable.
+func writeFont(ow *objectWriter, id int, u *fontUse) error {
+	f := u.font
+	cidID, descID, fileID, cmapID := id+1, id+2, id+3, id+4
+
+	ow.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		f.name, cidID, cmapID))
+
+	glyphs := make([]sfnt.GlyphIndex, 0, len(u.glyphs))
+	for g := range u.glyphs {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	var widths strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, "%d [%s] ", g, num(f.advance(g)))
+	}
+	ow.object(cidID, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW 0 /W [%s] >>",
+		f.name, descID, strings.TrimSpace(widths.String())))
+
+	flags := 32 // Nonsymbolic
+	if f.fixedPitch {
+		flags |= 1
+	}
+	ow.object(descID, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%s %s %s %s] /ItalicAngle %s /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		f.name, flags, num(f.bbox[0]), num(f.bbox[1]), num(f.bbox[2]), num(f.bbox[3]),
+		num(f.italicAngle), num(f.ascent), num(f.descent), num(f.capHeight), fileID))
+
+	if err := ow.stream(fileID, fmt.Sprintf("/Length1 %d", len(f.src)), f.src); err != nil {
+		return err
+	}
+	return ow.stream(cmapID, "", toUnicodeCMap(glyphs, u.glyphs))
+}
+
+func toUnicodeCMap(glyphs []sfnt.GlyphIndex, runes map[sfnt.GlyphIndex]rune) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// bfchar sections are limited to 100 entries each.
+	for start := 0; start < len(glyphs); start += 100 {
+		end := min(start+100, len(glyphs))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, g := range glyphs[start:end] {
+			var dst strings.Builder
+			for _, unit := range utf16.Encode([]rune{runes[g]}) {
+				fmt.Fprintf(&dst, "%04X", unit)
+			}
+			fmt.Fprintf(&b, "<%04X> <%s>\n", uint16(g), dst.String())
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// objectWriter accumulates the file body and records each object's byte
+// offset for the cross-reference table.
+type objectWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (ow *objectWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&ow.buf, format, args...)
+}
+
+func (ow *objectWriter) begin(id int) {
+	if ow.offsets == nil {
+		ow.offsets = make(map[int]int)
+	}
+	ow.offsets[id] = ow.buf.Len()
+	ow.printf("%d 0 obj\n", id)
+}
+
+func (ow *objectWriter) object(id int, body string) {
+	ow.begin(id)
+	ow.printf("%s\nendobj\n", body)
+}
+
+// stream writes a Flate-compressed stream object. extra is spliced into the
+// stream dictionary.
+func (ow *objectWriter) stream(id int, extra string, data []byte) error {
+	var z bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&z, zlib.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	ow.begin(id)
+	dict := fmt.Sprintf("/Length %d /Filter /FlateDecode", z.Len())
+	if extra != "" {
+		dict += " " + extra
+	}
+	ow.printf("<< %s >>\nstream\n", dict)
+	ow.buf.Write(z.Bytes())
+	ow.printf("\nendstream\nendobj\n")
+	return nil
+}
+
+func (ow *objectWriter) trailer(rootID, infoID int) {
+	size := 0
+	for id := range ow.offsets {
+		size = max(size, id)
+	}
+	size++
+
+	xref := ow.buf.Len()
+	ow.printf("xref\n0 %d\n0000000000 65535 f \n", size)
+	for id := 1; id < size; id++ {
+		ow.printf("%010d 00000 n \n", ow.offsets[id])
+	}
+	ow.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		size, rootID, infoID, xref)
+}
+
+// num formats a coordinate compactly with at most three decimals.
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// literal encodes an ASCII string as a PDF literal string.
+func literal(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// textString encodes s as a PDF text string, using UTF-16BE when s is not
+// plain ASCII.
+func textString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 0x7e || r < 0x20 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return literal(s)
+	}
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
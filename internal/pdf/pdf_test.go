@@ -0,0 +1,131 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestDocumentCrossReferenceOffsetsPointAtObjects(t *testing.T) {
+	doc := New(LetterWidth, LetterHeight)
+	f := MustParseFont(goregular.TTF)
+	doc.AddPage().Text(f, 12, 72, 72, "first page")
+	doc.AddPage().Text(f, 12, 72, 72, "second page")
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.7\n")) {
+		t.Fatalf("expected PDF header, got %q", out[:16])
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("expected trailer with startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	table := string(out[xref:])
+	header := regexp.MustCompile(`^xref\n0 (\d+)\n`).FindStringSubmatch(table)
+	if header == nil {
+		t.Fatalf("startxref does not point at the xref table: %q", table[:20])
+	}
+	size, _ := strconv.Atoi(header[1])
+	entries := strings.Split(table[len(header[0]):], "\n")
+	for id := 1; id < size; id++ {
+		offset, err := strconv.Atoi(entries[id][:10])
+		if err != nil {
+			t.Fatalf("malformed xref entry %q", entries[id])
+		}
+		want := strconv.Itoa(id) + " 0 obj"
+		if !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q, want %q", id, out[offset:offset+len(want)], want)
+		}
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Fatalf("expected page tree with two pages")
+	}
+}
+
+func TestTextIsEmbeddedAndExtractable(t *testing.T) {
+	doc := New(LetterWidth, LetterHeight)
+	f := MustParseFont(goregular.TTF)
+	doc.AddPage().Text(f, 10, 72, 72, "Go → PDF ☃")
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if !bytes.Contains(out, []byte("/FontFile2")) {
+		t.Fatalf("expected the TrueType program to be embedded")
+	}
+	if !bytes.Contains(out, []byte("/Encoding /Identity-H")) {
+		t.Fatalf("expected an Identity-H composite font")
+	}
+
+	cmaps := ""
+	for _, s := range streams(t, out) {
+		if strings.Contains(s, "beginbfchar") {
+			cmaps += s
+		}
+	}
+	// U+2192 RIGHTWARDS ARROW must map back to itself; the snowman is not in
+	// Go Regular and is drawn as '?'.
+	if !strings.Contains(cmaps, "<2192>") {
+		t.Fatalf("expected ToUnicode map to cover the arrow, got %q", cmaps)
+	}
+	if strings.Contains(cmaps, "<2603>") {
+		t.Fatalf("expected missing glyphs to fall back to '?'")
+	}
+}
+
+func TestLinksBecomeURIAnnotations(t *testing.T) {
+	doc := New(LetterWidth, LetterHeight)
+	doc.AddPage().Link(72, 72, 100, 12, "https://example.com/a(b)")
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if !bytes.Contains(out, []byte(`/S /URI /URI (https://example.com/a\(b\))`)) {
+		t.Fatalf("expected escaped URI action in %q", out)
+	}
+	// The link's top-left corner is 72pt from the top of the page.
+	if !bytes.Contains(out, []byte("/Rect [72 708 172 720]")) {
+		t.Fatalf("expected link rect in PDF user space")
+	}
+}
+
+func TestFontWidthScalesWithSize(t *testing.T) {
+	f := MustParseFont(goregular.TTF)
+	small := f.Width("hello", 10)
+	large := f.Width("hello", 20)
+	if small <= 0 || large != 2*small {
+		t.Fatalf("expected width to scale linearly, got %v and %v", small, large)
+	}
+}
+
+func streams(t *testing.T, pdf []byte) []string {
+	t.Helper()
+	re := regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode[^>]*>>\nstream\n`)
+	var out []string
+	for _, loc := range re.FindAllSubmatchIndex(pdf, -1) {
+		n, _ := strconv.Atoi(string(pdf[loc[2]:loc[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(pdf[loc[1] : loc[1]+n]))
+		if err != nil {
+			t.Fatalf("open stream: %v", err)
+		}
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("inflate stream: %v", err)
+		}
+		out = append(out, string(data))
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiterRefillsOverTime(t *testing.T) {
+	l := New(1, 2)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d was limited within the burst", i)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok || wait != time.Second {
+		t.Fatalf("Allow = %v, %v; want limited for 1s", ok, wait)
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Fatal("another key shares the bucket")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Fatal("expected a token after a second")
+	}
+
+	// Idle buckets are dropped once they would be full again.
+	now = now.Add(time.Minute)
+	l.Allow("c")
+	if len(l.buckets) != 1 {
+		t.Fatalf("expected idle buckets to be swept, have %d", len(l.buckets))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParsePrefixes("10.0.0.0/8, 127.0.0.1")
+	if err != nil {
+		t.Fatalf("ParsePrefixes returned error: %v", err)
+	}
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		want   string
+	}{
+		{"direct", "198.51.100.1:1234", "", "198.51.100.1"},
+		{"untrusted peer ignores header", "198.51.100.1:1234", "203.0.113.9", "198.51.100.1"},
+		{"trusted proxy", "127.0.0.1:1234", "203.0.113.9", "203.0.113.9"},
+		{"spoofed left-most entry", "10.1.2.3:1234", "192.0.2.1, 203.0.113.9, 10.0.0.5", "203.0.113.9"},
+		{"trusted proxy without header", "10.1.2.3:1234", "", "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if got := ClientIP(r, trusted); got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParsePrefixes("10.0.0.0/33"); err == nil {
+		t.Fatal("expected an invalid prefix to be rejected")
+	}
+}
+
+func TestMiddlewareSetsRetryAfter(t *testing.T) {
+	l := New(0.5, 1)
+	h := l.Middleware(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != want {
+			t.Fatalf("request %d: status %d, want %d", i, w.Code, want)
+		}
+		if want == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "2" {
+			t.Fatalf("Retry-After = %q, want 2", w.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package resume
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+
+	"srv.exe.dev/internal/pdf"
+)
+
+// The PDF uses the Go Mono family to echo the site's monospace typography.
+// Fonts are parsed once, on first use.
+var pdfFonts = sync.OnceValue(func() [2]*pdf.Font {
+	return [2]*pdf.Font{pdf.MustParseFont(gomono.TTF), pdf.MustParseFont(gomonobold.TTF)}
+})
+
+// Page geometry and type scale, in points.
+const (
+	pdfMargin    = 54.0
+	pdfFooter    = 36.0
+	pdfBodySize  = 9.0
+	pdfSmallSize = 8.0
+	pdfLeading   = 1.45
+)
+
+type rgb [3]float64
+
+var (
+	pdfInk   = rgb{0.08, 0.08, 0.08}
+	pdfMuted = rgb{0.42, 0.42, 0.42}
+	pdfRule  = rgb{0.85, 0.83, 0.80}
+)
+
+// PDF renders the resume as a paginated Letter-size PDF with embedded
+// fonts and clickable contact links.
+func (r *Resume) PDF() ([]byte, error) {
+	fonts := pdfFonts()
+	l := &pdfLayout{
+		doc:     pdf.New(pdf.LetterWidth, pdf.LetterHeight),
+		regular: fonts[0],
+		bold:    fonts[1],
+	}
+	l.doc.Title = r.Basics.Name + " — Resume"
+	l.doc.Author = r.Basics.Name
+	l.doc.Subject = r.Basics.Label
+	l.newPage()
+
+	l.header(r.Basics)
+	if r.Basics.Summary != "" {
+		l.section("Summary", l.lineHeight(pdfBodySize)*2)
+		l.paragraph(r.Basics.Summary)
+	}
+	if len(r.Work) > 0 {
+		l.section("Experience", l.entryHeight())
+		for i, w := range r.Work {
+			if i > 0 {
+				l.y += 8
+			}
+			l.work(w)
+		}
+	}
+	if len(r.Education) > 0 {
+		l.section("Education", l.entryHeight())
+		for _, e := range r.Education {
+			l.entry(e.Degree(), e.Period(), e.Details(), "")
+		}
+	}
+	if len(r.Certificates) > 0 {
+		l.section("Certifications", l.lineHeight(pdfBodySize))
+		for _, c := range r.Certificates {
+			l.paragraph(c.Title())
+		}
+	}
+	if len(r.Skills) > 0 {
+		l.section("Skills", l.lineHeight(pdfBodySize))
+		for _, s := range r.Skills {
+			l.skill(s)
+		}
+	}
+	l.footers(r.Basics.Name)
+
+	return l.doc.Bytes()
+}
+
+// pdfLayout is a simple top-to-bottom flow layout that starts a new page
+// whenever the next block would run into the footer.
+type pdfLayout struct {
+	doc           *pdf.Document
+	page          *pdf.Page
+	regular, bold *pdf.Font
+	y             float64
+}
+
+func (l *pdfLayout) width() float64  { return l.doc.Width() - 2*pdfMargin }
+func (l *pdfLayout) bottom() float64 { return l.doc.Height() - pdfMargin - pdfFooter }
+
+func (l *pdfLayout) lineHeight(size float64) float64 { return size * pdfLeading }
+
+// entryHeight is the space an entry heading plus its first line needs, so a
+// heading is never stranded at the bottom of a page.
+func (l *pdfLayout) entryHeight() float64 {
+	return l.lineHeight(10) + 2*l.lineHeight(pdfBodySize)
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.y = pdfMargin
+}
+
+// need starts a new page unless h points remain above the footer.
+func (l *pdfLayout) need(h float64) {
+	if l.y+h > l.bottom() {
+		l.newPage()
+	}
+}
+
+func (l *pdfLayout) text(f *pdf.Font, size float64, c rgb, x float64, s string) {
+	l.page.SetFillColor(c[0], c[1], c[2])
+	l.page.Text(f, size, x, l.y+f.Ascent(size), s)
+}
+
+func (l *pdfLayout) header(b Basics) {
+	l.text(l.bold, 18, pdfInk, pdfMargin, b.Name)
+	l.y += l.lineHeight(18)
+	if b.Label != "" {
+		l.text(l.regular, 10, pdfMuted, pdfMargin, b.Label)
+		l.y += l.lineHeight(10)
+	}
+
+	type contact struct{ text, uri string }
+	var contacts []contact
+	if loc := b.Location.String(); loc != "" {
+		contacts = append(contacts, contact{text: loc})
+	}
+	if b.Email != "" {
+		contacts = append(contacts, contact{text: b.Email, uri: "mailto:" + b.Email})
+	}
+	if b.URL != "" {
+		contacts = append(contacts, contact{text: displayURL(b.URL), uri: b.URL})
+	}
+	for _, p := range b.Profiles {
+		contacts = append(contacts, contact{text: displayURL(p.URL), uri: p.URL})
+	}
+
+	x := pdfMargin
+	sep := "  ·  "
+	for i, c := range contacts {
+		w := l.regular.Width(c.text, pdfSmallSize)
+		if i > 0 {
+			sw := l.regular.Width(sep, pdfSmallSize)
+			if x+sw+w > pdfMargin+l.width() {
+				x = pdfMargin
+				l.y += l.lineHeight(pdfSmallSize)
+			} else {
+				l.text(l.regular, pdfSmallSize, pdfMuted, x, sep)
+				x += sw
+			}
+		}
+		l.text(l.regular, pdfSmallSize, pdfMuted, x, c.text)
+		if c.uri != "" {
+			l.page.Link(x, l.y, w, l.lineHeight(pdfSmallSize), c.uri)
+		}
+		x += w
+	}
+	l.y += l.lineHeight(pdfSmallSize) + 6
+}
+
+// section draws an upper-case heading with a rule, keeping at least
+// keepWithNext points of the following content on the same page.
+func (l *pdfLayout) section(title string, keepWithNext float64) {
+	l.y += 10
+	l.need(l.lineHeight(pdfSmallSize) + 8 + keepWithNext)
+	l.text(l.bold, pdfSmallSize, pdfMuted, pdfMargin, strings.ToUpper(title))
+	l.y += l.lineHeight(pdfSmallSize) + 2
+	l.page.SetStrokeColor(pdfRule[0], pdfRule[1], pdfRule[2])
+	l.page.Line(pdfMargin, l.y, pdfMargin+l.width(), l.y, 0.5)
+	l.y += 6
+}
+
+// entry draws a bold title with a right-aligned date and a muted subtitle.
+func (l *pdfLayout) entry(title, period, subtitle, uri string) {
+	l.need(l.entryHeight())
+	l.text(l.bold, 10, pdfInk, pdfMargin, title)
+	if period != "" {
+		w := l.regular.Width(period, pdfSmallSize)
+		l.text(l.regular, pdfSmallSize, pdfMuted, pdfMargin+l.width()-w, period)
+	}
+	l.y += l.lineHeight(10)
+	if subtitle != "" {
+		l.text(l.regular, pdfSmallSize, pdfMuted, pdfMargin, subtitle)
+		if uri != "" {
+			l.page.Link(pdfMargin, l.y, l.regular.Width(subtitle, pdfSmallSize), l.lineHeight(pdfSmallSize), uri)
+		}
+		l.y += l.lineHeight(pdfSmallSize) + 2
+	}
+}
+
+func (l *pdfLayout) work(w Work) {
+	l.entry(w.Position, w.Period(), w.Organization(), w.URL)
+	if w.Summary != "" {
+		l.paragraph(w.Summary)
+	}
+	for _, h := range w.Highlights {
+		l.bullet(h.Text)
+	}
+}
+
+func (l *pdfLayout) bullet(s string) {
+	const marker = "→ "
+	indent := l.regular.Width(marker, pdfBodySize)
+	lines := wrap(l.regular, pdfBodySize, l.width()-indent, s)
+	for i, line := range lines {
+		l.need(l.lineHeight(pdfBodySize))
+		if i == 0 {
+			l.text(l.regular, pdfBodySize, pdfMuted, pdfMargin, marker)
+		}
+		l.text(l.regular, pdfBodySize, pdfInk, pdfMargin+indent, line)
+		l.y += l.lineHeight(pdfBodySize)
+	}
+}
+
+func (l *pdfLayout) skill(s Skill) {
+	label := s.Name + ": "
+	indent := l.regular.Width(label, pdfBodySize)
+	lines := wrap(l.regular, pdfBodySize, l.width()-indent, s.KeywordList())
+	for i, line := range lines {
+		l.need(l.lineHeight(pdfBodySize))
+		if i == 0 {
+			l.text(l.regular, pdfBodySize, pdfMuted, pdfMargin, label)
+		}
+		l.text(l.regular, pdfBodySize, pdfInk, pdfMargin+indent, line)
+		l.y += l.lineHeight(pdfBodySize)
+	}
+}
+
+func (l *pdfLayout) paragraph(s string) {
+	for _, line := range wrap(l.regular, pdfBodySize, l.width(), s) {
+		l.need(l.lineHeight(pdfBodySize))
+		l.text(l.regular, pdfBodySize, pdfInk, pdfMargin, line)
+		l.y += l.lineHeight(pdfBodySize)
+	}
+}
+
+// footers stamps "Name · page N of M" on every page once the page count is
+// known.
+func (l *pdfLayout) footers(name string) {
+	pages := l.doc.Pages()
+	for i, p := range pages {
+		label := name + "  ·  page " + strconv.Itoa(i+1) + " of " + strconv.Itoa(len(pages))
+		w := l.regular.Width(label, pdfSmallSize)
+		p.SetFillColor(pdfMuted[0], pdfMuted[1], pdfMuted[2])
+		p.Text(l.regular, pdfSmallSize, (l.doc.Width()-w)/2, l.doc.Height()-pdfMargin, label)
+	}
+}
+
+// wrap greedily breaks s into lines no wider than width, splitting words
+// that are too long to fit on a line of their own.
+func wrap(f *pdf.Font, size, width float64, s string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if f.Width(candidate, size) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = word
+		for f.Width(line, size) > width {
+			runes := []rune(line)
+			cut := len(runes)
+			for cut > 1 && f.Width(string(runes[:cut]), size) > width {
+				cut--
+			}
+			lines = append(lines, string(runes[:cut]))
+			line = string(runes[cut:])
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func displayURL(u string) string {
+	u = strings.TrimPrefix(u, "https://")
+	u = strings.TrimPrefix(u, "http://")
+	u = strings.TrimPrefix(u, "www.")
+	return strings.TrimSuffix(u, "/")
+}
@@ -0,0 +1,253 @@
+// Package resume loads resume data stored in the JSON Resume schema
+// (https://jsonresume.org/schema) and exposes it as typed values that the
+// templates, the live server and the static site generator all share.
+package resume
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Resume is the root JSON Resume document.
+type Resume struct {
+	Schema       string        `json:"$schema,omitempty" yaml:"$schema"`
+	Basics       Basics        `json:"basics" yaml:"basics"`
+	Work         []Work        `json:"work,omitempty" yaml:"work"`
+	Education    []Education   `json:"education,omitempty" yaml:"education"`
+	Certificates []Certificate `json:"certificates,omitempty" yaml:"certificates"`
+	Skills       []Skill       `json:"skills,omitempty" yaml:"skills"`
+}
+
+// Basics holds contact details and the headline summary.
+type Basics struct {
+	Name     string    `json:"name" yaml:"name"`
+	Label    string    `json:"label,omitempty" yaml:"label"`
+	Image    string    `json:"image,omitempty" yaml:"image"`
+	Email    string    `json:"email,omitempty" yaml:"email"`
+	Phone    string    `json:"phone,omitempty" yaml:"phone"`
+	URL      string    `json:"url,omitempty" yaml:"url"`
+	Summary  string    `json:"summary,omitempty" yaml:"summary"`
+	Location Location  `json:"location" yaml:"location"`
+	Profiles []Profile `json:"profiles,omitempty" yaml:"profiles"`
+}
+
+// Location is a postal location; only the coarse fields are rendered.
+type Location struct {
+	Address     string `json:"address,omitempty" yaml:"address"`
+	PostalCode  string `json:"postalCode,omitempty" yaml:"postalCode"`
+	City        string `json:"city,omitempty" yaml:"city"`
+	CountryCode string `json:"countryCode,omitempty" yaml:"countryCode"`
+	Region      string `json:"region,omitempty" yaml:"region"`
+}
+
+// Profile is a social or code-hosting profile.
+type Profile struct {
+	Network  string `json:"network" yaml:"network"`
+	Username string `json:"username,omitempty" yaml:"username"`
+	URL      string `json:"url" yaml:"url"`
+}
+
+// Work is a single position.
+type Work struct {
+	Name       string      `json:"name" yaml:"name"`
+	Position   string      `json:"position" yaml:"position"`
+	URL        string      `json:"url,omitempty" yaml:"url"`
+	Location   string      `json:"location,omitempty" yaml:"location"`
+	StartDate  string      `json:"startDate,omitempty" yaml:"startDate"`
+	EndDate    string      `json:"endDate,omitempty" yaml:"endDate"`
+	Summary    string      `json:"summary,omitempty" yaml:"summary"`
+	Highlights []Highlight `json:"highlights,omitempty" yaml:"highlights"`
+	// Focus tags the role with the areas it demonstrates, for variants.
+	// It is not part of the JSON Resume schema and is left out of exports.
+	Focus []string `json:"-" yaml:"focus"`
+}
+
+// Highlight is a bullet point. In YAML it is either a plain string or a
+// mapping with text and focus tags; in JSON exports it is always a plain
+// string, as the JSON Resume schema requires.
+type Highlight struct {
+	Text  string
+	Focus []string
+}
+
+// Education is a degree or course of study.
+type Education struct {
+	Institution string   `json:"institution" yaml:"institution"`
+	URL         string   `json:"url,omitempty" yaml:"url"`
+	Area        string   `json:"area,omitempty" yaml:"area"`
+	StudyType   string   `json:"studyType,omitempty" yaml:"studyType"`
+	StartDate   string   `json:"startDate,omitempty" yaml:"startDate"`
+	EndDate     string   `json:"endDate,omitempty" yaml:"endDate"`
+	Score       string   `json:"score,omitempty" yaml:"score"`
+	Courses     []string `json:"courses,omitempty" yaml:"courses"`
+}
+
+// Certificate is a professional certification.
+type Certificate struct {
+	Name   string `json:"name" yaml:"name"`
+	Date   string `json:"date,omitempty" yaml:"date"`
+	Issuer string `json:"issuer,omitempty" yaml:"issuer"`
+	URL    string `json:"url,omitempty" yaml:"url"`
+}
+
+// Skill groups related keywords under a heading such as "Backend".
+type Skill struct {
+	Name     string   `json:"name" yaml:"name"`
+	Level    string   `json:"level,omitempty" yaml:"level"`
+	Keywords []string `json:"keywords,omitempty" yaml:"keywords"`
+	Focus    []string `json:"-" yaml:"focus"`
+}
+
+// Load reads a resume from a YAML or JSON file. JSON is a subset of YAML,
+// so a document exported from another JSON Resume tool loads unchanged.
+func Load(path string) (*Resume, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from server configuration, not user input.
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes a YAML or JSON resume document.
+func Parse(data []byte) (*Resume, error) {
+	var r Resume
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("decode resume: %w", err)
+	}
+	if strings.TrimSpace(r.Basics.Name) == "" {
+		return nil, errors.New("resume: basics.name is required")
+	}
+	return &r, nil
+}
+
+// JSON returns the resume as indented JSON Resume output.
+func (r *Resume) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// UnmarshalYAML accepts either a plain string or a {text, focus} mapping.
+func (h *Highlight) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		h.Text = node.Value
+		h.Focus = nil
+		return nil
+	}
+	var raw struct {
+		Text  string   `yaml:"text"`
+		Focus []string `yaml:"focus"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	h.Text, h.Focus = raw.Text, raw.Focus
+	return nil
+}
+
+// MarshalJSON writes the highlight as a plain string.
+func (h Highlight) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.Text)
+}
+
+// String renders the location as "City, Region, CC", skipping empty parts.
+func (l Location) String() string {
+	return joinNonEmpty(", ", l.City, l.Region, l.CountryCode)
+}
+
+// Label is the lower-case link text used for the profile.
+func (p Profile) Label() string {
+	return strings.ToLower(p.Network)
+}
+
+// Period renders the employment range, e.g. "Aug 2024 — Present".
+func (w Work) Period() string {
+	return formatRange(w.StartDate, w.EndDate, "Jan 2006", "Present")
+}
+
+// Organization renders the employer and location, e.g. "Bayer · St. Louis, MO".
+func (w Work) Organization() string {
+	return joinNonEmpty(" · ", w.Name, w.Location)
+}
+
+// Degree renders the study type and area, e.g. "B.S. Computer Science".
+func (e Education) Degree() string {
+	return joinNonEmpty(" ", e.StudyType, e.Area)
+}
+
+// Period renders the years of study, e.g. "2012 — 2016".
+func (e Education) Period() string {
+	return formatRange(e.StartDate, e.EndDate, "2006", "Present")
+}
+
+// Details renders the institution and score, e.g. "UL Lafayette · GPA: 3.66".
+func (e Education) Details() string {
+	score := ""
+	if e.Score != "" {
+		score = "GPA: " + e.Score
+	}
+	return joinNonEmpty(" · ", e.Institution, score)
+}
+
+// Title renders the certificate name with its year, e.g. "CSM (2016)".
+func (c Certificate) Title() string {
+	if c.Date == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s (%s)", c.Name, formatDate(c.Date, "2006"))
+}
+
+// KeywordList renders the keywords as a comma-separated list.
+func (s Skill) KeywordList() string {
+	return strings.Join(s.Keywords, ", ")
+}
+
+// dateLayouts are the ISO 8601 precisions permitted by the JSON Resume schema.
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// formatDate renders an ISO date with layout, passing unparseable values through.
+func formatDate(s, layout string) string {
+	t, ok := parseDate(s)
+	if !ok {
+		return s
+	}
+	return t.Format(layout)
+}
+
+func formatRange(start, end, layout, open string) string {
+	if start == "" {
+		return formatDate(end, layout)
+	}
+	to := open
+	if end != "" {
+		to = formatDate(end, layout)
+	}
+	return formatDate(start, layout) + " — " + to
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
@@ -0,0 +1,198 @@
+package resume
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLoadBundledResume(t *testing.T) {
+	_, thisFile, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(thisFile), "..", "..", "srv", "data", "resume.yaml")
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if r.Basics.Name != "Jacob LeCoq" {
+		t.Fatalf("expected basics.name to be loaded, got %q", r.Basics.Name)
+	}
+	if len(r.Work) != 7 {
+		t.Fatalf("expected 7 work entries, got %d", len(r.Work))
+	}
+	if got := r.Work[0].Period(); got != "Aug 2024 — Present" {
+		t.Fatalf("expected open-ended period, got %q", got)
+	}
+	if got := r.Work[1].Period(); got != "Oct 2023 — Aug 2024" {
+		t.Fatalf("expected closed period, got %q", got)
+	}
+}
+
+func TestParseAcceptsJSONResumeDocuments(t *testing.T) {
+	doc := `{
+  "basics": {"name": "Ada", "location": {"city": "London", "countryCode": "GB"}},
+  "education": [{"institution": "Uni", "studyType": "B.S.", "area": "Maths", "startDate": "1833", "endDate": "1835-06-01", "score": "4.0"}],
+  "certificates": [{"name": "Engines", "date": "1843-09"}],
+  "skills": [{"name": "Maths", "keywords": ["Analysis", "Algebra"]}]
+}`
+	r, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if got := r.Basics.Location.String(); got != "London, GB" {
+		t.Fatalf("unexpected location %q", got)
+	}
+	edu := r.Education[0]
+	if got := edu.Degree(); got != "B.S. Maths" {
+		t.Fatalf("unexpected degree %q", got)
+	}
+	if got := edu.Period(); got != "1833 — 1835" {
+		t.Fatalf("unexpected education period %q", got)
+	}
+	if got := edu.Details(); got != "Uni · GPA: 4.0" {
+		t.Fatalf("unexpected education details %q", got)
+	}
+	if got := r.Certificates[0].Title(); got != "Engines (1843)" {
+		t.Fatalf("unexpected certificate title %q", got)
+	}
+	if got := r.Skills[0].KeywordList(); got != "Analysis, Algebra" {
+		t.Fatalf("unexpected keyword list %q", got)
+	}
+}
+
+func TestParseRequiresName(t *testing.T) {
+	if _, err := Parse([]byte("basics:\n  label: Engineer\n")); err == nil {
+		t.Fatalf("expected missing basics.name to be rejected")
+	}
+}
+
+func TestJSONRoundTrips(t *testing.T) {
+	r, err := Parse([]byte("basics:\n  name: Ada\nwork:\n  - name: Analytical Engines\n    position: Programmer\n    startDate: \"1842\"\n"))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	data, err := r.JSON()
+	if err != nil {
+		t.Fatalf("JSON returned error: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("exported resume is not valid JSON: %v", err)
+	}
+	if !strings.Contains(string(data), `"startDate": "1842"`) {
+		t.Fatalf("expected JSON Resume field names in export, got %s", data)
+	}
+
+	back, err := Parse(data)
+	if err != nil {
+		t.Fatalf("re-importing exported JSON failed: %v", err)
+	}
+	if back.Work[0].Position != "Programmer" {
+		t.Fatalf("expected round trip to preserve work entries, got %+v", back.Work)
+	}
+}
+
+func TestPDFPaginatesLongResumes(t *testing.T) {
+	r := &Resume{Basics: Basics{Name: "Ada", Email: "ada@example.com"}}
+	for i := 0; i < 20; i++ {
+		r.Work = append(r.Work, Work{
+			Name:       "Analytical Engines",
+			Position:   "Programmer",
+			StartDate:  "1842",
+			Highlights: []Highlight{{Text: strings.Repeat("Wrote the first published algorithm for a machine. ", 4)}},
+		})
+	}
+
+	out, err := r.PDF()
+	if err != nil {
+		t.Fatalf("PDF returned error: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "%PDF-") {
+		t.Fatalf("expected PDF output")
+	}
+	if strings.Contains(string(out), "/Count 1 ") {
+		t.Fatalf("expected a long resume to span multiple pages")
+	}
+	if !strings.Contains(string(out), "/URI (mailto:ada@example.com)") {
+		t.Fatalf("expected a clickable email link")
+	}
+}
+
+func TestTextAndMarkdownExports(t *testing.T) {
+	r := &Resume{
+		Basics: Basics{Name: "Ada", Label: "Programmer", Email: "ada@example.com"},
+		Work: []Work{{
+			Name:       "Analytical Engines",
+			Position:   "Programmer",
+			StartDate:  "1842",
+			Highlights: []Highlight{{Text: strings.Repeat("Wrote the first published algorithm. ", 4)}, {Text: "Used *notes* [G]"}},
+		}},
+	}
+
+	text := string(r.Text())
+	for _, line := range strings.Split(text, "\n") {
+		if len([]rune(line)) > textWidth {
+			t.Fatalf("expected text export to wrap at %d columns, got %q", textWidth, line)
+		}
+	}
+	if !strings.Contains(text, "EXPERIENCE\n----------\n") || !strings.Contains(text, "  - Used *notes* [G]\n") {
+		t.Fatalf("unexpected text export:\n%s", text)
+	}
+
+	md := string(r.Markdown())
+	if !strings.HasPrefix(md, "# Ada\n") {
+		t.Fatalf("expected Markdown heading, got:\n%s", md)
+	}
+	if !strings.Contains(md, "[ada@example.com](mailto:ada@example.com)") {
+		t.Fatalf("expected Markdown email link, got:\n%s", md)
+	}
+	if !strings.Contains(md, `- Used \*notes\* \[G\]`) {
+		t.Fatalf("expected Markdown to escape highlight text, got:\n%s", md)
+	}
+}
+
+func TestVCard(t *testing.T) {
+	r := &Resume{Basics: Basics{
+		Name:     "Ada King Lovelace",
+		Label:    "Programmer, Analyst",
+		Email:    "ada@example.com",
+		Profiles: []Profile{{Network: "GitHub", URL: "https://github.com/ada"}},
+	}}
+	photo := append([]byte("\xff\xd8\xff\xe0"), make([]byte, 200)...)
+
+	card := string(r.VCard(photo))
+
+	if !strings.HasPrefix(card, "BEGIN:VCARD\r\nVERSION:4.0\r\n") || !strings.HasSuffix(card, "END:VCARD\r\n") {
+		t.Fatalf("unexpected vCard framing:\n%s", card)
+	}
+	for _, want := range []string{
+		"N:Lovelace;Ada King;;;\r\n",
+		"TITLE:Programmer\\, Analyst\r\n",
+		"URL;TYPE=github:https://github.com/ada\r\n",
+		"PHOTO:data:image/jpeg;base64,",
+	} {
+		if !strings.Contains(card, want) {
+			t.Fatalf("expected vCard to contain %q:\n%s", want, card)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(card, "\r\n"), "\r\n") {
+		if len(line) > vcardLineOctets {
+			t.Fatalf("expected lines folded at %d octets, got %d: %q", vcardLineOctets, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Fatalf("expected folding to keep UTF-8 sequences intact: %q", line)
+		}
+	}
+
+	long := &Resume{Basics: Basics{Name: "Ada", Summary: strings.Repeat("é", 100)}}
+	for _, line := range strings.Split(string(long.VCard(nil)), "\r\n") {
+		if !utf8.ValidString(line) || len(line) > vcardLineOctets {
+			t.Fatalf("expected multi-byte text folded on rune boundaries: %q", line)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package resume
+
+import (
+	"strings"
+)
+
+// textWidth is the column the plain-text export wraps at.
+const textWidth = 80
+
+// Text renders the resume as wrapped plain text, laid out for applicant
+// tracking systems and for pasting into web forms.
+func (r *Resume) Text() []byte {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(r.Basics.Name) + "\n")
+	if r.Basics.Label != "" {
+		b.WriteString(r.Basics.Label + "\n")
+	}
+	contact := []string{r.Basics.Location.String(), r.Basics.Email, displayURL(r.Basics.URL)}
+	for _, p := range r.Basics.Profiles {
+		contact = append(contact, displayURL(p.URL))
+	}
+	if line := joinNonEmpty(" | ", contact...); line != "" {
+		writeWrapped(&b, line, "", "")
+	}
+
+	if r.Basics.Summary != "" {
+		textSection(&b, "Summary")
+		writeWrapped(&b, r.Basics.Summary, "", "")
+	}
+	if len(r.Work) > 0 {
+		textSection(&b, "Experience")
+		for i, w := range r.Work {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(w.Position + "\n")
+			b.WriteString(joinNonEmpty(" | ", w.Organization(), w.Period()) + "\n")
+			if w.Summary != "" {
+				writeWrapped(&b, w.Summary, "", "")
+			}
+			for _, h := range w.Highlights {
+				writeWrapped(&b, h.Text, "  - ", "    ")
+			}
+		}
+	}
+	if len(r.Education) > 0 {
+		textSection(&b, "Education")
+		for _, e := range r.Education {
+			b.WriteString(e.Degree() + "\n")
+			b.WriteString(joinNonEmpty(" | ", e.Details(), e.Period()) + "\n")
+		}
+	}
+	if len(r.Certificates) > 0 {
+		textSection(&b, "Certifications")
+		for _, c := range r.Certificates {
+			b.WriteString(c.Title() + "\n")
+		}
+	}
+	if len(r.Skills) > 0 {
+		textSection(&b, "Skills")
+		for _, s := range r.Skills {
+			writeWrapped(&b, s.Name+": "+s.KeywordList(), "", "  ")
+		}
+	}
+	return []byte(b.String())
+}
+
+// Markdown renders the resume as a Markdown document.
+func (r *Resume) Markdown() []byte {
+	var b strings.Builder
+	b.WriteString("# " + mdEscape(r.Basics.Name) + "\n")
+	if r.Basics.Label != "" {
+		b.WriteString("\n**" + mdEscape(r.Basics.Label) + "**\n")
+	}
+	var contact []string
+	if loc := r.Basics.Location.String(); loc != "" {
+		contact = append(contact, mdEscape(loc))
+	}
+	if r.Basics.Email != "" {
+		contact = append(contact, mdLink(r.Basics.Email, "mailto:"+r.Basics.Email))
+	}
+	if r.Basics.URL != "" {
+		contact = append(contact, mdLink(displayURL(r.Basics.URL), r.Basics.URL))
+	}
+	for _, p := range r.Basics.Profiles {
+		contact = append(contact, mdLink(p.Label(), p.URL))
+	}
+	if len(contact) > 0 {
+		b.WriteString("\n" + strings.Join(contact, " · ") + "\n")
+	}
+
+	if r.Basics.Summary != "" {
+		b.WriteString("\n## Summary\n\n" + mdEscape(r.Basics.Summary) + "\n")
+	}
+	if len(r.Work) > 0 {
+		b.WriteString("\n## Experience\n")
+		for _, w := range r.Work {
+			b.WriteString("\n### " + mdEscape(w.Position) + "\n\n")
+			b.WriteString("*" + mdEscape(w.Organization()) + "* · " + mdEscape(w.Period()) + "\n")
+			if w.Summary != "" {
+				b.WriteString("\n" + mdEscape(w.Summary) + "\n")
+			}
+			if len(w.Highlights) > 0 {
+				b.WriteString("\n")
+				for _, h := range w.Highlights {
+					b.WriteString("- " + mdEscape(h.Text) + "\n")
+				}
+			}
+		}
+	}
+	if len(r.Education) > 0 {
+		b.WriteString("\n## Education\n")
+		for _, e := range r.Education {
+			b.WriteString("\n### " + mdEscape(e.Degree()) + "\n\n")
+			b.WriteString("*" + mdEscape(e.Details()) + "* · " + mdEscape(e.Period()) + "\n")
+		}
+	}
+	if len(r.Certificates) > 0 {
+		b.WriteString("\n## Certifications\n\n")
+		for _, c := range r.Certificates {
+			b.WriteString("- " + mdEscape(c.Title()) + "\n")
+		}
+	}
+	if len(r.Skills) > 0 {
+		b.WriteString("\n## Skills\n\n")
+		for _, s := range r.Skills {
+			b.WriteString("- **" + mdEscape(s.Name) + ":** " + mdEscape(s.KeywordList()) + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func textSection(b *strings.Builder, title string) {
+	b.WriteString("\n" + strings.ToUpper(title) + "\n")
+	b.WriteString(strings.Repeat("-", len(title)) + "\n")
+}
+
+// writeWrapped word-wraps s to textWidth columns. The first line starts
+// with first and continuation lines with indent.
+func writeWrapped(b *strings.Builder, s, first, indent string) {
+	prefix := first
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len([]rune(prefix+line+" "+word)) > textWidth {
+			b.WriteString(prefix + line + "\n")
+			prefix, line = indent, ""
+		}
+		if line == "" {
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	b.WriteString(prefix + line + "\n")
+}
+
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+// mdEscape escapes characters that Markdown would otherwise interpret.
+func mdEscape(s string) string {
+	return mdEscaper.Replace(s)
+}
+
+func mdLink(text, uri string) string {
+	uri = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(uri)
+	return "[" + mdEscape(text) + "](" + uri + ")"
+}
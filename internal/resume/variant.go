@@ -0,0 +1,180 @@
+package resume
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Variant is a named, role-focused view of the resume, e.g. "backend".
+// Work entries, highlights and skills carry focus tags; a variant keeps
+// the ones that match its focus and can pin an explicit order.
+type Variant struct {
+	// Name is the URL slug: the variant is served at /resume/{name}.
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	// Label and Summary replace basics.label and basics.summary when set.
+	Label   string `yaml:"label"`
+	Summary string `yaml:"summary"`
+
+	// Focus lists the tags this variant emphasises.
+	Focus []string `yaml:"focus"`
+	// Work selects and orders work entries by employer name. When empty,
+	// every entry that matches Focus is kept in its original order.
+	Work []string `yaml:"work"`
+	// Skills selects and orders skill groups by name. When empty, groups
+	// matching Focus are moved to the front.
+	Skills []string `yaml:"skills"`
+	// MaxHighlights caps the bullets kept per work entry; zero keeps all.
+	MaxHighlights int `yaml:"maxHighlights"`
+}
+
+type variantsFile struct {
+	Variants []Variant `yaml:"variants"`
+}
+
+var variantNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// LoadVariants reads variant definitions from a YAML file. A missing file
+// means no variants are configured.
+func LoadVariants(path string) ([]Variant, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from server configuration, not user input.
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseVariants(data)
+}
+
+// ParseVariants decodes and validates variant definitions.
+func ParseVariants(data []byte) ([]Variant, error) {
+	var f variantsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode resume variants: %w", err)
+	}
+	seen := make(map[string]bool, len(f.Variants))
+	for _, v := range f.Variants {
+		if !variantNamePattern.MatchString(v.Name) {
+			return nil, fmt.Errorf("resume variant %q: name must be a lower-case slug", v.Name)
+		}
+		if seen[v.Name] {
+			return nil, fmt.Errorf("resume variant %q: defined more than once", v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return f.Variants, nil
+}
+
+// FindVariant returns the variant called name.
+func FindVariant(variants []Variant, name string) (Variant, bool) {
+	for _, v := range variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+// Apply returns a copy of r tailored to the variant. r is not modified.
+func (v Variant) Apply(r *Resume) *Resume {
+	out := *r
+	if v.Label != "" {
+		out.Basics.Label = v.Label
+	}
+	if v.Summary != "" {
+		out.Basics.Summary = v.Summary
+	}
+	out.Work = v.selectWork(r.Work)
+	out.Skills = v.selectSkills(r.Skills)
+	return &out
+}
+
+func (v Variant) selectWork(work []Work) []Work {
+	var picked []Work
+	if len(v.Work) > 0 {
+		for _, name := range v.Work {
+			for _, w := range work {
+				if w.Name == name {
+					picked = append(picked, w)
+					break
+				}
+			}
+		}
+	} else {
+		for _, w := range work {
+			if v.matches(w.Focus) {
+				picked = append(picked, w)
+			}
+		}
+	}
+
+	for i, w := range picked {
+		picked[i].Highlights = v.selectHighlights(w.Highlights)
+	}
+	return picked
+}
+
+// selectHighlights keeps untagged and matching bullets, listing the ones
+// tagged with the variant's focus first.
+func (v Variant) selectHighlights(highlights []Highlight) []Highlight {
+	var focused, general []Highlight
+	for _, h := range highlights {
+		switch {
+		case len(h.Focus) == 0:
+			general = append(general, h)
+		case v.matches(h.Focus):
+			focused = append(focused, h)
+		}
+	}
+	out := append(focused, general...)
+	if v.MaxHighlights > 0 && len(out) > v.MaxHighlights {
+		out = out[:v.MaxHighlights]
+	}
+	return out
+}
+
+func (v Variant) selectSkills(skills []Skill) []Skill {
+	if len(v.Skills) > 0 {
+		var picked []Skill
+		for _, name := range v.Skills {
+			for _, s := range skills {
+				if s.Name == name {
+					picked = append(picked, s)
+					break
+				}
+			}
+		}
+		return picked
+	}
+
+	var focused, rest []Skill
+	for _, s := range skills {
+		if len(s.Focus) > 0 && v.matches(s.Focus) {
+			focused = append(focused, s)
+		} else {
+			rest = append(rest, s)
+		}
+	}
+	return append(focused, rest...)
+}
+
+// matches reports whether tags overlap the variant's focus. Untagged
+// entries, and variants without a focus, match everything.
+func (v Variant) matches(tags []string) bool {
+	if len(tags) == 0 || len(v.Focus) == 0 {
+		return true
+	}
+	for _, t := range tags {
+		if slices.Contains(v.Focus, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package resume
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const variantTestResume = `
+basics:
+  name: Ada
+  label: Engineer
+work:
+  - name: Engines
+    focus: [backend]
+    highlights:
+      - General bullet
+      - text: Frontend bullet
+        focus: [frontend]
+      - text: Backend bullet
+        focus: [backend]
+  - name: Looms
+    focus: [frontend]
+    highlights: [Weaving]
+skills:
+  - name: Frontend
+    focus: [frontend]
+  - name: Backend
+    focus: [backend]
+`
+
+func TestVariantApply(t *testing.T) {
+	r, err := Parse([]byte(variantTestResume))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	v := Variant{Name: "backend", Label: "Backend Engineer", Focus: []string{"backend"}}
+
+	got := v.Apply(r)
+
+	if got.Basics.Label != "Backend Engineer" {
+		t.Fatalf("expected label override, got %q", got.Basics.Label)
+	}
+	if r.Basics.Label != "Engineer" {
+		t.Fatalf("expected Apply to leave the original resume untouched")
+	}
+	if len(got.Work) != 1 || got.Work[0].Name != "Engines" {
+		t.Fatalf("expected only matching work entries, got %+v", got.Work)
+	}
+	hl := got.Work[0].Highlights
+	if len(hl) != 2 || hl[0].Text != "Backend bullet" || hl[1].Text != "General bullet" {
+		t.Fatalf("expected focused bullet first and off-focus bullet dropped, got %+v", hl)
+	}
+	if len(r.Work[0].Highlights) != 3 {
+		t.Fatalf("expected Apply to leave original highlights untouched")
+	}
+	if got.Skills[0].Name != "Backend" || len(got.Skills) != 2 {
+		t.Fatalf("expected matching skills first, got %+v", got.Skills)
+	}
+
+	capped := Variant{Name: "short", Work: []string{"Looms", "Engines"}, MaxHighlights: 1}.Apply(r)
+	if capped.Work[0].Name != "Looms" || len(capped.Work[1].Highlights) != 1 {
+		t.Fatalf("expected explicit work order and highlight cap, got %+v", capped.Work)
+	}
+}
+
+func TestParseVariantsValidatesNames(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"bad slug", "variants:\n  - name: Back End\n"},
+		{"duplicate", "variants:\n  - name: backend\n  - name: backend\n"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseVariants([]byte(tt.doc)); err == nil {
+			t.Fatalf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestLoadBundledVariants(t *testing.T) {
+	_, thisFile, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(thisFile), "..", "..", "srv", "data", "resume-variants.yaml")
+
+	variants, err := LoadVariants(path)
+	if err != nil {
+		t.Fatalf("LoadVariants returned error: %v", err)
+	}
+	if _, ok := FindVariant(variants, "backend"); !ok {
+		t.Fatalf("expected a backend variant, got %+v", variants)
+	}
+
+	missing, err := LoadVariants(filepath.Join(t.TempDir(), "none.yaml"))
+	if err != nil || missing != nil {
+		t.Fatalf("expected a missing file to mean no variants, got %v, %v", missing, err)
+	}
+}
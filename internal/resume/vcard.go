@@ -0,0 +1,93 @@
+package resume
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// vcardLineOctets is the maximum line length from RFC 6350 §3.2, excluding
+// the line break.
+const vcardLineOctets = 75
+
+// VCard renders the contact details as a vCard 4.0 (RFC 6350). When photo
+// is non-empty it is embedded as a data URI.
+func (r *Resume) VCard(photo []byte) []byte {
+	b := r.Basics
+	var lines []string
+	add := func(prop, value string) {
+		lines = append(lines, prop+":"+value)
+	}
+
+	add("BEGIN", "VCARD")
+	add("VERSION", "4.0")
+	add("FN", vcardEscape(b.Name))
+	given, family := splitName(b.Name)
+	add("N", vcardEscape(family)+";"+vcardEscape(given)+";;;")
+	if b.Label != "" {
+		add("TITLE", vcardEscape(b.Label))
+	}
+	if b.Email != "" {
+		add("EMAIL;TYPE=work", vcardEscape(b.Email))
+	}
+	if b.Phone != "" {
+		add("TEL;VALUE=text;TYPE=work", vcardEscape(b.Phone))
+	}
+	if loc := b.Location; loc.City != "" || loc.Region != "" || loc.CountryCode != "" {
+		add("ADR;TYPE=work", ";;"+vcardEscape(loc.Address)+";"+vcardEscape(loc.City)+";"+
+			vcardEscape(loc.Region)+";"+vcardEscape(loc.PostalCode)+";"+vcardEscape(loc.CountryCode))
+	}
+	if b.URL != "" {
+		add("URL", b.URL)
+	}
+	for _, p := range b.Profiles {
+		add("URL;TYPE="+strings.ToLower(p.Network), p.URL)
+	}
+	if b.Summary != "" {
+		add("NOTE", vcardEscape(b.Summary))
+	}
+	if len(photo) > 0 {
+		add("PHOTO", "data:"+http.DetectContentType(photo)+";base64,"+base64.StdEncoding.EncodeToString(photo))
+	}
+	add("END", "VCARD")
+
+	var out strings.Builder
+	for _, l := range lines {
+		writeFolded(&out, l)
+	}
+	return []byte(out.String())
+}
+
+// splitName treats the last word of a name as the family name.
+func splitName(name string) (given, family string) {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return "", name
+	}
+	return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+
+// vcardEscape escapes a text value as required by RFC 6350 §3.4.
+func vcardEscape(s string) string {
+	return vcardEscaper.Replace(s)
+}
+
+// writeFolded writes a content line terminated by CRLF, folding it every
+// 75 octets without splitting a UTF-8 sequence. Continuation lines start
+// with a single space, which counts towards their length.
+func writeFolded(b *strings.Builder, line string) {
+	limit := vcardLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = vcardLineOctets - 1
+	}
+	b.WriteString(line + "\r\n")
+}
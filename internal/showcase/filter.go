@@ -0,0 +1,185 @@
+package showcase
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// Sort orders for Filter.Sort. The empty order keeps the projects as
+// fetched, pinned ones first.
+const (
+	SortStars   = "stars"
+	SortUpdated = "updated"
+	SortName    = "name"
+)
+
+// Sorts lists the orders a Filter accepts, the fetched order first.
+var Sorts = []string{"", SortStars, SortUpdated, SortName}
+
+// Filter narrows and orders the showcase projects. The server reads it
+// from ?lang=, ?topic= and ?sort=; the static build renders one page per
+// filter under Path.
+type Filter struct {
+	// Language matches a project's primary language, ignoring case.
+	Language string
+	// Topic matches any of a project's topics, ignoring case.
+	Topic string
+	Sort  string
+}
+
+// ParseFilter reads a Filter from query parameters, rejecting unknown sort
+// orders.
+func ParseFilter(q url.Values) (Filter, error) {
+	f := Filter{
+		Language: strings.TrimSpace(q.Get("lang")),
+		Topic:    strings.TrimSpace(q.Get("topic")),
+		Sort:     q.Get("sort"),
+	}
+	if !slices.Contains(Sorts, f.Sort) {
+		return Filter{}, fmt.Errorf("unknown sort %q; want stars, updated or name", f.Sort)
+	}
+	return f, nil
+}
+
+// IsZero reports whether f leaves the projects as fetched.
+func (f Filter) IsZero() bool {
+	return f == Filter{}
+}
+
+// Query returns f as a query string with its leading "?", or "" for the
+// zero Filter.
+func (f Filter) Query() string {
+	q := url.Values{}
+	if f.Language != "" {
+		q.Set("lang", f.Language)
+	}
+	if f.Topic != "" {
+		q.Set("topic", f.Topic)
+	}
+	if f.Sort != "" {
+		q.Set("sort", f.Sort)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
+
+// Path returns where the static build renders f, relative to the projects
+// page, e.g. "lang/go/sort/stars/", or "" for the zero Filter. Detail pages
+// live under a forge, e.g. "github/{owner}/{name}/", so these never collide
+// with them.
+func (f Filter) Path() string {
+	var b strings.Builder
+	if f.Language != "" {
+		b.WriteString("lang/" + Slug(f.Language) + "/")
+	}
+	if f.Topic != "" {
+		b.WriteString("topic/" + Slug(f.Topic) + "/")
+	}
+	if f.Sort != "" {
+		b.WriteString("sort/" + f.Sort + "/")
+	}
+	return b.String()
+}
+
+// Apply returns the projects f matches, in f's order. projects is not
+// modified.
+func (f Filter) Apply(projects []githubapi.Project) []githubapi.Project {
+	out := make([]githubapi.Project, 0, len(projects))
+	for _, p := range projects {
+		if f.Language != "" && !strings.EqualFold(p.Language, f.Language) {
+			continue
+		}
+		if f.Topic != "" && !slices.ContainsFunc(p.Topics, func(t string) bool { return strings.EqualFold(t, f.Topic) }) {
+			continue
+		}
+		out = append(out, p)
+	}
+	switch f.Sort {
+	case SortStars:
+		slices.SortStableFunc(out, func(a, b githubapi.Project) int { return cmp.Compare(b.Stars, a.Stars) })
+	case SortUpdated:
+		// UpdatedAt is RFC 3339 in UTC, so it sorts as a string.
+		slices.SortStableFunc(out, func(a, b githubapi.Project) int { return strings.Compare(b.UpdatedAt, a.UpdatedAt) })
+	case SortName:
+		slices.SortStableFunc(out, func(a, b githubapi.Project) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+	}
+	return out
+}
+
+// Facet is a language or topic with the number of projects that have it.
+type Facet struct {
+	Value string
+	Count int
+}
+
+// Facets collects the primary languages and topics of projects, most
+// common first and then alphabetically. Values differing only in case are
+// counted together under the first spelling seen, as filters ignore case.
+func Facets(projects []githubapi.Project) (languages, topics []Facet) {
+	var langs, tops facetCounter
+	for _, p := range projects {
+		if p.Language != "" {
+			langs.add(p.Language)
+		}
+		for _, t := range p.Topics {
+			tops.add(t)
+		}
+	}
+	return langs.sorted(), tops.sorted()
+}
+
+type facetCounter struct {
+	facets []Facet
+	index  map[string]int
+}
+
+func (c *facetCounter) add(value string) {
+	key := strings.ToLower(value)
+	if i, ok := c.index[key]; ok {
+		c.facets[i].Count++
+		return
+	}
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+	c.index[key] = len(c.facets)
+	c.facets = append(c.facets, Facet{Value: value, Count: 1})
+}
+
+func (c *facetCounter) sorted() []Facet {
+	slices.SortFunc(c.facets, func(a, b Facet) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Value, b.Value))
+	})
+	return c.facets
+}
+
+// Slug turns a language or topic into a URL path segment, e.g. "C++" into
+// "cpp" and "Jupyter Notebook" into "jupyter-notebook".
+func Slug(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, "+", "p")
+	s = strings.ReplaceAll(s, "#", "sharp")
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
@@ -0,0 +1,257 @@
+package showcase
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// Overrides is the decoded projects.yaml: hand-written adjustments merged
+// into the fetched showcase projects.
+type Overrides struct {
+	// Featured names projects to list first, in this order.
+	Featured []string `yaml:"featured"`
+	// Hide names projects to leave out.
+	Hide []string `yaml:"hide"`
+	// Overrides replaces fields of fetched projects, keyed by name.
+	Overrides map[string]Entry `yaml:"overrides"`
+	// Projects are manual entries, such as closed-source work, listed
+	// after the fetched ones.
+	Projects []Entry `yaml:"projects"`
+}
+
+// Entry describes a manual project, or the fields to replace on a fetched
+// one. Empty fields leave the fetched value alone.
+type Entry struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// URL is the project's main link, e.g. its repository.
+	URL      string `yaml:"url"`
+	Homepage string `yaml:"homepage"`
+	// Source is the badge shown on a manual project's card, e.g.
+	// "Closed source".
+	Source string `yaml:"source"`
+	// Tech lists technologies, shown in place of the repository topics.
+	Tech  []string                `yaml:"tech"`
+	Image string                  `yaml:"image"`
+	Links []githubapi.ProjectLink `yaml:"links"`
+}
+
+// LoadOverrides reads projects.yaml. A missing file yields no overrides.
+func LoadOverrides(path string) (Overrides, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from server configuration, not user input.
+	if errors.Is(err, os.ErrNotExist) {
+		return Overrides{}, nil
+	}
+	if err != nil {
+		return Overrides{}, err
+	}
+	return ParseOverrides(data)
+}
+
+// ParseOverrides decodes and validates a projects.yaml document.
+func ParseOverrides(data []byte) (Overrides, error) {
+	var o Overrides
+	if err := yaml.Unmarshal(data, &o); err != nil {
+		return Overrides{}, fmt.Errorf("decode project overrides: %w", err)
+	}
+	seen := make(map[string]bool)
+	for i, e := range o.Projects {
+		if e.Name == "" {
+			return Overrides{}, fmt.Errorf("project overrides: projects[%d]: name is required", i)
+		}
+		if strings.ContainsAny(e.Name, "/?#") {
+			return Overrides{}, fmt.Errorf("project overrides: project %q: name must not contain /, ? or #", e.Name)
+		}
+		key := strings.ToLower(e.Name)
+		if seen[key] {
+			return Overrides{}, fmt.Errorf("project overrides: project %q is listed twice", e.Name)
+		}
+		seen[key] = true
+	}
+	for name, e := range o.Overrides {
+		if e.Name != "" && e.Name != name {
+			return Overrides{}, fmt.Errorf("project overrides: override %q cannot rename the project", name)
+		}
+		if e.Source != "" {
+			return Overrides{}, fmt.Errorf("project overrides: override %q: source only applies to manual projects", name)
+		}
+	}
+	for _, e := range o.entries() {
+		for _, u := range append([]string{e.URL, e.Homepage, e.Image}, linkURLs(e.Links)...) {
+			if err := validateURL(u); err != nil {
+				return Overrides{}, fmt.Errorf("project overrides: %s: %w", e.Name, err)
+			}
+		}
+	}
+	return o, nil
+}
+
+// entries returns every entry, named, for validation.
+func (o Overrides) entries() []Entry {
+	out := slices.Clone(o.Projects)
+	for name, e := range o.Overrides {
+		e.Name = name
+		out = append(out, e)
+	}
+	return out
+}
+
+func linkURLs(links []githubapi.ProjectLink) []string {
+	out := make([]string, len(links))
+	for i, l := range links {
+		out[i] = l.URL
+	}
+	return out
+}
+
+// validateURL accepts empty strings, site-relative paths such as
+// "/static/img/app.png" and absolute http(s) URLs.
+func validateURL(raw string) error {
+	if raw == "" || (strings.HasPrefix(raw, "/") && !strings.HasPrefix(raw, "//")) {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q", raw)
+	}
+	return nil
+}
+
+// Apply merges the overrides into projects: it replaces overridden
+// fields, appends the manual entries, drops hidden projects and moves
+// featured ones to the front. Names match case-insensitively, as GitHub's
+// do. projects is not modified.
+func (o Overrides) Apply(projects []githubapi.Project) []githubapi.Project {
+	out := make([]githubapi.Project, 0, len(projects)+len(o.Projects))
+	for _, p := range projects {
+		if e, ok := o.override(p.Name); ok {
+			p = e.merge(p)
+		}
+		out = append(out, p)
+	}
+	for _, e := range o.Projects {
+		p := e.merge(githubapi.Project{Name: e.Name, Manual: true})
+		if over, ok := o.override(e.Name); ok {
+			p = over.merge(p)
+		}
+		out = append(out, p)
+	}
+
+	out = slices.DeleteFunc(out, func(p githubapi.Project) bool {
+		return containsFold(o.Hide, p.Name)
+	})
+	slices.SortStableFunc(out, func(a, b githubapi.Project) int {
+		return featuredRank(o.Featured, a.Name) - featuredRank(o.Featured, b.Name)
+	})
+	return out
+}
+
+// ApplyDetail returns a copy of d with the project's overridden fields
+// replaced, so detail pages match the showcase cards.
+func (o Overrides) ApplyDetail(d *githubapi.ProjectDetail) *githubapi.ProjectDetail {
+	e, ok := o.override(d.Name)
+	if !ok {
+		return d
+	}
+	out := *d
+	out.Project = e.merge(d.Project)
+	return &out
+}
+
+func (o Overrides) override(name string) (Entry, bool) {
+	for key, e := range o.Overrides {
+		if strings.EqualFold(key, name) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// merge returns p with the entry's non-empty fields applied.
+func (e Entry) merge(p githubapi.Project) githubapi.Project {
+	if e.Description != "" {
+		p.Description = e.Description
+	}
+	if e.URL != "" {
+		p.URL = e.URL
+	}
+	if e.Homepage != "" {
+		p.HomepageURL = e.Homepage
+	}
+	if e.Source != "" {
+		p.Source = e.Source
+	}
+	if e.Tech != nil {
+		p.Topics = e.Tech
+	}
+	if e.Image != "" {
+		p.Image = e.Image
+	}
+	if e.Links != nil {
+		p.Links = e.Links
+	}
+	return p
+}
+
+// WithBasePath returns a copy of o whose site-relative URLs, such as
+// "/static/img/app.png", are prefixed with base, for a site served under a
+// path like /portfolio. Absolute URLs are left alone.
+func (o Overrides) WithBasePath(base string) Overrides {
+	if base == "" {
+		return o
+	}
+	out := o
+	out.Overrides = make(map[string]Entry, len(o.Overrides))
+	for name, e := range o.Overrides {
+		out.Overrides[name] = e.withBasePath(base)
+	}
+	out.Projects = make([]Entry, len(o.Projects))
+	for i, e := range o.Projects {
+		out.Projects[i] = e.withBasePath(base)
+	}
+	return out
+}
+
+func (e Entry) withBasePath(base string) Entry {
+	prefix := func(u string) string {
+		if strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") {
+			return base + u
+		}
+		return u
+	}
+	e.URL = prefix(e.URL)
+	e.Homepage = prefix(e.Homepage)
+	e.Image = prefix(e.Image)
+	if e.Links != nil {
+		links := make([]githubapi.ProjectLink, len(e.Links))
+		for i, l := range e.Links {
+			l.URL = prefix(l.URL)
+			links[i] = l
+		}
+		e.Links = links
+	}
+	return e
+}
+
+// featuredRank orders featured projects by their position in featured and
+// everything else after them.
+func featuredRank(featured []string, name string) int {
+	for i, f := range featured {
+		if strings.EqualFold(f, name) {
+			return i
+		}
+	}
+	return len(featured)
+}
+
+func containsFold(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+}
@@ -0,0 +1,102 @@
+// Package showcase loads the configuration that decides which repositories
+// appear on the projects showcase. The live server and the static site
+// generator read the same file, srv/data/showcase.yaml.
+package showcase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/sources"
+)
+
+// Config is the decoded showcase.yaml. Zero values fall back to the
+// githubapi defaults.
+type Config struct {
+	// Pinned is how many pinned repositories to show.
+	Pinned int `yaml:"pinned"`
+	// Recent is how many recently updated repositories to show when no
+	// GitHub token is available and pinned items cannot be read.
+	Recent int `yaml:"recent"`
+	// Topics is how many topics to show per repository.
+	Topics int `yaml:"topics"`
+	// Commits is how many recent commits per repository feed the activity
+	// section and Atom feed.
+	Commits int `yaml:"commits"`
+	// Languages is how many languages each repository's language bar shows.
+	Languages int `yaml:"languages"`
+	// ResumeLanguages adds the bytes-by-language breakdown across the
+	// user's repositories to the resume's skills section.
+	ResumeLanguages bool `yaml:"resumeLanguages"`
+	// Extra lists "owner/repo" entries shown after the pinned ones.
+	Extra   []string `yaml:"extra"`
+	Exclude Exclude  `yaml:"exclude"`
+	// PageSize and MaxPages tune pagination against the GitHub APIs.
+	PageSize int `yaml:"pageSize"`
+	MaxPages int `yaml:"maxPages"`
+	// APIUsers are the GitHub users, besides the site's own, that
+	// /api/projects will fetch repositories for.
+	APIUsers []string `yaml:"apiUsers"`
+	// Sources lists forges beyond GitHub whose repositories are merged
+	// into the showcase after the GitHub ones.
+	Sources []sources.Config `yaml:"sources"`
+}
+
+// Exclude holds the rules for hiding repositories.
+type Exclude struct {
+	// Repos are glob patterns matched against "repo" and "owner/repo".
+	Repos    []string `yaml:"repos"`
+	Forks    bool     `yaml:"forks"`
+	Archived bool     `yaml:"archived"`
+}
+
+// Load reads the showcase configuration. A missing file yields the
+// default configuration.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from server configuration, not user input.
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a showcase configuration.
+func Parse(data []byte) (Config, error) {
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return Config{}, fmt.Errorf("decode showcase config: %w", err)
+	}
+	if err := githubapi.ValidateFetchOptions(c.FetchOptions()); err != nil {
+		return Config{}, fmt.Errorf("showcase config: %w", err)
+	}
+	for i, src := range c.Sources {
+		if err := src.Validate(); err != nil {
+			return Config{}, fmt.Errorf("showcase config: sources[%d]: %w", i, err)
+		}
+	}
+	return c, nil
+}
+
+// FetchOptions converts the configuration to githubapi options.
+func (c Config) FetchOptions() githubapi.FetchOptions {
+	return githubapi.FetchOptions{
+		PinnedCount:     c.Pinned,
+		RecentCount:     c.Recent,
+		TopicCount:      c.Topics,
+		LanguageCount:   c.Languages,
+		CommitCount:     c.Commits,
+		ExtraRepos:      c.Extra,
+		Exclude:         c.Exclude.Repos,
+		ExcludeForks:    c.Exclude.Forks,
+		ExcludeArchived: c.Exclude.Archived,
+		PageSize:        c.PageSize,
+		MaxPages:        c.MaxPages,
+	}
+}
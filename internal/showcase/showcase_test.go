@@ -0,0 +1,204 @@
+package showcase
+
+import (
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+func TestLoadBundledConfig(t *testing.T) {
+	_, thisFile, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(thisFile), "..", "..", "srv", "data", "showcase.yaml")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if opts := cfg.FetchOptions(); opts.PinnedCount != 6 || opts.TopicCount != 5 {
+		t.Fatalf("unexpected options from bundled config: %+v", opts)
+	}
+
+	if _, err := LoadOverrides(filepath.Join(filepath.Dir(path), "projects.yaml")); err != nil {
+		t.Fatalf("LoadOverrides returned error for the bundled file: %v", err)
+	}
+
+	missing, err := Load(filepath.Join(t.TempDir(), "none.yaml"))
+	if err != nil {
+		t.Fatalf("expected a missing file to mean defaults, got %v", err)
+	}
+	if missing.Pinned != 0 || len(missing.Extra) != 0 {
+		t.Fatalf("expected zero config for a missing file, got %+v", missing)
+	}
+}
+
+func TestParseRejectsInvalidEntries(t *testing.T) {
+	for _, doc := range []string{
+		"extra: [not-a-repo]\n",
+		"exclude:\n  repos: ['[oops']\n",
+		"sources:\n  - type: gitea\n    user: someone\n",
+	} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Fatalf("expected %q to be rejected", doc)
+		}
+	}
+}
+
+func TestOverridesApply(t *testing.T) {
+	o, err := ParseOverrides([]byte(`
+featured: [manual, b]
+hide: [Hidden]
+overrides:
+  A:
+    description: Better words
+    tech: [go]
+    links:
+      - label: docs
+        url: https://a.example/docs
+projects:
+  - name: manual
+    description: Closed-source work
+    source: Closed source
+    image: /static/img/manual.png
+`))
+	if err != nil {
+		t.Fatalf("ParseOverrides returned error: %v", err)
+	}
+	fetched := []githubapi.Project{
+		{Name: "a", Description: "terse", Topics: []string{"x"}, Stars: 3},
+		{Name: "hidden"},
+		{Name: "b"},
+	}
+
+	got := o.Apply(fetched)
+	var names []string
+	for _, p := range got {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "manual,b,a" {
+		t.Fatalf("order = %v, want manual,b,a", names)
+	}
+	if a := got[2]; a.Description != "Better words" || a.Stars != 3 || len(a.Topics) != 1 || a.Topics[0] != "go" || len(a.Links) != 1 {
+		t.Fatalf("override not merged: %+v", a)
+	}
+	if m := got[0]; !m.Manual || m.OnGitHub() || m.Source != "Closed source" || m.Image != "/static/img/manual.png" {
+		t.Fatalf("unexpected manual entry %+v", m)
+	}
+	if fetched[0].Description != "terse" {
+		t.Fatal("Apply modified its input")
+	}
+}
+
+func TestOverridesWithBasePath(t *testing.T) {
+	o, err := ParseOverrides([]byte(`
+overrides:
+  a:
+    image: /static/img/a.png
+    links:
+      - label: case study
+        url: /blog/a
+      - label: docs
+        url: https://a.example/docs
+projects:
+  - name: manual
+    url: /projects/manual-notes
+    image: https://cdn.example/manual.png
+`))
+	if err != nil {
+		t.Fatalf("ParseOverrides returned error: %v", err)
+	}
+
+	got := o.WithBasePath("/portfolio").Apply([]githubapi.Project{{Name: "a"}})
+	a, m := got[0], got[1]
+	if a.Image != "/portfolio/static/img/a.png" || a.Links[0].URL != "/portfolio/blog/a" || a.Links[1].URL != "https://a.example/docs" {
+		t.Fatalf("unexpected override %+v", a)
+	}
+	if m.URL != "/portfolio/projects/manual-notes" || m.Image != "https://cdn.example/manual.png" {
+		t.Fatalf("unexpected manual entry %+v", m)
+	}
+	if o.Overrides["a"].Image != "/static/img/a.png" || o.Overrides["a"].Links[0].URL != "/blog/a" {
+		t.Fatal("WithBasePath modified its receiver")
+	}
+}
+
+func TestParseOverridesRejectsInvalidEntries(t *testing.T) {
+	for _, doc := range []string{
+		"projects:\n  - description: no name\n",
+		"projects:\n  - name: a\n  - name: A\n",
+		"projects:\n  - name: a/b\n",
+		"projects:\n  - name: a\n    url: javascript:alert(1)\n",
+		"overrides:\n  a:\n    source: GitLab\n",
+		"overrides:\n  a:\n    image: //evil.example/x.png\n",
+	} {
+		if _, err := ParseOverrides([]byte(doc)); err == nil {
+			t.Fatalf("expected %q to be rejected", doc)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	projects := []githubapi.Project{
+		{Name: "runeforge", Language: "Go", Topics: []string{"cli", "game"}, Stars: 5, UpdatedAt: "2026-01-03T00:00:00Z"},
+		{Name: "Atlas", Language: "TypeScript", Topics: []string{"web"}, Stars: 20, UpdatedAt: "2026-02-01T00:00:00Z"},
+		{Name: "mcp", Language: "go", Topics: []string{"CLI"}, Stars: 9, UpdatedAt: "2025-12-01T00:00:00Z"},
+	}
+	names := func(ps []githubapi.Project) string {
+		var out []string
+		for _, p := range ps {
+			out = append(out, p.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		query string
+		want  string
+		path  string
+	}{
+		{"", "runeforge,Atlas,mcp", ""},
+		{"lang=Go", "runeforge,mcp", "lang/go/"},
+		{"topic=cli&sort=stars", "mcp,runeforge", "topic/cli/sort/stars/"},
+		{"sort=updated", "Atlas,runeforge,mcp", "sort/updated/"},
+		{"sort=name", "Atlas,mcp,runeforge", "sort/name/"},
+		{"lang=Rust", "", "lang/rust/"},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		f, err := ParseFilter(q)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) returned error: %v", tt.query, err)
+		}
+		if got := names(f.Apply(projects)); got != tt.want {
+			t.Errorf("%q: Apply = %s, want %s", tt.query, got, tt.want)
+		}
+		if got := f.Path(); got != tt.path {
+			t.Errorf("%q: Path = %q, want %q", tt.query, got, tt.path)
+		}
+	}
+	if names(projects) != "runeforge,Atlas,mcp" {
+		t.Fatalf("Apply reordered its input: %s", names(projects))
+	}
+
+	if _, err := ParseFilter(url.Values{"sort": {"forks"}}); err == nil {
+		t.Fatal("expected an unknown sort to be rejected")
+	}
+	if q := (Filter{Language: "C++", Sort: SortStars}).Query(); q != "?lang=C%2B%2B&sort=stars" {
+		t.Fatalf("Query = %q", q)
+	}
+	for in, want := range map[string]string{"C++": "cpp", "C#": "csharp", "Jupyter Notebook": "jupyter-notebook", "vue.js": "vue.js"} {
+		if got := Slug(in); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	languages, topics := Facets(projects)
+	if len(languages) != 2 || languages[0] != (Facet{Value: "Go", Count: 2}) {
+		t.Fatalf("unexpected language facets %+v", languages)
+	}
+	if len(topics) != 3 || topics[0] != (Facet{Value: "cli", Count: 2}) || topics[1].Value != "game" {
+		t.Fatalf("unexpected topic facets %+v", topics)
+	}
+}
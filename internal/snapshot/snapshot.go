@@ -0,0 +1,99 @@
+// Package snapshot saves the data fetched from GitHub and the other project
+// sources to a JSON file, so cmd/build and the server can run from it
+// without network access.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// Version is the snapshot format version. Load rejects other versions.
+// Version 2 keys Details by Project.Key rather than by name.
+const Version = 2
+
+// Snapshot is everything the projects pages and the resume read from the
+// forges.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	User      string    `json:"user"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	// Projects are as fetched, before projects.yaml is applied, so
+	// overrides can still be edited when building from a snapshot.
+	Projects []githubapi.Project `json:"projects"`
+	// Details holds README and release data, keyed by Project.Key, so
+	// same-named repositories of different owners or forges stay apart.
+	Details       map[string]*githubapi.ProjectDetail `json:"details,omitempty"`
+	Contributions *githubapi.ContributionCalendar     `json:"contributions,omitempty"`
+	Activity      []githubapi.ActivityItem            `json:"activity,omitempty"`
+	Languages     []githubapi.LanguageShare           `json:"languages,omitempty"`
+	Upstream      []githubapi.UpstreamRepo            `json:"upstream,omitempty"`
+}
+
+// New returns an empty snapshot for user, stamped with the current time.
+func New(user string) *Snapshot {
+	return &Snapshot{
+		Version:   Version,
+		User:      user,
+		FetchedAt: time.Now().UTC(),
+		Details:   make(map[string]*githubapi.ProjectDetail),
+	}
+}
+
+// Load reads a snapshot written by Save.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from a flag or server configuration.
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decode projects snapshot: %w", err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("projects snapshot has version %d, want %d", s.Version, Version)
+	}
+	if s.Details == nil {
+		s.Details = make(map[string]*githubapi.ProjectDetail)
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot as indented JSON. The file is replaced
+// atomically, so a failed build never leaves a truncated snapshot behind.
+func (s *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode projects snapshot: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".snapshot-*.json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Detail returns the stored detail for project, or one without a README
+// when none was saved.
+func (s *Snapshot) Detail(project githubapi.Project) *githubapi.ProjectDetail {
+	if d, ok := s.Details[project.Key()]; ok && d != nil {
+		return d
+	}
+	return &githubapi.ProjectDetail{Project: project}
+}
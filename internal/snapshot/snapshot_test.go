@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "projects.json")
+	snap := New("HexSleeves")
+	snap.Projects = []githubapi.Project{{Name: "runeforge", Owner: "HexSleeves", Stars: 5}}
+	snap.Details[snap.Projects[0].Key()] = &githubapi.ProjectDetail{Project: snap.Projects[0], README: "# Runeforge"}
+	snap.Contributions = &githubapi.ContributionCalendar{Total: 3, Days: []githubapi.ContributionDay{{Date: "2026-01-02", Count: 3, Level: 2}}}
+
+	if err := snap.Save(path); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got.User != "HexSleeves" || !got.FetchedAt.Equal(snap.FetchedAt) || len(got.Projects) != 1 || got.Projects[0].Stars != 5 {
+		t.Fatalf("unexpected snapshot %+v", got)
+	}
+	if d := got.Detail(got.Projects[0]); d.README != "# Runeforge" {
+		t.Fatalf("Detail = %+v, want the saved README", d)
+	}
+	if d := got.Detail(githubapi.Project{Name: "other"}); d.Name != "other" || d.README != "" {
+		t.Fatalf("Detail for an unsaved project = %+v", d)
+	}
+	if d := got.Detail(githubapi.Project{Name: "runeforge", Owner: "someone-else"}); d.README != "" {
+		t.Fatalf("Detail for a same-named project of another owner = %+v", d)
+	}
+	if got.Contributions == nil || got.Contributions.Total != 3 {
+		t.Fatalf("contributions not restored: %+v", got.Contributions)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected only the snapshot in its directory, got %v, %v", entries, err)
+	}
+}
+
+func TestLoadRejectsOtherVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "projects": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected a snapshot with an unknown version to be rejected")
+	}
+}
@@ -0,0 +1,97 @@
+package sources
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// Gitea lists a user's repositories through the Gitea API, which Forgejo
+// (and so Codeberg) also serves.
+type Gitea struct {
+	forge
+}
+
+type giteaRepo struct {
+	Name  string `json:"name"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+	Description string   `json:"description"`
+	HTMLURL     string   `json:"html_url"`
+	Language    string   `json:"language"`
+	Stars       int      `json:"stars_count"`
+	Forks       int      `json:"forks_count"`
+	UpdatedAt   string   `json:"updated_at"`
+	Website     string   `json:"website"`
+	Topics      []string `json:"topics"`
+	Fork        bool     `json:"fork"`
+	Archived    bool     `json:"archived"`
+	Private     bool     `json:"private"`
+}
+
+// FetchProjects returns the user's most recently updated public
+// repositories. The API has no sort parameter for a user's repositories,
+// so every page, up to maxPages, is fetched and sorted here.
+func (g *Gitea) FetchProjects(ctx context.Context) ([]githubapi.Project, error) {
+	var raw []giteaRepo
+	for page := 1; page <= maxPages; page++ {
+		repos, err := g.fetchPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, repos...)
+		if len(repos) < maxLimit {
+			break
+		}
+	}
+
+	// RFC 3339 timestamps in the same zone sort lexically.
+	slices.SortStableFunc(raw, func(a, b giteaRepo) int {
+		return cmp.Compare(b.UpdatedAt, a.UpdatedAt)
+	})
+	var projects []githubapi.Project
+	for _, r := range raw {
+		if r.Private {
+			continue
+		}
+		projects = append(projects, githubapi.Project{
+			Source:      g.label,
+			Forge:       g.kind,
+			Name:        r.Name,
+			Owner:       r.Owner.Login,
+			Description: r.Description,
+			URL:         r.HTMLURL,
+			Language:    r.Language,
+			Stars:       r.Stars,
+			Forks:       r.Forks,
+			UpdatedAt:   r.UpdatedAt,
+			HomepageURL: r.Website,
+			Topics:      r.Topics,
+			Fork:        r.Fork,
+			Archived:    r.Archived,
+		})
+	}
+	return g.shown(projects), nil
+}
+
+// fetchPage returns one page of up to maxLimit of the user's repositories.
+func (g *Gitea) fetchPage(ctx context.Context, page int) ([]giteaRepo, error) {
+	u := fmt.Sprintf("%s/api/v1/users/%s/repos?limit=%d&page=%d", g.baseURL, url.PathEscape(g.user), maxLimit, page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create gitea request: %w", err)
+	}
+	var raw []giteaRepo
+	if err := g.do(req, func(req *http.Request, token string) {
+		req.Header.Set("Authorization", "token "+token)
+	}, &raw); err != nil {
+		return nil, fmt.Errorf("gitea: %w", err)
+	}
+	return raw, nil
+}
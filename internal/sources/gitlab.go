@@ -0,0 +1,84 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// GitLab lists a user's projects through the GitLab REST API (v4).
+type GitLab struct {
+	forge
+}
+
+type gitlabProject struct {
+	Name      string `json:"name"`
+	Namespace struct {
+		Path string `json:"path"`
+	} `json:"namespace"`
+	Description       string   `json:"description"`
+	WebURL            string   `json:"web_url"`
+	StarCount         int      `json:"star_count"`
+	ForksCount        int      `json:"forks_count"`
+	LastActivityAt    string   `json:"last_activity_at"`
+	Topics            []string `json:"topics"`
+	Archived          bool     `json:"archived"`
+	ForkedFromProject *struct {
+		ID int `json:"id"`
+	} `json:"forked_from_project"`
+}
+
+// FetchProjects returns the user's most recently active projects,
+// following the pages until enough remain after the exclusions.
+func (g *GitLab) FetchProjects(ctx context.Context) ([]githubapi.Project, error) {
+	var projects []githubapi.Project
+	for page := 1; page <= maxPages; page++ {
+		raw, err := g.fetchPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range raw {
+			projects = append(projects, githubapi.Project{
+				Source:      g.label,
+				Forge:       g.kind,
+				Name:        r.Name,
+				Owner:       r.Namespace.Path,
+				Description: r.Description,
+				URL:         r.WebURL,
+				Stars:       r.StarCount,
+				Forks:       r.ForksCount,
+				UpdatedAt:   r.LastActivityAt,
+				Topics:      r.Topics,
+				Fork:        r.ForkedFromProject != nil,
+				Archived:    r.Archived,
+			})
+		}
+		projects = slices.DeleteFunc(projects, g.opts.Excluded)
+		if len(raw) < maxLimit || len(projects) >= g.limit {
+			break
+		}
+	}
+	return g.shown(projects), nil
+}
+
+// fetchPage returns one page of the user's projects, most recently active
+// first.
+func (g *GitLab) fetchPage(ctx context.Context, page int) ([]gitlabProject, error) {
+	u := fmt.Sprintf("%s/api/v4/users/%s/projects?order_by=last_activity_at&sort=desc&per_page=%d&page=%d",
+		g.baseURL, url.PathEscape(g.user), maxLimit, page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create gitlab request: %w", err)
+	}
+	var raw []gitlabProject
+	if err := g.do(req, func(req *http.Request, token string) {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}, &raw); err != nil {
+		return nil, fmt.Errorf("gitlab: %w", err)
+	}
+	return raw, nil
+}
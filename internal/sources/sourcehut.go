@@ -0,0 +1,127 @@
+package sources
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// sourcehutQuery lists a page of a user's repositories on git.sr.ht. The
+// API takes no ordering, so FetchProjects reads every page and sorts them.
+const sourcehutQuery = `
+query($username: String!, $cursor: Cursor) {
+  user(username: $username) {
+    repositories(cursor: $cursor) {
+      results { name description visibility updated owner { canonicalName } }
+      cursor
+    }
+  }
+}
+`
+
+// SourceHut lists a user's repositories through the git.sr.ht GraphQL
+// API, which requires a personal access token.
+type SourceHut struct {
+	forge
+}
+
+type sourcehutRepo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+	Updated     string `json:"updated"`
+	Owner       struct {
+		CanonicalName string `json:"canonicalName"`
+	} `json:"owner"`
+}
+
+type sourcehutResponse struct {
+	Data struct {
+		User *struct {
+			Repositories struct {
+				Results []sourcehutRepo `json:"results"`
+				// Cursor is null on the last page.
+				Cursor *string `json:"cursor"`
+			} `json:"repositories"`
+		} `json:"user"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// FetchProjects returns the user's most recently updated public
+// repositories, following the cursor through up to maxPages pages.
+func (s *SourceHut) FetchProjects(ctx context.Context) ([]githubapi.Project, error) {
+	var repos []sourcehutRepo
+	var cursor *string
+	for range maxPages {
+		page, next, err := s.fetchPage(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page...)
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+
+	// RFC 3339 timestamps in the same zone sort lexically.
+	slices.SortStableFunc(repos, func(a, b sourcehutRepo) int {
+		return cmp.Compare(b.Updated, a.Updated)
+	})
+	var projects []githubapi.Project
+	for _, r := range repos {
+		if r.Visibility != "PUBLIC" {
+			continue
+		}
+		owner := strings.TrimPrefix(r.Owner.CanonicalName, "~")
+		projects = append(projects, githubapi.Project{
+			Source:      s.label,
+			Forge:       s.kind,
+			Name:        r.Name,
+			Owner:       owner,
+			Description: r.Description,
+			URL:         fmt.Sprintf("%s/~%s/%s", s.baseURL, owner, r.Name),
+			UpdatedAt:   r.Updated,
+		})
+	}
+	return s.shown(projects), nil
+}
+
+// fetchPage returns the page of repositories after cursor, nil for the
+// first page, and the cursor of the next page.
+func (s *SourceHut) fetchPage(ctx context.Context, cursor *string) ([]sourcehutRepo, *string, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":     sourcehutQuery,
+		"variables": map[string]any{"username": strings.TrimPrefix(s.user, "~"), "cursor": cursor},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal sourcehut request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/query", bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create sourcehut request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var resp sourcehutResponse
+	if err := s.do(req, bearer, &resp); err != nil {
+		return nil, nil, fmt.Errorf("sourcehut: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, nil, fmt.Errorf("sourcehut: %s", resp.Errors[0].Message)
+	}
+	if resp.Data.User == nil {
+		return nil, nil, fmt.Errorf("sourcehut: user %q not found", s.user)
+	}
+	repos := resp.Data.User.Repositories
+	return repos.Results, repos.Cursor, nil
+}
@@ -0,0 +1,269 @@
+// Package sources gathers showcase projects from several forges: GitHub
+// through the githubapi package, plus GitLab, Gitea/Forgejo and sourcehut.
+// Every source maps its repositories onto githubapi.Project so they can
+// be merged into one showcase.
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// ProjectSource lists the projects a user hosts on one forge.
+type ProjectSource interface {
+	// Name identifies the source in errors, e.g. "gitlab.com/someone".
+	Name() string
+	FetchProjects(ctx context.Context) ([]githubapi.Project, error)
+}
+
+// DefaultLimit is how many projects a non-GitHub source returns when its
+// Config.Limit is zero.
+const DefaultLimit = 6
+
+// maxLimit is the largest page any of the supported APIs returns.
+const maxLimit = 50
+
+// maxPages bounds the requests one source makes while paging through a
+// user's repositories.
+const maxPages = 10
+
+// maxResponseBytes bounds how much of a response body is read.
+const maxResponseBytes = 8 << 20
+
+const userAgent = "portfolio-site"
+
+// Source types accepted in Config.Type.
+const (
+	TypeGitLab    = "gitlab"
+	TypeGitea     = "gitea"
+	TypeForgejo   = "forgejo"
+	TypeSourceHut = "sourcehut"
+)
+
+// Config describes one extra source in showcase.yaml.
+type Config struct {
+	// Type is "gitlab", "gitea", "forgejo" or "sourcehut".
+	Type string `yaml:"type"`
+	// User is the account whose public repositories are listed.
+	User string `yaml:"user"`
+	// URL is the instance, e.g. "https://codeberg.org". GitLab and
+	// sourcehut default to gitlab.com and git.sr.ht; Gitea and Forgejo
+	// have no default.
+	URL string `yaml:"url"`
+	// Label is the badge shown on cards, e.g. "Codeberg". It defaults to
+	// the forge's name.
+	Label string `yaml:"label"`
+	// TokenEnv names an environment variable holding an API token.
+	// sourcehut requires one.
+	TokenEnv string `yaml:"tokenEnv"`
+	// Limit is how many of the most recently updated repositories to show.
+	Limit int `yaml:"limit"`
+}
+
+// Validate reports configuration mistakes without contacting the forge.
+func (c Config) Validate() error {
+	switch c.Type {
+	case TypeGitLab, TypeGitea, TypeForgejo, TypeSourceHut:
+	default:
+		return fmt.Errorf("unknown source type %q", c.Type)
+	}
+	if c.User == "" {
+		return fmt.Errorf("%s source: user is required", c.Type)
+	}
+	if (c.Type == TypeGitea || c.Type == TypeForgejo) && c.URL == "" {
+		return fmt.Errorf("%s source: url is required", c.Type)
+	}
+	if c.Type == TypeSourceHut && c.TokenEnv == "" {
+		return fmt.Errorf("sourcehut source: tokenEnv is required")
+	}
+	if c.Limit < 0 || c.Limit > maxLimit {
+		return fmt.Errorf("%s source: limit must be between 0 and %d", c.Type, maxLimit)
+	}
+	return nil
+}
+
+// New returns the source described by c, reading its token from the
+// environment. The source drops the projects opts excludes before cutting
+// its results to c.Limit.
+func New(c Config, client *http.Client, opts githubapi.FetchOptions) (ProjectSource, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	f := forge{
+		kind:    c.Type,
+		client:  client,
+		baseURL: strings.TrimSuffix(c.URL, "/"),
+		user:    c.User,
+		label:   c.Label,
+		limit:   c.Limit,
+		opts:    opts,
+	}
+	if c.TokenEnv != "" {
+		f.token = os.Getenv(c.TokenEnv)
+	}
+	if f.limit == 0 {
+		f.limit = DefaultLimit
+	}
+	switch c.Type {
+	case TypeGitLab:
+		return &GitLab{forge: f.withDefaults("https://gitlab.com", "GitLab")}, nil
+	case TypeGitea:
+		return &Gitea{forge: f.withDefaults("", "Gitea")}, nil
+	case TypeForgejo:
+		return &Gitea{forge: f.withDefaults("", "Forgejo")}, nil
+	default:
+		return &SourceHut{forge: f.withDefaults("https://git.sr.ht", "sourcehut")}, nil
+	}
+}
+
+// FromConfig returns the sources described by cfgs, in order, each
+// applying the exclusions in opts.
+func FromConfig(cfgs []Config, client *http.Client, opts githubapi.FetchOptions) ([]ProjectSource, error) {
+	out := make([]ProjectSource, 0, len(cfgs))
+	for _, c := range cfgs {
+		src, err := New(c, client, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, src)
+	}
+	return out, nil
+}
+
+// PartialError reports sources that failed while others succeeded.
+type PartialError struct {
+	Errs []error
+}
+
+func (e *PartialError) Error() string {
+	return errors.Join(e.Errs...).Error()
+}
+
+func (e *PartialError) Unwrap() []error { return e.Errs }
+
+// FetchAll fetches every source concurrently and concatenates their
+// projects in source order. Sources apply their own exclusions, before
+// their limit. The first source is the primary one: if it fails, FetchAll
+// fails. When only other sources fail, the projects fetched are returned
+// with a *PartialError.
+func FetchAll(ctx context.Context, srcs []ProjectSource) ([]githubapi.Project, error) {
+	results := make([][]githubapi.Project, len(srcs))
+	errs := make([]error, len(srcs))
+	var wg sync.WaitGroup
+	for i, src := range srcs {
+		wg.Go(func() {
+			results[i], errs[i] = src.FetchProjects(ctx)
+		})
+	}
+	wg.Wait()
+
+	if len(errs) > 0 && errs[0] != nil {
+		return nil, errs[0]
+	}
+	var projects []githubapi.Project
+	var failed []error
+	for i, res := range results {
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", srcs[i].Name(), errs[i]))
+			continue
+		}
+		projects = append(projects, res...)
+	}
+	if len(failed) > 0 {
+		return projects, &PartialError{Errs: failed}
+	}
+	return projects, nil
+}
+
+// GitHub adapts githubapi.Client to ProjectSource.
+type GitHub struct {
+	Client   *githubapi.Client
+	Username string
+	Options  githubapi.FetchOptions
+}
+
+func (g *GitHub) Name() string { return "github.com/" + g.Username }
+
+func (g *GitHub) FetchProjects(ctx context.Context) ([]githubapi.Project, error) {
+	return g.Client.FetchProjects(ctx, g.Username, g.Options)
+}
+
+// forge holds what the REST-style sources have in common.
+type forge struct {
+	// kind is the Config.Type, recorded as each project's Forge.
+	kind    string
+	client  *http.Client
+	baseURL string
+	user    string
+	token   string
+	label   string
+	limit   int
+	// opts holds the exclusion rules from showcase.yaml.
+	opts githubapi.FetchOptions
+}
+
+func (f forge) withDefaults(baseURL, label string) forge {
+	if f.baseURL == "" {
+		f.baseURL = baseURL
+	}
+	if f.label == "" {
+		f.label = label
+	}
+	return f
+}
+
+// shown drops the projects f.opts excludes and keeps the first f.limit of
+// the rest.
+func (f forge) shown(projects []githubapi.Project) []githubapi.Project {
+	projects = slices.DeleteFunc(projects, f.opts.Excluded)
+	return projects[:min(len(projects), f.limit)]
+}
+
+// Name returns the instance host and user, e.g. "gitlab.com/someone".
+func (f forge) Name() string {
+	host := f.baseURL
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	return host + "/" + f.user
+}
+
+// do sends req with the token, if any, set by auth and decodes a JSON
+// response into out.
+func (f forge) do(req *http.Request, auth func(*http.Request, string), out any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if f.token != "" {
+		auth(req, f.token)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("perform request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func bearer(req *http.Request, token string) {
+	req.Header.Set("Authorization", "Bearer "+token)
+}
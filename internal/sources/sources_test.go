@@ -0,0 +1,315 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"srv.exe.dev/internal/githubapi"
+)
+
+// newSource starts an httptest stand-in for a forge and returns the source
+// configured against it.
+func newSource(t *testing.T, c Config, h http.HandlerFunc) ProjectSource {
+	t.Helper()
+	return newSourceWithOptions(t, c, githubapi.FetchOptions{}, h)
+}
+
+// newSourceWithOptions is newSource with exclusion rules.
+func newSourceWithOptions(t *testing.T, c Config, opts githubapi.FetchOptions, h http.HandlerFunc) ProjectSource {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	c.URL = ts.URL
+	src, err := New(c, ts.Client(), opts)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	return src
+}
+
+func TestGitLab(t *testing.T) {
+	t.Setenv("TEST_GITLAB_TOKEN", "glpat")
+	src := newSource(t, Config{Type: TypeGitLab, User: "someone", TokenEnv: "TEST_GITLAB_TOKEN", Limit: 2}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/users/someone/projects" || r.URL.Query().Get("per_page") != "50" || r.URL.Query().Get("page") != "1" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "glpat" {
+			t.Errorf("PRIVATE-TOKEN = %q", got)
+		}
+		_, _ = w.Write([]byte(`[
+			{"name":"tool","namespace":{"path":"someone"},"description":"A tool","web_url":"https://gitlab.example/someone/tool",
+			 "star_count":4,"forks_count":1,"last_activity_at":"2026-03-01T10:00:00Z","topics":["go"],"archived":false},
+			{"name":"fork","namespace":{"path":"someone"},"forked_from_project":{"id":7},"archived":true}
+		]`))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(projects))
+	}
+	p := projects[0]
+	if p.Source != "GitLab" || p.Forge != TypeGitLab || p.Name != "tool" || p.Owner != "someone" || p.Stars != 4 || p.Forks != 1 ||
+		p.URL != "https://gitlab.example/someone/tool" || len(p.Topics) != 1 || p.OnGitHub() {
+		t.Fatalf("unexpected project %+v", p)
+	}
+	if !projects[1].Fork || !projects[1].Archived {
+		t.Fatalf("fork flags not mapped: %+v", projects[1])
+	}
+}
+
+func TestGitLabExcludesBeforeLimitAcrossPages(t *testing.T) {
+	opts := githubapi.FetchOptions{Exclude: []string{"skip-*"}, ExcludeForks: true}
+	var pages []string
+	src := newSourceWithOptions(t, Config{Type: TypeGitLab, User: "someone", Limit: 2}, opts, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page != "1" {
+			_, _ = w.Write([]byte(`[{"name":"keep-2","namespace":{"path":"someone"}},{"name":"unread","namespace":{"path":"someone"}}]`))
+			return
+		}
+		// A full page holding only one project that is not excluded.
+		var items []string
+		items = append(items, `{"name":"keep-1","namespace":{"path":"someone"}}`, `{"name":"a-fork","namespace":{"path":"someone"},"forked_from_project":{"id":1}}`)
+		for i := range maxLimit - 2 {
+			items = append(items, fmt.Sprintf(`{"name":"skip-%d","namespace":{"path":"someone"}}`, i))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "keep-1,keep-2" {
+		t.Fatalf("got %s, want the limit filled after exclusions", got)
+	}
+	if strings.Join(pages, ",") != "1,2" {
+		t.Fatalf("requested pages %v, want 1,2", pages)
+	}
+}
+
+func TestLabelDoesNotChangeForge(t *testing.T) {
+	src := newSource(t, Config{Type: TypeForgejo, User: "someone", Label: "GitHub"}, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"mirror","owner":{"login":"someone"}}]`))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Source != "GitHub" || projects[0].Forge != TypeForgejo || projects[0].OnGitHub() {
+		t.Fatalf("got %+v, want a Forgejo project labelled GitHub", projects)
+	}
+}
+
+func TestGiteaSortsSkipsPrivateAndLimits(t *testing.T) {
+	opts := githubapi.FetchOptions{ExcludeArchived: true}
+	src := newSourceWithOptions(t, Config{Type: TypeForgejo, User: "someone", Label: "Codeberg", Limit: 2}, opts, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/someone/repos" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("sent Authorization without a token")
+		}
+		_, _ = w.Write([]byte(`[
+			{"name":"archived","owner":{"login":"someone"},"updated_at":"2026-07-01T00:00:00Z","archived":true},
+			{"name":"old","owner":{"login":"someone"},"updated_at":"2025-01-01T00:00:00Z"},
+			{"name":"secret","owner":{"login":"someone"},"updated_at":"2026-06-01T00:00:00Z","private":true},
+			{"name":"new","owner":{"login":"someone"},"html_url":"https://codeberg.example/someone/new","language":"Rust",
+			 "stars_count":3,"updated_at":"2026-05-01T00:00:00Z","website":"https://new.example"},
+			{"name":"older","owner":{"login":"someone"},"updated_at":"2024-01-01T00:00:00Z"}
+		]`))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if strings.Join(names, ",") != "new,old" {
+		t.Fatalf("projects = %v, want new,old", names)
+	}
+	if p := projects[0]; p.Source != "Codeberg" || p.Language != "Rust" || p.Stars != 3 || p.HomepageURL != "https://new.example" {
+		t.Fatalf("unexpected project %+v", p)
+	}
+}
+
+func TestSourceHut(t *testing.T) {
+	t.Setenv("TEST_SRHT_TOKEN", "srht")
+	src := newSource(t, Config{Type: TypeSourceHut, User: "~someone", TokenEnv: "TEST_SRHT_TOKEN"}, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/query" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer srht" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Variables["username"] != "someone" {
+			t.Errorf("unexpected variables %+v, %v", req.Variables, err)
+		}
+		_, _ = w.Write([]byte(`{"data":{"user":{"repositories":{"results":[
+			{"name":"site","description":"My site","visibility":"PUBLIC","updated":"2026-02-01T00:00:00Z","owner":{"canonicalName":"~someone"}},
+			{"name":"notes","visibility":"PRIVATE","owner":{"canonicalName":"~someone"}}
+		]}}}}`))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("got %+v, want only the public repository", projects)
+	}
+	p := projects[0]
+	if p.Source != "sourcehut" || p.Owner != "someone" || !strings.HasSuffix(p.URL, "/~someone/site") {
+		t.Fatalf("unexpected project %+v", p)
+	}
+}
+
+func TestSourceHutSortsAndLimits(t *testing.T) {
+	t.Setenv("TEST_SRHT_TOKEN", "srht")
+	src := newSource(t, Config{Type: TypeSourceHut, User: "someone", TokenEnv: "TEST_SRHT_TOKEN", Limit: 2}, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"user":{"repositories":{"results":[
+			{"name":"old","visibility":"PUBLIC","updated":"2025-01-01T00:00:00Z","owner":{"canonicalName":"~someone"}},
+			{"name":"newest","visibility":"PUBLIC","updated":"2026-03-01T00:00:00Z","owner":{"canonicalName":"~someone"}},
+			{"name":"hidden","visibility":"UNLISTED","updated":"2026-04-01T00:00:00Z","owner":{"canonicalName":"~someone"}},
+			{"name":"newer","visibility":"PUBLIC","updated":"2026-02-01T00:00:00Z","owner":{"canonicalName":"~someone"}}
+		]}}}}`))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "newest,newer" {
+		t.Fatalf("got %s, want the two most recently updated public repositories", got)
+	}
+}
+
+func TestSourceHutFollowsCursor(t *testing.T) {
+	t.Setenv("TEST_SRHT_TOKEN", "srht")
+	opts := githubapi.FetchOptions{Exclude: []string{"someone/dotfiles"}}
+	var cursors []any
+	src := newSourceWithOptions(t, Config{Type: TypeSourceHut, User: "someone", TokenEnv: "TEST_SRHT_TOKEN", Limit: 2}, opts, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		cursors = append(cursors, req.Variables["cursor"])
+		if req.Variables["cursor"] == nil {
+			_, _ = w.Write([]byte(`{"data":{"user":{"repositories":{"cursor":"page-2","results":[
+				{"name":"dotfiles","visibility":"PUBLIC","updated":"2026-05-01T00:00:00Z","owner":{"canonicalName":"~someone"}},
+				{"name":"first","visibility":"PUBLIC","updated":"2026-01-01T00:00:00Z","owner":{"canonicalName":"~someone"}}
+			]}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"user":{"repositories":{"cursor":null,"results":[
+			{"name":"second","visibility":"PUBLIC","updated":"2026-02-01T00:00:00Z","owner":{"canonicalName":"~someone"}}
+		]}}}}`))
+	})
+
+	projects, err := src.FetchProjects(context.Background())
+	if err != nil {
+		t.Fatalf("FetchProjects returned error: %v", err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "second,first" {
+		t.Fatalf("got %s, want both pages sorted with the excluded repository left out", got)
+	}
+	if len(cursors) != 2 || cursors[1] != "page-2" {
+		t.Fatalf("cursors = %v, want the second request to pass page-2", cursors)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"unknown type", Config{Type: "svn", User: "u"}, "unknown source type"},
+		{"no user", Config{Type: TypeGitLab}, "user is required"},
+		{"gitea without url", Config{Type: TypeGitea, User: "u"}, "url is required"},
+		{"sourcehut without token", Config{Type: TypeSourceHut, User: "u"}, "tokenEnv is required"},
+		{"limit too large", Config{Type: TypeGitLab, User: "u", Limit: 51}, "limit must be"},
+		{"valid", Config{Type: TypeGitLab, User: "u"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Validate returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// stubSource returns fixed projects or an error.
+type stubSource struct {
+	name     string
+	projects []githubapi.Project
+	err      error
+}
+
+func (s stubSource) Name() string { return s.name }
+
+func (s stubSource) FetchProjects(context.Context) ([]githubapi.Project, error) {
+	return s.projects, s.err
+}
+
+func TestFetchAll(t *testing.T) {
+	github := stubSource{name: "github.com/u", projects: []githubapi.Project{{Name: "a", Source: githubapi.SourceGitHub}}}
+	gitlab := stubSource{name: "gitlab.com/u", projects: []githubapi.Project{{Name: "b", Source: "GitLab"}}}
+	broken := stubSource{name: "codeberg.org/u", err: errors.New("boom")}
+	projects, err := FetchAll(context.Background(), []ProjectSource{github, gitlab})
+	if err != nil || len(projects) != 2 || projects[0].Name != "a" || projects[1].Name != "b" {
+		t.Fatalf("FetchAll = %+v, %v; want a then b", projects, err)
+	}
+
+	projects, err = FetchAll(context.Background(), []ProjectSource{github, broken, gitlab})
+	var partial *PartialError
+	if !errors.As(err, &partial) || len(partial.Errs) != 1 || !strings.Contains(err.Error(), "codeberg.org/u") {
+		t.Fatalf("error = %v, want a PartialError naming the failed source", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("projects = %+v, want the ones that were fetched", projects)
+	}
+
+	primary := stubSource{name: "github.com/u", err: errors.New("rate limited")}
+	if projects, err := FetchAll(context.Background(), []ProjectSource{primary, gitlab}); err == nil || errors.As(err, &partial) || projects != nil {
+		t.Fatalf("FetchAll = %+v, %v; want the primary source's error", projects, err)
+	}
+}
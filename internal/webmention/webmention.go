@@ -0,0 +1,473 @@
+// Package webmention sends and verifies Webmentions
+// (https://www.w3.org/TR/webmention/), reading the likes, reposts and
+// replies they carry from the source page's microformats.
+package webmention
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"srv.exe.dev/db/dbgen"
+)
+
+// MaxSourceBytes caps how much of a source page is read.
+const MaxSourceBytes = 1 << 20
+
+// MaxContentLength caps the text kept from a reply, in characters.
+const MaxContentLength = 500
+
+// Type is the kind of response a mention is.
+type Type string
+
+const (
+	TypeLike    Type = "like"
+	TypeRepost  Type = "repost"
+	TypeReply   Type = "reply"
+	TypeMention Type = "mention"
+)
+
+var (
+	// ErrNoLink is returned by Verify when the source does not link to the
+	// target, as when a mention has been edited out.
+	ErrNoLink = errors.New("source does not link to target")
+	// ErrGone is returned by Verify when the source has been deleted.
+	ErrGone = errors.New("source is gone")
+	// ErrNoEndpoint is returned by Discover for pages that do not accept
+	// Webmentions.
+	ErrNoEndpoint = errors.New("no webmention endpoint")
+)
+
+// Mention is a verified Webmention.
+type Mention struct {
+	Source     string
+	Target     string
+	Type       Type
+	AuthorName string
+	AuthorURL  string
+	// Content is the plain text of a reply, or the source's title for
+	// other mentions.
+	Content string
+}
+
+// FromRows converts stored mentions.
+func FromRows(rows []dbgen.Webmention) []Mention {
+	out := make([]Mention, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, Mention{
+			Source:     r.Source,
+			Target:     r.Target,
+			Type:       Type(r.Type),
+			AuthorName: r.AuthorName,
+			AuthorURL:  r.AuthorUrl,
+			Content:    r.Content,
+		})
+	}
+	return out
+}
+
+// NewClient returns an HTTP client for fetching pages named by strangers.
+// It refuses to connect to loopback, private and other non-public
+// addresses, so a Webmention cannot make the server probe its own network.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublic(addr) {
+				return fmt.Errorf("refusing to connect to non-public address %s", addr)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
+
+// Verify fetches source and checks that it links to target, reading the
+// kind of response and its author from the h-entry around the link. It
+// returns ErrGone when the source answers 404 or 410 and ErrNoLink when it
+// no longer links to target, in which case any stored mention should be
+// removed.
+func Verify(ctx context.Context, client *http.Client, source, target string) (*Mention, error) {
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html, */*;q=0.5")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch source: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("fetch source: unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxSourceBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read source: %w", err)
+	}
+
+	m := &Mention{Source: source, Target: target, Type: TypeMention}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		// Plain text and other documents can only mention the target.
+		if !bytes.Contains(body, []byte(target)) {
+			return nil, ErrNoLink
+		}
+		m.AuthorName = sourceURL.Hostname()
+		return m, nil
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse source: %w", err)
+	}
+	var link *html.Node
+	walk(doc, func(n *html.Node) {
+		href, ok := attr(n, "href")
+		if !ok || !sameURL(resolve(sourceURL, href), target) {
+			return
+		}
+		if t := linkType(n); link == nil || rank(t) > rank(m.Type) {
+			link, m.Type = n, t
+		}
+	})
+	if link == nil {
+		return nil, ErrNoLink
+	}
+
+	entry := ancestorWithClass(link, "h-entry")
+	if entry == nil {
+		entry = find(doc, func(n *html.Node) bool { return hasClass(n, "h-entry") })
+	}
+	if entry == nil {
+		entry = doc
+	}
+	m.AuthorName, m.AuthorURL = author(entry, doc, sourceURL)
+	if m.AuthorName == "" {
+		m.AuthorName = sourceURL.Hostname()
+	}
+	if m.Type == TypeReply {
+		if content := find(entry, func(n *html.Node) bool { return hasClass(n, "e-content") || hasClass(n, "p-content") }); content != nil {
+			m.Content = truncate(text(content), MaxContentLength)
+		}
+	}
+	if m.Content == "" {
+		if name := find(entry, func(n *html.Node) bool { return hasClass(n, "p-name") }); name != nil && entry != doc {
+			m.Content = truncate(text(name), MaxContentLength)
+		} else if title := find(doc, func(n *html.Node) bool { return n.Type == html.ElementNode && n.Data == "title" }); title != nil {
+			m.Content = truncate(text(title), MaxContentLength)
+		}
+	}
+	return m, nil
+}
+
+// linkType reads the kind of response from a link's microformats classes.
+func linkType(n *html.Node) Type {
+	switch {
+	case hasClass(n, "u-like-of"):
+		return TypeLike
+	case hasClass(n, "u-repost-of"):
+		return TypeRepost
+	case hasClass(n, "u-in-reply-to"):
+		return TypeReply
+	default:
+		return TypeMention
+	}
+}
+
+// rank orders types so a page linking to the target both in passing and
+// as a reply counts as a reply.
+func rank(t Type) int {
+	switch t {
+	case TypeReply:
+		return 3
+	case TypeRepost:
+		return 2
+	case TypeLike:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// author returns the name and URL of the entry's p-author, falling back to
+// the page's first h-card.
+func author(entry, doc *html.Node, base *url.URL) (name, link string) {
+	card := find(entry, func(n *html.Node) bool { return hasClass(n, "p-author") })
+	if card == nil {
+		card = find(doc, func(n *html.Node) bool { return hasClass(n, "h-card") })
+	}
+	if card == nil {
+		return "", ""
+	}
+	name = text(card)
+	if n := find(card, func(n *html.Node) bool { return hasClass(n, "p-name") }); n != nil {
+		name = text(n)
+	}
+	if href, ok := attr(card, "href"); ok {
+		link = resolve(base, href)
+	}
+	if n := find(card, func(n *html.Node) bool { return hasClass(n, "u-url") }); n != nil {
+		if href, ok := attr(n, "href"); ok {
+			link = resolve(base, href)
+		}
+	}
+	return truncate(name, 100), link
+}
+
+// Discover finds target's Webmention endpoint from its Link header or a
+// <link> or <a> element with rel="webmention", resolved against target.
+func Discover(ctx context.Context, client *http.Client, target string) (string, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch target: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("fetch target: unexpected status %s", resp.Status)
+	}
+	// Redirects change the base the endpoint is resolved against.
+	base := resp.Request.URL
+	if base == nil {
+		base = targetURL
+	}
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			ref, params, ok := strings.Cut(link, ";")
+			ref = strings.TrimSpace(ref)
+			if !ok || !strings.HasPrefix(ref, "<") || !strings.HasSuffix(ref, ">") {
+				continue
+			}
+			if hasWebmentionRel(params) {
+				return resolve(base, strings.Trim(ref, "<>")), nil
+			}
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" {
+		return "", ErrNoEndpoint
+	}
+	doc, err := html.Parse(io.LimitReader(resp.Body, MaxSourceBytes))
+	if err != nil {
+		return "", fmt.Errorf("parse target: %w", err)
+	}
+	n := find(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode || (n.Data != "link" && n.Data != "a") {
+			return false
+		}
+		rel, _ := attr(n, "rel")
+		_, hasHref := attr(n, "href")
+		return hasHref && containsField(rel, "webmention")
+	})
+	if n == nil {
+		return "", ErrNoEndpoint
+	}
+	href, _ := attr(n, "href")
+	return resolve(base, href), nil
+}
+
+func hasWebmentionRel(params string) bool {
+	for _, p := range strings.Split(params, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "rel") && containsField(strings.Trim(value, `"`), "webmention") {
+			return true
+		}
+	}
+	return false
+}
+
+// Send notifies endpoint that source links to target.
+func Send(ctx context.Context, client *http.Client, endpoint, source, target string) error {
+	form := url.Values{"source": {source}, "target": {target}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webmention: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("send webmention: unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Links returns the absolute http and https links in an HTML fragment,
+// such as a rendered post, without duplicates and in order.
+func Links(fragment string) []string {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return nil
+	}
+	var links []string
+	seen := make(map[string]bool)
+	for _, root := range nodes {
+		walk(root, func(n *html.Node) {
+			if n.Type != html.ElementNode || n.Data != "a" {
+				return
+			}
+			href, _ := attr(n, "href")
+			u, err := url.Parse(href)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || seen[href] {
+				return
+			}
+			seen[href] = true
+			links = append(links, href)
+		})
+	}
+	return links
+}
+
+// sameURL compares URLs ignoring the case of the host and a trailing slash.
+func sameURL(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme &&
+		strings.EqualFold(ua.Host, ub.Host) &&
+		strings.TrimSuffix(ua.Path, "/") == strings.TrimSuffix(ub.Path, "/") &&
+		ua.RawQuery == ub.RawQuery
+}
+
+func resolve(base *url.URL, href string) string {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+// find returns the first node under n, n included, matching match.
+func find(n *html.Node, match func(*html.Node) bool) *html.Node {
+	if match(n) {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func ancestorWithClass(n *html.Node, class string) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if hasClass(p, class) {
+			return p
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, name string) (string, bool) {
+	if n.Type != html.ElementNode {
+		return "", false
+	}
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func hasClass(n *html.Node, class string) bool {
+	classes, _ := attr(n, "class")
+	return containsField(classes, class)
+}
+
+func containsField(list, field string) bool {
+	for _, f := range strings.Fields(list) {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// text returns the text under n with whitespace collapsed, leaving out
+// scripts and styles.
+func text(n *html.Node) string {
+	var b strings.Builder
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		case n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,136 @@
+package webmention
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const target = "https://hexsleeves.github.io/portfolio/blog/hello/"
+
+func TestVerifyReadsMicroformats(t *testing.T) {
+	pages := map[string]string{
+		"/like": `<div class="h-entry">
+			<a class="p-author h-card" href="/about"><img alt=""> Ada Lovelace</a>
+			likes <a class="u-like-of" href="https://hexsleeves.github.io/portfolio/blog/hello">this post</a>
+		</div>`,
+		"/reply": `<html><head><title>Re: hello</title></head><body>
+			<a class="h-card" href="https://bob.example"><span class="p-name">Bob</span></a>
+			<article class="h-entry">
+				<p>See <a href="` + target + `">the post</a>.</p>
+				<a class="u-in-reply-to" href="` + target + `">in reply to</a>
+				<div class="e-content"><p>Great   point,</p><p>thanks! <script>ignored()</script></p></div>
+			</article></body></html>`,
+		"/mention":  `<html><head><title>Weekly links</title></head><body><a href="` + target + `">hello</a></body></html>`,
+		"/unlinked": `<p>Nothing to see here.</p>`,
+	}
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, "see "+target)
+		default:
+			page, ok := pages[r.URL.Path]
+			if !ok {
+				http.Error(w, "boom", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = io.WriteString(w, page)
+		}
+	}))
+	defer stand.Close()
+	verify := func(path string) (*Mention, error) {
+		return Verify(context.Background(), stand.Client(), stand.URL+path, target)
+	}
+
+	m, err := verify("/like")
+	if err != nil || m.Type != TypeLike || m.AuthorName != "Ada Lovelace" || m.AuthorURL != stand.URL+"/about" {
+		t.Fatalf("like = %+v, %v", m, err)
+	}
+	m, err = verify("/reply")
+	if err != nil || m.Type != TypeReply || m.AuthorName != "Bob" || m.AuthorURL != "https://bob.example" {
+		t.Fatalf("reply = %+v, %v", m, err)
+	}
+	if m.Content != "Great point, thanks!" {
+		t.Fatalf("expected the reply's text, got %q", m.Content)
+	}
+	m, err = verify("/mention")
+	if err != nil || m.Type != TypeMention || m.Content != "Weekly links" || m.AuthorName != "127.0.0.1" {
+		t.Fatalf("mention = %+v, %v", m, err)
+	}
+	if m, err := verify("/text"); err != nil || m.Type != TypeMention {
+		t.Fatalf("plain text mention = %+v, %v", m, err)
+	}
+	if _, err := verify("/unlinked"); !errors.Is(err, ErrNoLink) {
+		t.Fatalf("expected ErrNoLink, got %v", err)
+	}
+	if _, err := verify("/gone"); !errors.Is(err, ErrGone) {
+		t.Fatalf("expected ErrGone, got %v", err)
+	}
+	if _, err := verify("/broken"); err == nil || errors.Is(err, ErrNoLink) {
+		t.Fatalf("expected a fetch error, got %v", err)
+	}
+}
+
+func TestDiscoverAndSend(t *testing.T) {
+	var got string
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/post/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = io.WriteString(w, `<a rel="nofollow webmention" href="../hook">endpoint</a>`)
+		case "/moved":
+			http.Redirect(w, r, "/post/", http.StatusFound)
+		case "/hook":
+			_ = r.ParseForm()
+			got = r.PostForm.Encode()
+			w.WriteHeader(http.StatusCreated)
+		case "/plain":
+			w.Header().Set("Content-Type", "text/plain")
+		default:
+			http.Error(w, "source does not link to target", http.StatusBadRequest)
+		}
+	}))
+	defer stand.Close()
+
+	endpoint, err := Discover(context.Background(), stand.Client(), stand.URL+"/moved")
+	if err != nil || endpoint != stand.URL+"/hook" {
+		t.Fatalf("Discover = %q, %v; want the endpoint resolved against the redirected URL", endpoint, err)
+	}
+	if _, err := Discover(context.Background(), stand.Client(), stand.URL+"/plain"); !errors.Is(err, ErrNoEndpoint) {
+		t.Fatalf("expected ErrNoEndpoint, got %v", err)
+	}
+	if err := Send(context.Background(), stand.Client(), endpoint, "https://a.example/s", "https://b.example/t"); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if got != "source=https%3A%2F%2Fa.example%2Fs&target=https%3A%2F%2Fb.example%2Ft" {
+		t.Fatalf("endpoint received %q", got)
+	}
+	if err := Send(context.Background(), stand.Client(), stand.URL+"/rejects", "s", "t"); err == nil || !strings.Contains(err.Error(), "does not link") {
+		t.Fatalf("expected the endpoint's message, got %v", err)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	got := Links(`<p><a href="https://a.example/x">a</a> <a href="/local">l</a> <a href="mailto:x@y">m</a>
+		<a href="http://b.example">b</a> <a href="https://a.example/x">again</a></p>`)
+	if strings.Join(got, " ") != "https://a.example/x http://b.example" {
+		t.Fatalf("Links = %q", got)
+	}
+}
+
+func TestNewClientRefusesPrivateAddresses(t *testing.T) {
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer stand.Close()
+	if _, err := NewClient(time.Second).Get(stand.URL); err == nil || !strings.Contains(err.Error(), "non-public") {
+		t.Fatalf("expected loopback to be refused, got %v", err)
+	}
+}
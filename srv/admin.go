@@ -0,0 +1,57 @@
+package srv
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+)
+
+// requireAdmin serves next only to requests carrying the ADMIN_USER and
+// ADMIN_PASSWORD basic auth credentials. Admin pages are hidden behind a
+// 404 when no password is configured. Browsers resend basic auth on any
+// request to the site, so state-changing requests must also come from the
+// site itself.
+func (s *Server) requireAdmin(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.adminPassword == "" {
+			http.NotFound(w, r)
+			return
+		}
+		user, password, ok := r.BasicAuth()
+		if !ok || !secretsEqual(user, s.adminUser) || !secretsEqual(password, s.adminPassword) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin", charset="UTF-8"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && !sameOrigin(r) {
+			http.Error(w, "Cross-site request refused", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		next(w, r)
+	})
+}
+
+// secretsEqual compares two credentials in constant time, hashing them
+// first so their lengths are not revealed either.
+func secretsEqual(got, want string) bool {
+	a := sha256.Sum256([]byte(got))
+	b := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// sameOrigin reports whether r was sent by a page on this site, going by
+// Sec-Fetch-Site where the browser sends it and Origin otherwise. Requests
+// with neither, such as from curl, are not from a browser and are allowed.
+func sameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin" || site == "none"
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
@@ -0,0 +1,136 @@
+package srv
+
+import (
+	"context"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/analytics"
+	"srv.exe.dev/internal/ratelimit"
+)
+
+// analyticsRollupInterval is how often page views older than the
+// retention window are rolled up.
+const analyticsRollupInterval = time.Hour
+
+// untrackedPrefixes are paths that never count as page views: assets,
+// APIs, webhooks and the site owner's own pages.
+var untrackedPrefixes = []string{"/static/", "/api/", "/hooks/", "/admin", "/dev", projectImagesPath}
+
+// trackPageViews records successful HTML page loads served by next. Bots
+// and visitors sending Do Not Track or Global Privacy Control are not
+// recorded, and no cookies are set.
+func (s *Server) trackPageViews(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.analyticsEnabled || s.DB == nil || r.Method != http.MethodGet || !tracked(r.URL.Path) ||
+			analytics.OptedOut(r) || analytics.IsBot(r.UserAgent()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == http.StatusOK && rec.html {
+			s.recordPageView(r)
+		}
+	})
+}
+
+func tracked(path string) bool {
+	for _, prefix := range untrackedPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordPageView stores a view of r's path by its visitor. Failures are
+// logged; the page has already been served.
+func (s *Server) recordPageView(r *http.Request) {
+	now := time.Now().UTC()
+	id := s.visitorHasher.VisitorID(ratelimit.ClientIP(r, s.trustedProxies), r.UserAgent(), now)
+	q := dbgen.New(s.DB)
+	if err := q.UpsertVisitor(r.Context(), dbgen.UpsertVisitorParams{ID: id, CreatedAt: now, LastSeen: now}); err != nil {
+		slog.Warn("record visitor", "error", err)
+		return
+	}
+	campaign := analytics.CampaignFrom(r.URL.Query())
+	// The request's Host header is client-supplied, so own-site navigation
+	// is judged against the configured hostname.
+	err := q.InsertPageView(r.Context(), dbgen.InsertPageViewParams{
+		VisitorID:    id,
+		Path:         analytics.Clip(r.URL.Path),
+		ReferrerHost: analytics.ReferrerHost(r.Referer(), s.Hostname),
+		UtmSource:    campaign.Source,
+		UtmMedium:    campaign.Medium,
+		UtmCampaign:  campaign.Campaign,
+		ViewedAt:     now,
+	})
+	if err != nil {
+		slog.Warn("record page view", "path", r.URL.Path, "error", err)
+		return
+	}
+	s.liveViews.publish(r.URL.Path)
+}
+
+// scheduleAnalyticsRollups rolls up old page views now and then every
+// analyticsRollupInterval until ctx is done.
+func (s *Server) scheduleAnalyticsRollups(ctx context.Context) {
+	ticker := time.NewTicker(analyticsRollupInterval)
+	defer ticker.Stop()
+	for {
+		s.rollUpAnalytics(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// rollUpAnalytics rolls up page views older than the retention window and
+// deletes visitors last seen before it.
+func (s *Server) rollUpAnalytics(ctx context.Context) {
+	if s.DB == nil {
+		return
+	}
+	res, err := analytics.RollUp(ctx, s.DB, analytics.Cutoff(s.retentionDays, time.Now()))
+	if err != nil {
+		slog.Warn("roll up page views", "error", err)
+		return
+	}
+	if res.PageViews > 0 || res.Visitors > 0 {
+		slog.Info("rolled up page views", "pageViews", res.PageViews, "visitors", res.Visitors)
+	}
+}
+
+// statusRecorder notes the status and whether the body is HTML.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	html   bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+		mediaType, _, _ := mime.ParseMediaType(r.Header().Get("Content-Type"))
+		r.html = mediaType == "text/html"
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"srv.exe.dev/internal/blog"
+	"srv.exe.dev/internal/comments"
 	"srv.exe.dev/internal/pagedata"
 )
 
@@ -51,39 +53,67 @@ func (s *Server) HandleBlogList(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) HandleBlogPost(w http.ResponseWriter, r *http.Request) {
-	slug := r.PathValue("slug")
-	if slug == "" || strings.Contains(slug, ".") {
-		http.NotFound(w, r)
+	post, ok := s.publishedPost(w, r)
+	if !ok {
 		return
 	}
+	threads := s.loadComments(r.Context(), post.Slug)
+	form := s.newCommentForm(post.Slug)
+	if r.URL.Query().Get("comment") == "pending" {
+		form.Notice = "Thanks! Your comment will appear once it has been approved."
+	}
+	if v := r.URL.Query().Get("reply"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			form.ReplyTo = comments.Find(threads, id)
+		}
+	}
+	s.renderBlogPost(w, r, post, threads, form, http.StatusOK)
+}
 
+// publishedPost loads the published post named by the slug path value,
+// answering 404 when there is none.
+func (s *Server) publishedPost(w http.ResponseWriter, r *http.Request) (*blog.Post, bool) {
+	post := s.findPublishedPost(r.PathValue("slug"))
+	if post == nil {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	return post, true
+}
+
+// findPublishedPost returns the published post with the given slug, or nil.
+func (s *Server) findPublishedPost(slug string) *blog.Post {
+	if slug == "" || strings.ContainsAny(slug, "./") {
+		return nil
+	}
 	post, err := s.loadBlogPost(slug + ".md")
 	if err != nil {
 		slog.Warn("load blog post", "error", err)
-		http.NotFound(w, r)
-		return
+		return nil
 	}
 	if !post.Published {
-		http.NotFound(w, r)
-		return
+		return nil
 	}
+	return post
+}
 
+// renderBlogPost renders a post with its comments and form.
+func (s *Server) renderBlogPost(w http.ResponseWriter, r *http.Request, post *blog.Post, threads []*comments.Comment, form *pagedata.CommentForm, status int) {
 	pd := s.newPage("blog")
 	pd.OGTitle = fmt.Sprintf("%s — Jacob LeCoq", post.Title)
 	pd.OGType = "article"
-	pd.OGPath = fmt.Sprintf("/blog/%s", slug)
+	pd.OGPath = fmt.Sprintf("/blog/%s", post.Slug)
 	if post.Description != "" {
 		pd.MetaDescription = post.Description
 	}
-
+	pd.WebmentionURL = "/webmention"
+	// Reply and confirmation views repeat the post; only it is indexed.
+	pd.NoIndex = len(r.URL.Query()) > 0 || r.Method != http.MethodGet
 	data := pagedata.BlogPageData{
 		PageData: pd,
 		Post:     post,
+		Comments: pagedata.NewCommentSection(threads, form),
+		Mentions: pagedata.NewMentionSection(s.loadWebmentions(r.Context(), post.Slug)),
 	}
-
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := s.templates.ExecuteTemplate(w, "blog_post.html", data); err != nil {
-		slog.Warn("render template", "template", "blog_post.html", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-	}
+	s.renderTemplateWithStatus(w, r, "blog_post.html", status, data)
 }
@@ -0,0 +1,176 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/comments"
+	"srv.exe.dev/internal/pagedata"
+	"srv.exe.dev/internal/ratelimit"
+)
+
+// maxCommentForm bounds a submitted comment form, comfortably above
+// comments.MaxBodyLength of multi-byte text.
+const maxCommentForm = 64 << 10
+
+// Each client may post a burst of commentRateBurst comments, then one
+// every 1/commentRate seconds.
+const (
+	commentRate      = 1.0 / 60
+	commentRateBurst = 3
+)
+
+// loadComments returns the approved comments on a post, threaded. A failed
+// query is logged and shows the post without comments.
+func (s *Server) loadComments(ctx context.Context, slug string) []*comments.Comment {
+	rows, err := dbgen.New(s.DB).ApprovedComments(ctx, slug)
+	if err != nil {
+		slog.Warn("load comments", "post", slug, "error", err)
+		return nil
+	}
+	return comments.Thread(rows)
+}
+
+// newCommentForm returns an empty comment form for the post, signed with
+// the current time.
+func (s *Server) newCommentForm(slug string) *pagedata.CommentForm {
+	return &pagedata.CommentForm{
+		Action: "/blog/" + slug + "/comments",
+		Token:  comments.NewToken(s.commentKey, slug, time.Now()),
+	}
+}
+
+// HandleCommentSubmit serves POST /blog/{slug}/comments. Comments are
+// stored unapproved and only appear once approved in /admin/comments.
+// Submissions that fill in the hidden honeypot field get the same response
+// as everyone else but are dropped.
+func (s *Server) HandleCommentSubmit(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.publishedPost(w, r)
+	if !ok {
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxCommentForm)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to read comment", http.StatusRequestEntityTooLarge)
+		return
+	}
+	ip := ratelimit.ClientIP(r, s.trustedProxies)
+	pendingURL := "/blog/" + post.Slug + "?comment=pending#comments"
+	if r.PostForm.Get("website") != "" {
+		slog.Info("dropped comment with honeypot filled", "post", post.Slug, "ip", ip)
+		http.Redirect(w, r, pendingURL, http.StatusSeeOther)
+		return
+	}
+
+	threads := s.loadComments(r.Context(), post.Slug)
+	form := s.newCommentForm(post.Slug)
+	form.Author = r.PostForm.Get("author")
+	form.Body = r.PostForm.Get("body")
+	reject := func(msg string) {
+		form.Error = msg
+		s.renderBlogPost(w, r, post, threads, form, http.StatusBadRequest)
+	}
+
+	var parentID *int64
+	if v := r.PostForm.Get("parent"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid parent comment", http.StatusBadRequest)
+			return
+		}
+		if form.ReplyTo = comments.Find(threads, id); form.ReplyTo == nil {
+			reject("The comment you replied to is no longer available.")
+			return
+		}
+		parentID = &id
+	}
+	if err := comments.CheckToken(s.commentKey, post.Slug, r.PostForm.Get("ts"), time.Now()); err != nil {
+		if errors.Is(err, comments.ErrTooFast) {
+			reject("That was quick! Please wait a few seconds and submit your comment again.")
+		} else {
+			reject("This form has expired. Please submit your comment again.")
+		}
+		return
+	}
+	author, body, err := comments.Validate(form.Author, form.Body)
+	if err != nil {
+		reject("Your comment could not be posted: " + err.Error() + ".")
+		return
+	}
+
+	id, err := dbgen.New(s.DB).InsertComment(r.Context(), dbgen.InsertCommentParams{
+		PostSlug:  post.Slug,
+		ParentID:  parentID,
+		Author:    author,
+		Body:      body,
+		Ip:        ip,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		slog.Warn("insert comment", "post", post.Slug, "error", err)
+		http.Error(w, "Failed to save comment", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("comment awaiting moderation", "post", post.Slug, "id", id, "ip", ip)
+	http.Redirect(w, r, pendingURL, http.StatusSeeOther)
+}
+
+// HandleModerationQueue serves GET /admin/comments, listing the comments
+// awaiting approval, oldest first.
+func (s *Server) HandleModerationQueue(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbgen.New(s.DB).PendingComments(r.Context())
+	if err != nil {
+		slog.Warn("load pending comments", "error", err)
+		http.Error(w, "Failed to load comments", http.StatusInternalServerError)
+		return
+	}
+	data := pagedata.ModerationPageData{PageData: s.newPage("admin")}
+	data.OGTitle = "Comments awaiting approval — Jacob LeCoq"
+	data.NoIndex = true
+	for _, row := range rows {
+		data.Pending = append(data.Pending, pagedata.PendingComment{Comment: comments.FromRow(row), IP: row.Ip})
+	}
+	s.renderTemplate(w, r, "admin_comments.html", data)
+}
+
+// HandleApproveComment serves POST /admin/comments/{id}/approve.
+func (s *Server) HandleApproveComment(w http.ResponseWriter, r *http.Request) {
+	s.moderateComment(w, r, func(q *dbgen.Queries, id int64) (int64, error) {
+		now := time.Now().UTC()
+		return q.ApproveComment(r.Context(), dbgen.ApproveCommentParams{ApprovedAt: &now, ID: id})
+	})
+}
+
+// HandleDeleteComment serves POST /admin/comments/{id}/delete. Replies to
+// the comment are deleted with it.
+func (s *Server) HandleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	s.moderateComment(w, r, func(q *dbgen.Queries, id int64) (int64, error) {
+		return q.DeleteComment(r.Context(), id)
+	})
+}
+
+// moderateComment applies a moderation action to the comment in the path
+// and returns to the queue.
+func (s *Server) moderateComment(w http.ResponseWriter, r *http.Request, action func(*dbgen.Queries, int64) (int64, error)) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	n, err := action(dbgen.New(s.DB), id)
+	if err != nil {
+		slog.Warn("moderate comment", "id", id, "error", err)
+		http.Error(w, "Failed to update comment", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	http.Redirect(w, r, "/admin/comments", http.StatusSeeOther)
+}
@@ -11,15 +11,11 @@ import (
 // BrowserLogHandler is a slog handler that broadcasts to SSE clients
 type BrowserLogHandler struct {
 	slog.Handler
-	mu      sync.RWMutex
-	clients map[chan string]struct{}
+	broadcaster
 }
 
 func NewBrowserLogHandler(base slog.Handler) *BrowserLogHandler {
-	return &BrowserLogHandler{
-		Handler: base,
-		clients: make(map[chan string]struct{}),
-	}
+	return &BrowserLogHandler{Handler: base}
 }
 
 func (h *BrowserLogHandler) Handle(ctx context.Context, r slog.Record) error {
@@ -30,33 +26,47 @@ func (h *BrowserLogHandler) Handle(ctx context.Context, r slog.Record) error {
 		return true
 	})
 
-	// Broadcast to all connected clients
-	h.mu.RLock()
-	for ch := range h.clients {
+	h.publish(msg)
+
+	// Also log to the base handler (console)
+	return h.Handler.Handle(ctx, r)
+}
+
+// broadcaster fans messages out to SSE clients. The zero value is ready
+// to use.
+type broadcaster struct {
+	mu      sync.RWMutex
+	clients map[chan string]struct{}
+}
+
+// publish sends msg to all connected clients.
+func (b *broadcaster) publish(msg string) {
+	b.mu.RLock()
+	for ch := range b.clients {
 		select {
 		case ch <- msg:
 		default:
 			// Drop if client is slow
 		}
 	}
-	h.mu.RUnlock()
-
-	// Also log to the base handler (console)
-	return h.Handler.Handle(ctx, r)
+	b.mu.RUnlock()
 }
 
-func (h *BrowserLogHandler) subscribe() chan string {
+func (b *broadcaster) subscribe() chan string {
 	ch := make(chan string, 100)
-	h.mu.Lock()
-	h.clients[ch] = struct{}{}
-	h.mu.Unlock()
+	b.mu.Lock()
+	if b.clients == nil {
+		b.clients = make(map[chan string]struct{})
+	}
+	b.clients[ch] = struct{}{}
+	b.mu.Unlock()
 	return ch
 }
 
-func (h *BrowserLogHandler) unsubscribe(ch chan string) {
-	h.mu.Lock()
-	delete(h.clients, ch)
-	h.mu.Unlock()
+func (b *broadcaster) unsubscribe(ch chan string) {
+	b.mu.Lock()
+	delete(b.clients, ch)
+	b.mu.Unlock()
 	close(ch)
 }
 
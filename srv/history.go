@@ -0,0 +1,131 @@
+package srv
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/pagedata"
+)
+
+// trendDays is how much star history the showcase cards summarise.
+const trendDays = 90
+
+// maxHistoryDays caps the ?days= window of
+// /api/projects/{forge}/{owner}/{name}/history.
+const maxHistoryDays = 366
+
+// recordProjectHistory stores today's star and fork counts for projects,
+// one row per project per UTC day, keyed by forge, owner and name. Manual
+// projects have no counts and are skipped. Nothing is recorded while
+// serving a snapshot, whose counts are from when it was taken.
+func (s *Server) recordProjectHistory(ctx context.Context, projects []githubapi.Project) {
+	if s.DB == nil || s.snapshotted {
+		return
+	}
+	now := time.Now().UTC()
+	q := dbgen.New(s.DB)
+	for _, p := range projects {
+		if p.Manual {
+			continue
+		}
+		err := q.UpsertProjectHistory(ctx, dbgen.UpsertProjectHistoryParams{
+			Forge:      cmp.Or(p.Forge, githubapi.ForgeGitHub),
+			Owner:      p.Owner,
+			Project:    p.Name,
+			Day:        now.Format(time.DateOnly),
+			Stars:      int64(p.Stars),
+			Forks:      int64(p.Forks),
+			RecordedAt: now,
+		})
+		if err != nil {
+			slog.Warn("record project history", "project", p.Key(), "error", err)
+			return
+		}
+	}
+}
+
+// loadTrends returns the showcase cards' star trends, keyed by
+// Project.Key. A failed query leaves the trends out.
+func (s *Server) loadTrends(ctx context.Context) map[string]*pagedata.Trend {
+	if s.DB == nil {
+		return nil
+	}
+	today := time.Now().UTC()
+	rows, err := dbgen.New(s.DB).AllProjectHistorySince(ctx, today.AddDate(0, 0, -trendDays).Format(time.DateOnly))
+	if err != nil {
+		slog.Warn("load project history", "error", err)
+		return nil
+	}
+	series := make(map[string][]pagedata.HistoryPoint)
+	for _, r := range rows {
+		key := githubapi.Project{Forge: r.Forge, Owner: r.Owner, Name: r.Project}.Key()
+		series[key] = append(series[key], pagedata.HistoryPoint{Date: r.Day, Stars: int(r.Stars), Forks: int(r.Forks)})
+	}
+	trends := make(map[string]*pagedata.Trend, len(series))
+	for key, points := range series {
+		if t := pagedata.NewTrend(points, today); t != nil {
+			trends[key] = t
+		}
+	}
+	return trends
+}
+
+// HandleProjectHistory serves GET /api/projects/{forge}/{owner}/{name}/history,
+// the project's daily star and fork counts over the last ?days= days (90 by
+// default). History is keyed by forge and owner as well as name, so
+// same-named repositories on other forges or accounts keep their own
+// series. The shorter /api/projects/{name}/history still serves the site
+// owner's GitHub repositories.
+func (s *Server) HandleProjectHistory(w http.ResponseWriter, r *http.Request) {
+	forge, owner, name := r.PathValue("forge"), r.PathValue("owner"), r.PathValue("name")
+	if forge == "" {
+		forge, owner = githubapi.ForgeGitHub, s.githubUser
+	}
+	days := trendDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryDays {
+			http.Error(w, "days must be between 1 and "+strconv.Itoa(maxHistoryDays), http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	rows, err := dbgen.New(s.DB).ProjectHistorySince(r.Context(), dbgen.ProjectHistorySinceParams{
+		Forge:   forge,
+		Owner:   owner,
+		Project: name,
+		Day:     time.Now().UTC().AddDate(0, 0, -days).Format(time.DateOnly),
+	})
+	if err != nil {
+		slog.Warn("load project history", "forge", forge, "owner", owner, "project", name, "error", err)
+		http.Error(w, "Failed to load history", http.StatusInternalServerError)
+		return
+	}
+	if len(rows) == 0 {
+		http.Error(w, "No history for project", http.StatusNotFound)
+		return
+	}
+	points := make([]pagedata.HistoryPoint, len(rows))
+	for i, r := range rows {
+		points[i] = pagedata.HistoryPoint{Date: r.Day, Stars: int(r.Stars), Forks: int(r.Forks)}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(struct {
+		Forge   string                  `json:"forge"`
+		Owner   string                  `json:"owner"`
+		Project string                  `json:"project"`
+		Days    int                     `json:"days"`
+		Points  []pagedata.HistoryPoint `json:"points"`
+	}{forge, owner, name, days, points}); err != nil {
+		slog.Warn("encode project history to json", "error", err)
+	}
+}
@@ -0,0 +1,50 @@
+package srv
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/imagecache"
+)
+
+// projectImagesPath is where cached social preview images are served.
+const projectImagesPath = "/static/projects/"
+
+// localizeProjectImages points projects with a custom social preview at a
+// local copy, so pages don't hot-link GitHub's CDN. Images that fail to
+// download are left out.
+func (s *Server) localizeProjectImages(ctx context.Context, projects []githubapi.Project) {
+	if s.projectImages == nil {
+		return
+	}
+	if err := s.projectImages.LocalizeProjects(ctx, projects, projectImagesPath); err != nil {
+		slog.Warn("cache project images", "error", err)
+	}
+}
+
+// localizeDetailImage is localizeProjectImages for a freshly fetched
+// detail. It runs before the detail is cached, so a page view never waits
+// on a download and a failed image is only retried with the next fetch.
+func (s *Server) localizeDetailImage(ctx context.Context, d *githubapi.ProjectDetail) {
+	if s.projectImages == nil {
+		return
+	}
+	if err := s.projectImages.LocalizeProject(ctx, &d.Project, projectImagesPath); err != nil {
+		slog.Warn("cache project images", "error", err)
+	}
+}
+
+// HandleProjectImage serves /static/projects/{file} from the image cache.
+// Names are content hashes, so responses can be cached indefinitely.
+func (s *Server) HandleProjectImage(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	if s.projectImages == nil || !imagecache.ValidName(file) {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeFile(w, r, filepath.Join(s.projectImages.Dir(), file))
+}
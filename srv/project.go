@@ -0,0 +1,197 @@
+package srv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"srv.exe.dev/internal/feed"
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/pagedata"
+	"srv.exe.dev/internal/showcase"
+)
+
+// projectDetailCache keeps the last successful detail fetch per project,
+// keyed by Project.Key, so detail pages don't spend API quota on every
+// view and survive outages.
+type projectDetailCache struct {
+	mu      sync.Mutex
+	entries map[string]projectDetailEntry
+}
+
+type projectDetailEntry struct {
+	detail    *githubapi.ProjectDetail
+	fetchedAt time.Time
+}
+
+// HandleProject serves /projects/{key...}, the detail page for a project
+// listed on the showcase, where key is the project's Key. The old
+// /projects/{name} URLs redirect there.
+func (s *Server) HandleProject(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if !strings.Contains(key, "/") {
+		s.redirectProjectName(w, r, key)
+		return
+	}
+	project, found, err := s.findShowcaseProject(r.Context(), key)
+	pd := s.newPage("showcase")
+	if err != nil && !found {
+		slog.Warn("fetch github repos", "user", s.githubUser, "error", err)
+		pd.Error = "Project details are temporarily unavailable. " + githubErrorHint(err)
+		s.renderTemplateWithStatus(w, r, "project.html", http.StatusServiceUnavailable, pagedata.ProjectPageData{PageData: pd})
+		return
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	detail, fetchedAt, err := s.loadProjectDetail(r.Context(), project)
+	if err != nil {
+		slog.Warn("fetch github project detail", "project", key, "error", err)
+		if detail == nil {
+			detail = &githubapi.ProjectDetail{Project: project}
+			pd.Error = "The README could not be loaded from GitHub right now. Please try again shortly."
+		} else {
+			pd.Error = fmt.Sprintf(
+				"GitHub is unavailable right now. Showing cached project data from %s.",
+				describeTimeSince(fetchedAt),
+			)
+		}
+	} else {
+		pd.Info = fmt.Sprintf("Last synced %s.", describeTimeSince(fetchedAt))
+	}
+	if overrides, err := showcase.LoadOverrides(filepath.Join(s.DataDir, "projects.yaml")); err != nil {
+		slog.Warn("load project overrides", "error", err)
+	} else {
+		detail = overrides.ApplyDetail(detail)
+	}
+
+	pd.OGTitle = fmt.Sprintf("%s — Jacob LeCoq", project.Name)
+	pd.MetaDescription = project.Description
+	pd.OGPath = "/projects/" + project.Key()
+	s.renderTemplate(w, r, "project.html", pagedata.NewProjectPageData(pd, detail))
+}
+
+// redirectProjectName sends a /projects/{name} URL, from before detail
+// pages were keyed by forge and owner, to the page of the one showcase
+// project with that name. Ambiguous names are not found.
+func (s *Server) redirectProjectName(w http.ResponseWriter, r *http.Request, name string) {
+	projects, err := s.showcaseProjects(r.Context())
+	var matches []githubapi.Project
+	for _, p := range projects {
+		if p.Name == name {
+			matches = append(matches, p)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		http.Redirect(w, r, "/projects/"+matches[0].Key(), http.StatusMovedPermanently)
+	case err != nil && len(projects) == 0:
+		slog.Warn("fetch github repos", "user", s.githubUser, "error", err)
+		http.Error(w, "Project details are temporarily unavailable", http.StatusServiceUnavailable)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// findShowcaseProject looks key up among the showcase projects, so only
+// curated repositories get a detail page. The error is only meaningful
+// when found is false.
+func (s *Server) findShowcaseProject(ctx context.Context, key string) (githubapi.Project, bool, error) {
+	projects, err := s.showcaseProjects(ctx)
+	for _, p := range projects {
+		if p.Key() == key {
+			return p, true, nil
+		}
+	}
+	return githubapi.Project{}, false, err
+}
+
+// showcaseProjects returns the cached showcase projects while they are
+// fresh, and loads them otherwise.
+func (s *Server) showcaseProjects(ctx context.Context) ([]githubapi.Project, error) {
+	if projects, _, ok := s.projectsCache.getFresh(projectsCacheTTL); ok {
+		return projects, nil
+	}
+	result, err := s.loadShowcaseProjects(ctx)
+	return result.projects, err
+}
+
+// HandleProjectActivityFeed serves /projects/activity.xml, an Atom feed of
+// releases and commits across the showcased projects.
+func (s *Server) HandleProjectActivityFeed(w http.ResponseWriter, r *http.Request) {
+	projects, err := s.showcaseProjects(r.Context())
+	var items []githubapi.ActivityItem
+	if len(projects) > 0 {
+		items, err = s.loadActivity(r.Context(), projects)
+	}
+	if err != nil {
+		slog.Warn("build project activity feed", "user", s.githubUser, "error", err)
+		http.Error(w, "Activity feed temporarily unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	body, err := feed.ProjectActivity(s.siteURL, items).Encode()
+	if err != nil {
+		slog.Warn("encode project activity feed", "error", err)
+		http.Error(w, "Failed to encode feed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+// loadProjectDetail returns a cached detail younger than projectsCacheTTL,
+// or fetches a fresh one. When the fetch fails, any older cached detail is
+// returned alongside the error.
+func (s *Server) loadProjectDetail(ctx context.Context, project githubapi.Project) (*githubapi.ProjectDetail, time.Time, error) {
+	if !project.OnGitHub() {
+		// Other forges only contribute the card's fields; there is no
+		// README or release to fetch.
+		return &githubapi.ProjectDetail{Project: project}, time.Now(), nil
+	}
+	cached, ok := s.projectDetails.get(project.Key())
+	if ok && time.Since(cached.fetchedAt) <= projectsCacheTTL {
+		return cached.detail, cached.fetchedAt, nil
+	}
+
+	owner := project.Owner
+	if owner == "" {
+		owner = s.githubUser
+	}
+	detail, err := s.fetchProjectDetail(ctx, owner, project.Name)
+	if err != nil {
+		return cached.detail, cached.fetchedAt, err
+	}
+	s.localizeDetailImage(ctx, detail)
+	return detail, s.projectDetails.set(project.Key(), detail), nil
+}
+
+func (c *projectDetailCache) get(key string) (projectDetailEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+func (c *projectDetailCache) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+func (c *projectDetailCache) set(key string, detail *githubapi.ProjectDetail) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]projectDetailEntry)
+	}
+	now := time.Now()
+	c.entries[key] = projectDetailEntry{detail: detail, fetchedAt: now}
+	return now
+}
@@ -0,0 +1,262 @@
+package srv
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/resume"
+)
+
+// errUnknownVariant is returned by loadResume for a variant name that is
+// not defined in resume-variants.yaml.
+var errUnknownVariant = errors.New("unknown resume variant")
+
+// resumePDFCacheSize bounds how many rendered PDFs (the full resume plus
+// its variants) are kept in memory.
+const resumePDFCacheSize = 16
+
+// languageStatsRetry is how long a failed language stats fetch is
+// remembered before GitHub is asked again.
+const languageStatsRetry = time.Minute
+
+// languageStatsCache keeps the last language breakdown shown on the
+// resume, so the resume only queries GitHub once per projectsCacheTTL.
+// Concurrent refreshes share a single fetch, and a failure holds off the
+// next one for languageStatsRetry.
+type languageStatsCache struct {
+	mu        sync.Mutex
+	langs     []githubapi.LanguageShare
+	fetchedAt time.Time
+	failedAt  time.Time
+	fetch     singleflight.Group
+}
+
+// resumePDFCache holds rendered PDFs keyed by a hash of the resume they
+// were rendered from, so the layout only runs after an edit.
+type resumePDFCache struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+// loadResume loads the resume, tailored to the named variant when variant
+// is not empty.
+func (s *Server) loadResume(variant string) (*resume.Resume, *resume.Variant, error) {
+	res, err := resume.Load(filepath.Join(s.DataDir, "resume.yaml"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if variant == "" {
+		return res, nil, nil
+	}
+	variants, err := resume.LoadVariants(filepath.Join(s.DataDir, "resume-variants.yaml"))
+	if err != nil {
+		return nil, nil, err
+	}
+	v, ok := resume.FindVariant(variants, variant)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", errUnknownVariant, variant)
+	}
+	return v.Apply(res), &v, nil
+}
+
+// HandleResume serves /resume and, through the {variant} path value,
+// /resume/{variant}.
+func (s *Server) HandleResume(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("variant")
+	res, variant, err := s.loadResume(name)
+	if errors.Is(err, errUnknownVariant) {
+		http.NotFound(w, r)
+		return
+	}
+	status := http.StatusOK
+	pd := s.newPage("resume")
+	if err != nil {
+		slog.Warn("load resume", "variant", name, "error", err)
+		status = http.StatusServiceUnavailable
+		pd.Error = "The resume is temporarily unavailable. Please try again shortly."
+	}
+	pd.OGTitle = "Resume — Jacob LeCoq"
+	pd.MetaDescription = "Resume of Jacob LeCoq, Senior Software Engineer with 8 years of full-stack experience."
+	pd.OGPath = "/resume"
+	exportBase := "/resume"
+	if name != "" {
+		// Variants are shared by link only and kept out of search results.
+		pd.OGPath = "/resume/" + name
+		pd.NoIndex = true
+		w.Header().Set("X-Robots-Tag", "noindex")
+		exportBase = "/resume/" + name + "/resume"
+	}
+	data := ResumePageData{
+		PageData:   pd,
+		Resume:     res,
+		Variant:    variant,
+		ExportBase: exportBase,
+	}
+	if err == nil {
+		data.Languages = s.resumeLanguages(r.Context())
+	}
+	s.renderTemplateWithStatus(w, r, "resume.html", status, data)
+}
+
+// HandleResumeJSON serves /resume.json and /resume/{variant}/resume.json.
+func (s *Server) HandleResumeJSON(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.loadResumeExport(w, r)
+	if !ok {
+		return
+	}
+	body, err := res.JSON()
+	if err != nil {
+		slog.Warn("encode resume to json", "error", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// HandleResumePDF serves /resume.pdf and /resume/{variant}/resume.pdf.
+func (s *Server) HandleResumePDF(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.loadResumeExport(w, r)
+	if !ok {
+		return
+	}
+	data, etag, err := s.resumePDF.get(res)
+	if err != nil {
+		slog.Warn("render resume pdf", "error", err)
+		http.Error(w, "Failed to render PDF", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="resume.pdf"`)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, "resume.pdf", time.Time{}, bytes.NewReader(data))
+}
+
+// HandleResumeText serves /resume.txt and /resume/{variant}/resume.txt.
+func (s *Server) HandleResumeText(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.loadResumeExport(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(res.Text())
+}
+
+// HandleResumeMarkdown serves /resume.md and /resume/{variant}/resume.md.
+func (s *Server) HandleResumeMarkdown(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.loadResumeExport(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	_, _ = w.Write(res.Markdown())
+}
+
+// HandleContactVCard serves /contact.vcf, the resume's contact details
+// with the profile photo embedded.
+func (s *Server) HandleContactVCard(w http.ResponseWriter, r *http.Request) {
+	res, ok := s.loadResumeExport(w, r)
+	if !ok {
+		return
+	}
+	photo, err := os.ReadFile(filepath.Join(s.StaticDir, "images", "profile.jpg"))
+	if err != nil {
+		// The card is still useful without a photo.
+		slog.Warn("read profile photo", "error", err)
+	}
+	w.Header().Set("Content-Type", "text/vcard; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="contact.vcf"`)
+	_, _ = w.Write(res.VCard(photo))
+}
+
+// loadResumeExport loads the resume (or variant) for a download handler,
+// writing an error response and returning false when it cannot.
+func (s *Server) loadResumeExport(w http.ResponseWriter, r *http.Request) (*resume.Resume, bool) {
+	name := r.PathValue("variant")
+	res, _, err := s.loadResume(name)
+	switch {
+	case errors.Is(err, errUnknownVariant):
+		http.NotFound(w, r)
+		return nil, false
+	case err != nil:
+		slog.Warn("load resume", "variant", name, "error", err)
+		http.Error(w, "Resume unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if name != "" {
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
+	return res, true
+}
+
+// resumeLanguages returns the language breakdown for the skills section,
+// or nil when it is disabled or GitHub has never answered. A failed
+// refresh keeps showing the previous breakdown; the resume never fails
+// because of GitHub.
+func (s *Server) resumeLanguages(ctx context.Context) []githubapi.LanguageShare {
+	c := &s.languageStats
+	c.mu.Lock()
+	langs := c.langs
+	fresh := !c.fetchedAt.IsZero() && time.Since(c.fetchedAt) <= projectsCacheTTL
+	failing := !c.failedAt.IsZero() && time.Since(c.failedAt) <= languageStatsRetry
+	c.mu.Unlock()
+	if fresh || failing {
+		return langs
+	}
+	// The fetch is shared with other requests, so it must not be cut short
+	// when this one goes away.
+	v, _, _ := c.fetch.Do("languages", func() (any, error) {
+		langs, err := s.fetchLanguageStats(context.WithoutCancel(ctx), s.githubUser)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err != nil {
+			slog.Warn("fetch github language stats", "user", s.githubUser, "error", err)
+			c.failedAt = time.Now()
+			return c.langs, nil
+		}
+		c.langs, c.fetchedAt, c.failedAt = langs, time.Now(), time.Time{}
+		return langs, nil
+	})
+	return v.([]githubapi.LanguageShare)
+}
+
+// get returns the PDF for res and its ETag, rendering it only when no PDF
+// of identical resume data is cached.
+func (c *resumePDFCache) get(res *resume.Resume) ([]byte, string, error) {
+	doc, err := res.JSON()
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(doc)
+	key := hex.EncodeToString(sum[:8])
+	etag := `"` + key + `"`
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if data, ok := c.docs[key]; ok {
+		return data, etag, nil
+	}
+	data, err := res.PDF()
+	if err != nil {
+		return nil, "", err
+	}
+	if c.docs == nil || len(c.docs) >= resumePDFCacheSize {
+		c.docs = make(map[string][]byte)
+	}
+	c.docs[key] = data
+	return data, etag, nil
+}
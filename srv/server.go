@@ -3,22 +3,34 @@ package srv
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
 	"net/http"
+	"net/netip"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"srv.exe.dev/db"
+	"srv.exe.dev/internal/analytics"
 	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/imagecache"
 	"srv.exe.dev/internal/pagedata"
+	"srv.exe.dev/internal/ratelimit"
+	"srv.exe.dev/internal/showcase"
+	"srv.exe.dev/internal/snapshot"
+	"srv.exe.dev/internal/sources"
+	"srv.exe.dev/internal/webmention"
 )
 
 // PageData is a convenience alias so existing code in this package compiles.
@@ -27,26 +39,130 @@ type PageData = pagedata.PageData
 // BlogPageData is a convenience alias for the blog handler.
 type BlogPageData = pagedata.BlogPageData
 
+// ResumePageData is a convenience alias for the resume handler.
+type ResumePageData = pagedata.ResumePageData
+
 type Server struct {
 	DB            *sql.DB
 	Hostname      string
 	TemplatesDir  string
 	StaticDir     string
 	PostsDir      string
+	DataDir       string
 	EnableDevLogs bool
-	templates     *template.Template
-	logHandler    *BrowserLogHandler
+	// siteURL is the absolute URL of the site root, from SITE_URL or
+	// https://Hostname. Absolute links are built from it, never from
+	// request headers.
+	siteURL    string
+	templates  *template.Template
+	logHandler *BrowserLogHandler
+	// fetchProjects returns the showcase: the user's GitHub repositories
+	// plus the extra repos and sources in showcase.yaml, less its
+	// exclusions.
 	fetchProjects func(context.Context, string) ([]githubapi.Project, error)
-	githubUser    string
-	projectsCache projectCache
+	// fetchUserProjects returns only a user's own GitHub repositories, with
+	// default options, for /api/projects.
+	fetchUserProjects func(context.Context, string) ([]githubapi.Project, error)
+	// fetchProjectDetail takes the repository owner and name.
+	fetchProjectDetail func(context.Context, string, string) (*githubapi.ProjectDetail, error)
+	fetchContributions func(context.Context, string) (*githubapi.ContributionCalendar, error)
+	fetchActivity      func(context.Context, []githubapi.Project) ([]githubapi.ActivityItem, error)
+	// fetchUpstream returns merged pull requests to other people's
+	// repositories.
+	fetchUpstream func(context.Context, string) ([]githubapi.UpstreamRepo, error)
+	// fetchLanguageStats returns nil when the resume should not show
+	// languages.
+	fetchLanguageStats func(context.Context, string) ([]githubapi.LanguageShare, error)
+	githubUser         string
+	projectsCache      projectCache
+	projectDetails     projectDetailCache
+	resumePDF          resumePDFCache
+	languageStats      languageStatsCache
+	apiProjects        apiProjectsCache
+	// apiLimiter rate-limits /api/projects and /webmention per client IP,
+	// which is read from X-Forwarded-For only behind trustedProxies.
+	apiLimiter     *ratelimit.Limiter
+	trustedProxies []netip.Prefix
+	// webhookSecret verifies /hooks/github deliveries; the endpoint is
+	// disabled without one.
+	webhookSecret string
+	// webhookBuildCommand optionally rebuilds the static site after a
+	// webhook refresh.
+	webhookBuildCommand string
+	webhookRefresh      coalescedRunner
+	webhookBuild        coalescedRunner
+	// projectImages caches custom social preview images for the cards.
+	projectImages *imagecache.Cache
+	// commentLimiter rate-limits comment submissions per client IP.
+	commentLimiter *ratelimit.Limiter
+	// commentKey signs the time-to-submit field of comment forms. It is
+	// random per process, so forms rendered before a restart expire.
+	commentKey []byte
+	// webmentionClient fetches Webmention sources; tests point it at a
+	// local stand-in.
+	webmentionClient *http.Client
+	// webmentionHosts are other hosts serving the blog, such as the
+	// static site, whose posts may be Webmention targets here.
+	webmentionHosts []string
+	webmentionQueue webmentionQueue
+	// adminUser and adminPassword guard /admin; the admin pages are
+	// disabled without a password.
+	adminUser     string
+	adminPassword string
+	// analyticsEnabled turns on cookieless page view tracking; set
+	// DISABLE_ANALYTICS to turn it off.
+	analyticsEnabled bool
+	visitorHasher    analytics.Hasher
+	// liveViews notifies the realtime stats panel of each recorded view.
+	liveViews broadcaster
+	// retentionDays is how long raw page views and visitors are kept
+	// before being rolled up into daily totals.
+	retentionDays int
+	// snapshotted is set when project data comes from PROJECTS_SNAPSHOT,
+	// so its counts are not recorded as today's star history.
+	snapshotted bool
 }
 
 const projectsCacheTTL = 15 * time.Minute
 
+// /api/projects allows a burst of apiRateBurst requests per client, then
+// one every 1/apiRate seconds.
+const (
+	apiRate      = 0.5
+	apiRateBurst = 10
+)
+
+// apiProjectsCache holds /api/projects responses per lower-cased username.
+type apiProjectsCache struct {
+	mu      sync.Mutex
+	entries map[string]apiProjectsEntry
+}
+
+type apiProjectsEntry struct {
+	projects  []githubapi.Project
+	fetchedAt time.Time
+}
+
 type projectCache struct {
 	mu        sync.RWMutex
 	projects  []githubapi.Project
 	fetchedAt time.Time
+	// invalidated is set by a webhook delivery and cleared by the next
+	// successful fetch.
+	invalidated bool
+
+	// The contribution calendar is refreshed at most once per
+	// projectsCacheTTL, since it changes slowly.
+	contributions   *githubapi.ContributionCalendar
+	contributionsAt time.Time
+
+	// Activity is refreshed on the same schedule.
+	activity   []githubapi.ActivityItem
+	activityAt time.Time
+
+	// So are merged pull requests to upstream repositories.
+	upstream   []githubapi.UpstreamRepo
+	upstreamAt time.Time
 }
 
 type showcaseProjectsResult struct {
@@ -65,18 +181,122 @@ func New(dbPath, hostname string) (*Server, error) {
 	slog.SetDefault(slog.New(logHandler))
 
 	httpClient := &http.Client{Timeout: 10 * time.Second}
+	tokens, err := githubapi.TokenSourceFromEnv(httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("configure github auth: %w", err)
+	}
+	github := githubapi.NewClientWithTokenSource(httpClient, tokens)
+
+	trustedProxies, err := ratelimit.ParsePrefixes(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("parse TRUSTED_PROXIES: %w", err)
+	}
+
+	siteURL, err := parseSiteURL(os.Getenv("SITE_URL"), hostname)
+	if err != nil {
+		return nil, fmt.Errorf("parse SITE_URL: %w", err)
+	}
+
+	retentionDays, err := analytics.ParseRetentionDays(os.Getenv("ANALYTICS_RETENTION_DAYS"))
+	if err != nil {
+		return nil, fmt.Errorf("parse ANALYTICS_RETENTION_DAYS: %w", err)
+	}
+
+	imagesDir := os.Getenv("PROJECT_IMAGES_DIR")
+	if imagesDir == "" {
+		imagesDir = filepath.Join(filepath.Dir(dbPath), "project-images")
+	}
+	projectImages, err := imagecache.New(imagesDir, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("open project image cache: %w", err)
+	}
+
+	commentKey := make([]byte, 32)
+	if _, err := rand.Read(commentKey); err != nil {
+		return nil, fmt.Errorf("generate comment form key: %w", err)
+	}
+	adminUser := os.Getenv("ADMIN_USER")
+	if adminUser == "" {
+		adminUser = "admin"
+	}
 
 	srv := &Server{
-		Hostname:      hostname,
-		TemplatesDir:  filepath.Join(baseDir, "templates"),
-		StaticDir:     filepath.Join(baseDir, "static"),
-		PostsDir:      filepath.Join(baseDir, "posts"),
-		EnableDevLogs: envEnabled("ENABLE_DEV_LOGS"),
-		logHandler:    logHandler,
-		githubUser:    "HexSleeves",
+		Hostname:            hostname,
+		TemplatesDir:        filepath.Join(baseDir, "templates"),
+		StaticDir:           filepath.Join(baseDir, "static"),
+		PostsDir:            filepath.Join(baseDir, "posts"),
+		DataDir:             filepath.Join(baseDir, "data"),
+		EnableDevLogs:       envEnabled("ENABLE_DEV_LOGS"),
+		siteURL:             siteURL,
+		logHandler:          logHandler,
+		githubUser:          "HexSleeves",
+		apiLimiter:          ratelimit.New(apiRate, apiRateBurst),
+		trustedProxies:      trustedProxies,
+		commentLimiter:      ratelimit.New(commentRate, commentRateBurst),
+		commentKey:          commentKey,
+		adminUser:           adminUser,
+		adminPassword:       os.Getenv("ADMIN_PASSWORD"),
+		analyticsEnabled:    !envEnabled("DISABLE_ANALYTICS"),
+		retentionDays:       retentionDays,
+		webmentionClient:    webmention.NewClient(10 * time.Second),
+		webmentionHosts:     webmentionHosts(os.Getenv("WEBMENTION_HOSTS")),
+		projectImages:       projectImages,
+		webhookSecret:       os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		webhookBuildCommand: os.Getenv("WEBHOOK_BUILD_COMMAND"),
 	}
 	srv.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
-		return githubapi.FetchProjects(ctx, httpClient, username, os.Getenv("GITHUB_TOKEN"))
+		// Re-read on each sync so showcase.yaml edits apply without a restart.
+		cfg, err := showcase.Load(filepath.Join(srv.DataDir, "showcase.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("load showcase config: %w", err)
+		}
+		opts := cfg.FetchOptions()
+		extra, err := sources.FromConfig(cfg.Sources, httpClient, opts)
+		if err != nil {
+			return nil, fmt.Errorf("configure project sources: %w", err)
+		}
+		srcs := append([]sources.ProjectSource{&sources.GitHub{Client: github, Username: username, Options: opts}}, extra...)
+		projects, err := sources.FetchAll(ctx, srcs)
+		var partial *sources.PartialError
+		if errors.As(err, &partial) {
+			// GitHub succeeded; show what the other forges returned.
+			slog.Warn("some project sources failed", "error", partial)
+			return projects, nil
+		}
+		return projects, err
+	}
+	srv.fetchUserProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return github.FetchProjects(ctx, username, githubapi.FetchOptions{})
+	}
+	srv.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		return github.FetchProjectDetail(ctx, owner, name)
+	}
+	srv.fetchContributions = github.FetchContributions
+	srv.fetchUpstream = github.FetchUpstreamContributions
+	srv.fetchActivity = func(ctx context.Context, projects []githubapi.Project) ([]githubapi.ActivityItem, error) {
+		cfg, err := showcase.Load(filepath.Join(srv.DataDir, "showcase.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("load showcase config: %w", err)
+		}
+		return github.FetchActivity(ctx, projects, cfg.FetchOptions())
+	}
+	srv.fetchLanguageStats = func(ctx context.Context, username string) ([]githubapi.LanguageShare, error) {
+		cfg, err := showcase.Load(filepath.Join(srv.DataDir, "showcase.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("load showcase config: %w", err)
+		}
+		if !cfg.ResumeLanguages {
+			return nil, nil
+		}
+		return github.FetchLanguageStats(ctx, username, cfg.FetchOptions())
+	}
+	if path := os.Getenv("PROJECTS_SNAPSHOT"); path != "" {
+		snap, err := snapshot.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("load projects snapshot: %w", err)
+		}
+		slog.Info("serving projects from snapshot", "path", path, "fetchedAt", snap.FetchedAt)
+		srv.useSnapshot(snap)
 	}
 	if err := srv.loadTemplates(); err != nil {
 		return nil, err
@@ -126,15 +346,12 @@ func (s *Server) HandleHome(w http.ResponseWriter, r *http.Request) {
 	s.renderTemplate(w, r, "home.html", data)
 }
 
-func (s *Server) HandleResume(w http.ResponseWriter, r *http.Request) {
-	data := s.newPage("resume")
-	data.OGTitle = "Resume — Jacob LeCoq"
-	data.MetaDescription = "Resume of Jacob LeCoq, Senior Software Engineer with 8 years of full-stack experience."
-	data.OGPath = "/resume"
-	s.renderTemplate(w, r, "resume.html", data)
-}
-
 func (s *Server) HandleShowcase(w http.ResponseWriter, r *http.Request) {
+	filter, err := showcase.ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	result, err := s.loadShowcaseProjects(r.Context())
 	status := http.StatusOK
 	infoMsg := ""
@@ -159,11 +376,25 @@ func (s *Server) HandleShowcase(w http.ResponseWriter, r *http.Request) {
 			)
 		default:
 			status = http.StatusServiceUnavailable
-			errMsg = "Projects are temporarily unavailable. Please try again shortly."
+			errMsg = "Projects are temporarily unavailable. " + githubErrorHint(err)
 		}
 	}
 	data := s.newPage("showcase")
-	data.Projects = result.projects
+	data.Projects = filter.Apply(result.projects)
+	if len(result.projects) > 0 {
+		data.Filters = pagedata.NewShowcaseFilters(result.projects, filter, func(f showcase.Filter) string {
+			return "/projects" + f.Query()
+		})
+	}
+	// Filtered views repeat the projects page; only it is indexed.
+	data.NoIndex = !filter.IsZero()
+	data.Contributions = pagedata.NewContributions(s.loadContributions(r.Context()))
+	data.Upstream = s.loadUpstream(r.Context())
+	if len(result.projects) > 0 {
+		activity, _ := s.loadActivity(r.Context(), result.projects)
+		data.Activity = pagedata.RecentActivity(activity)
+		data.Trends = s.loadTrends(r.Context())
+	}
 	data.Info = infoMsg
 	data.Error = errMsg
 	data.OGTitle = "Projects — Jacob LeCoq"
@@ -183,9 +414,20 @@ func (s *Server) HandleAPIProjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	projects, err := s.fetchProjects(r.Context(), username)
+	allowed, err := s.apiUserAllowed(username)
+	if err != nil {
+		slog.Warn("load showcase config", "error", err)
+		http.Error(w, "Failed to fetch repos", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "username not allowed", http.StatusForbidden)
+		return
+	}
+
+	projects, err := s.apiProjects.load(r.Context(), username, s.fetchUserProjects)
 	if err != nil {
-		slog.Warn("fetch github repos", "error", err)
+		slog.Warn("fetch github repos", "user", username, "error", err)
 		http.Error(w, "Failed to fetch repos", http.StatusInternalServerError)
 		return
 	}
@@ -198,6 +440,45 @@ func (s *Server) HandleAPIProjects(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// apiUserAllowed reports whether /api/projects may fetch username: the
+// site's own GitHub user or one listed under apiUsers in showcase.yaml.
+func (s *Server) apiUserAllowed(username string) (bool, error) {
+	if strings.EqualFold(username, s.githubUser) {
+		return true, nil
+	}
+	cfg, err := showcase.Load(filepath.Join(s.DataDir, "showcase.yaml"))
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(cfg.APIUsers, func(u string) bool {
+		return strings.EqualFold(u, username)
+	}), nil
+}
+
+// load returns the cached projects for username while they are younger
+// than projectsCacheTTL, and fetches them otherwise.
+func (c *apiProjectsCache) load(ctx context.Context, username string, fetch func(context.Context, string) ([]githubapi.Project, error)) ([]githubapi.Project, error) {
+	key := strings.ToLower(username)
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) <= projectsCacheTTL {
+		return e.projects, nil
+	}
+
+	projects, err := fetch(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]apiProjectsEntry)
+	}
+	c.entries[key] = apiProjectsEntry{projects: projects, fetchedAt: time.Now()}
+	return projects, nil
+}
+
 func (s *Server) setUpDatabase(dbPath string) error {
 	wdb, err := db.Open(dbPath)
 	if err != nil {
@@ -210,20 +491,44 @@ func (s *Server) setUpDatabase(dbPath string) error {
 	return nil
 }
 
-func (s *Server) routes() *http.ServeMux {
+func (s *Server) routes() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /{$}", s.HandleHome)
 	mux.HandleFunc("GET /resume", s.HandleResume)
+	mux.HandleFunc("GET /resume.json", s.HandleResumeJSON)
+	mux.HandleFunc("GET /resume.pdf", s.HandleResumePDF)
+	mux.HandleFunc("GET /resume.txt", s.HandleResumeText)
+	mux.HandleFunc("GET /resume.md", s.HandleResumeMarkdown)
+	mux.HandleFunc("GET /contact.vcf", s.HandleContactVCard)
+	mux.HandleFunc("GET /resume/{variant}", s.HandleResume)
+	mux.HandleFunc("GET /resume/{variant}/resume.json", s.HandleResumeJSON)
+	mux.HandleFunc("GET /resume/{variant}/resume.pdf", s.HandleResumePDF)
+	mux.HandleFunc("GET /resume/{variant}/resume.txt", s.HandleResumeText)
+	mux.HandleFunc("GET /resume/{variant}/resume.md", s.HandleResumeMarkdown)
 	mux.HandleFunc("GET /projects", s.HandleShowcase)
+	mux.HandleFunc("GET /projects/activity.xml", s.HandleProjectActivityFeed)
+	mux.HandleFunc("GET /projects/{key...}", s.HandleProject)
+	mux.HandleFunc("POST /hooks/github", s.HandleGitHubWebhook)
 	mux.HandleFunc("GET /blog", s.HandleBlogList)
 	mux.HandleFunc("GET /blog/{slug}", s.HandleBlogPost)
-	mux.HandleFunc("GET /api/projects", s.HandleAPIProjects)
+	mux.Handle("POST /blog/{slug}/comments", s.commentLimiter.Middleware(s.trustedProxies, http.HandlerFunc(s.HandleCommentSubmit)))
+	mux.Handle("POST /webmention", s.apiLimiter.Middleware(s.trustedProxies, http.HandlerFunc(s.HandleWebmention)))
+	mux.Handle("GET /admin/comments", s.requireAdmin(s.HandleModerationQueue))
+	mux.Handle("POST /admin/comments/{id}/approve", s.requireAdmin(s.HandleApproveComment))
+	mux.Handle("POST /admin/comments/{id}/delete", s.requireAdmin(s.HandleDeleteComment))
+	mux.Handle("GET /admin/stats", s.requireAdmin(s.HandleStats))
+	mux.Handle("GET /admin/stats/live", s.requireAdmin(s.HandleLiveStats))
+	mux.Handle("GET /admin/stats/export", s.requireAdmin(s.HandleStatsExport))
+	mux.Handle("GET /api/projects", s.apiLimiter.Middleware(s.trustedProxies, http.HandlerFunc(s.HandleAPIProjects)))
+	mux.Handle("GET /api/projects/{forge}/{owner}/{name}/history", s.apiLimiter.Middleware(s.trustedProxies, http.HandlerFunc(s.HandleProjectHistory)))
+	mux.Handle("GET /api/projects/{name}/history", s.apiLimiter.Middleware(s.trustedProxies, http.HandlerFunc(s.HandleProjectHistory)))
 	if s.EnableDevLogs {
 		mux.Handle("GET /dev/logs", s.logHandler)
 		mux.HandleFunc("GET /dev", s.HandleDevLogs)
 	}
+	mux.HandleFunc("GET "+projectImagesPath+"{file}", s.HandleProjectImage)
 	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(s.StaticDir))))
-	return mux
+	return s.trackPageViews(mux)
 }
 
 func (s *Server) Serve(addr string) error {
@@ -235,6 +540,8 @@ func (s *Server) Serve(addr string) error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	go s.scheduleAnalyticsRollups(context.Background())
+
 	slog.Info("starting server", "addr", addr)
 	return server.ListenAndServe()
 }
@@ -242,6 +549,11 @@ func (s *Server) Serve(addr string) error {
 func (s *Server) loadShowcaseProjects(ctx context.Context) (showcaseProjectsResult, error) {
 	projects, err := s.fetchProjects(ctx, s.githubUser)
 	if err == nil {
+		projects, err = s.applyProjectOverrides(projects)
+	}
+	if err == nil {
+		s.localizeProjectImages(ctx, projects)
+		s.recordProjectHistory(ctx, projects)
 		fetchedAt := s.projectsCache.set(projects)
 		return showcaseProjectsResult{projects: projects, fetchedAt: fetchedAt}, nil
 	}
@@ -266,6 +578,111 @@ func (s *Server) loadShowcaseProjects(ctx context.Context) (showcaseProjectsResu
 	return showcaseProjectsResult{}, err
 }
 
+// applyProjectOverrides merges projects.yaml into the fetched projects. It
+// is re-read on each sync so edits apply without a restart.
+func (s *Server) applyProjectOverrides(projects []githubapi.Project) ([]githubapi.Project, error) {
+	overrides, err := showcase.LoadOverrides(filepath.Join(s.DataDir, "projects.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("load project overrides: %w", err)
+	}
+	return overrides.Apply(projects), nil
+}
+
+// loadContributions returns the contribution calendar, fetching it when
+// the cached one is older than projectsCacheTTL. A failed fetch keeps the
+// previous calendar; the heatmap is simply left out when there is none.
+func (s *Server) loadContributions(ctx context.Context) *githubapi.ContributionCalendar {
+	cal, fetchedAt := s.projectsCache.contributionsSnapshot()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) <= projectsCacheTTL {
+		return cal
+	}
+	fresh, err := s.fetchContributions(ctx, s.githubUser)
+	if err != nil {
+		slog.Warn("fetch github contributions", "user", s.githubUser, "error", err)
+		return cal
+	}
+	s.projectsCache.setContributions(fresh)
+	return fresh
+}
+
+// loadUpstream returns the upstream repositories the user has contributed
+// to, fetching them when the cached ones are older than projectsCacheTTL.
+// A failed fetch keeps the previous list; the section is left out when
+// there is none.
+func (s *Server) loadUpstream(ctx context.Context) []githubapi.UpstreamRepo {
+	repos, fetchedAt := s.projectsCache.upstreamSnapshot()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) <= projectsCacheTTL {
+		return repos
+	}
+	fresh, err := s.fetchUpstream(ctx, s.githubUser)
+	if err != nil {
+		slog.Warn("fetch github upstream contributions", "user", s.githubUser, "error", err)
+		return repos
+	}
+	s.projectsCache.setUpstream(fresh)
+	return fresh
+}
+
+// loadActivity returns recent releases and commits across projects,
+// fetching them when the cached ones are older than projectsCacheTTL. A
+// failed fetch falls back to the previous items; the error is only
+// returned when there are none.
+func (s *Server) loadActivity(ctx context.Context, projects []githubapi.Project) ([]githubapi.ActivityItem, error) {
+	items, fetchedAt := s.projectsCache.activitySnapshot()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) <= projectsCacheTTL {
+		return items, nil
+	}
+	fresh, err := s.fetchActivity(ctx, projects)
+	if err != nil {
+		slog.Warn("fetch github activity", "user", s.githubUser, "error", err)
+		if fetchedAt.IsZero() {
+			return nil, err
+		}
+		return items, nil
+	}
+	s.projectsCache.setActivity(fresh)
+	return fresh, nil
+}
+
+func (c *projectCache) activitySnapshot() ([]githubapi.ActivityItem, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.activity, c.activityAt
+}
+
+func (c *projectCache) setActivity(items []githubapi.ActivityItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.activity = items
+	c.activityAt = time.Now()
+}
+
+func (c *projectCache) contributionsSnapshot() (*githubapi.ContributionCalendar, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.contributions, c.contributionsAt
+}
+
+func (c *projectCache) setContributions(cal *githubapi.ContributionCalendar) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.contributions = cal
+	c.contributionsAt = time.Now()
+}
+
+func (c *projectCache) upstreamSnapshot() ([]githubapi.UpstreamRepo, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.upstream, c.upstreamAt
+}
+
+func (c *projectCache) setUpstream(repos []githubapi.UpstreamRepo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.upstream = repos
+	c.upstreamAt = time.Now()
+}
+
 func (c *projectCache) snapshot() ([]githubapi.Project, time.Time) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -277,7 +694,10 @@ func (c *projectCache) getFresh(maxAge time.Duration) ([]githubapi.Project, time
 	if len(projects) == 0 || fetchedAt.IsZero() {
 		return nil, time.Time{}, false
 	}
-	if time.Since(fetchedAt) > maxAge {
+	c.mu.RLock()
+	invalidated := c.invalidated
+	c.mu.RUnlock()
+	if invalidated || time.Since(fetchedAt) > maxAge {
 		return nil, fetchedAt, false
 	}
 	return projects, fetchedAt, true
@@ -288,9 +708,53 @@ func (c *projectCache) set(projects []githubapi.Project) time.Time {
 	defer c.mu.Unlock()
 	c.projects = append([]githubapi.Project(nil), projects...)
 	c.fetchedAt = time.Now()
+	c.invalidated = false
 	return c.fetchedAt
 }
 
+// invalidate marks the projects as outdated, so they are fetched again
+// before being served from the cache. They still serve as a fallback if
+// that fetch fails.
+func (c *projectCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidated = true
+}
+
+// parseSiteURL returns SITE_URL without a trailing slash, or
+// https://hostname when it is not set.
+func parseSiteURL(env, hostname string) (string, error) {
+	if env == "" {
+		return "https://" + hostname, nil
+	}
+	u, err := url.Parse(env)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%q is not an absolute http or https URL", env)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// defaultWebmentionHosts is where the static build is published.
+const defaultWebmentionHosts = "hexsleeves.github.io"
+
+// webmentionHosts parses WEBMENTION_HOSTS, a comma-separated list of hosts
+// besides the server's own whose posts accept Webmentions here.
+func webmentionHosts(env string) []string {
+	if env == "" {
+		env = defaultWebmentionHosts
+	}
+	var hosts []string
+	for _, h := range strings.Split(env, ",") {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
 func envEnabled(name string) bool {
 	switch strings.ToLower(strings.TrimSpace(os.Getenv(name))) {
 	case "1", "true", "yes", "on":
@@ -300,6 +764,28 @@ func envEnabled(name string) bool {
 	}
 }
 
+// githubErrorHint explains a failed GitHub call to visitors, using the
+// client's typed errors where it can.
+func githubErrorHint(err error) string {
+	var apiErr *githubapi.APIError
+	errors.As(err, &apiErr)
+	switch {
+	case errors.Is(err, githubapi.ErrRateLimited):
+		if apiErr != nil && !apiErr.RetryAt.IsZero() {
+			return fmt.Sprintf("GitHub's API rate limit was reached; it resets in %s.", describeDuration(time.Until(apiErr.RetryAt)))
+		}
+		return "GitHub's API rate limit was reached. Please try again later."
+	case errors.Is(err, githubapi.ErrUnauthorized):
+		return "GitHub rejected the site's access token."
+	case errors.Is(err, githubapi.ErrNotFound):
+		return "The GitHub profile could not be found."
+	case errors.Is(err, githubapi.ErrUnavailable):
+		return "GitHub has been failing, so requests are paused briefly. Please try again shortly."
+	default:
+		return "Please try again shortly."
+	}
+}
+
 func describeTimeSince(t time.Time) string {
 	if t.IsZero() {
 		return "an unknown time ago"
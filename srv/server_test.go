@@ -1,17 +1,31 @@
 package srv
 
 import (
+	"bufio"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/comments"
 	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/pagedata"
+	"srv.exe.dev/internal/snapshot"
+	"srv.exe.dev/internal/sources"
 )
 
 func TestServerSetupAndHandlers(t *testing.T) {
@@ -57,6 +71,26 @@ func TestServerSetupAndHandlers(t *testing.T) {
 		if !strings.Contains(body, "Experience") {
 			t.Errorf("expected page to contain Experience section")
 		}
+		if !strings.Contains(body, "Aug 2024 — Present") {
+			t.Errorf("expected page to render work entries from resume data")
+		}
+	})
+
+	t.Run("resume json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/resume.json", nil)
+		w := httptest.NewRecorder()
+
+		server.HandleResumeJSON(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "application/json") {
+			t.Errorf("expected JSON content type, got %s", ct)
+		}
+		if !strings.Contains(w.Body.String(), `"name": "Jacob LeCoq"`) {
+			t.Errorf("expected JSON Resume basics in response")
+		}
 	})
 
 	t.Run("showcase page", func(t *testing.T) {
@@ -107,7 +141,7 @@ func TestAPIProjects(t *testing.T) {
 	})
 
 	t.Run("with username", func(t *testing.T) {
-		server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		server.fetchUserProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
 			if username != "HexSleeves" {
 				t.Fatalf("expected username HexSleeves, got %s", username)
 			}
@@ -133,6 +167,84 @@ func TestAPIProjects(t *testing.T) {
 			t.Errorf("expected JSON response to include stubbed repository")
 		}
 	})
+
+	t.Run("caches per username", func(t *testing.T) {
+		server.fetchUserProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+			t.Fatalf("expected the cached response for %s", username)
+			return nil, nil
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/projects?username=hexsleeves", nil)
+		w := httptest.NewRecorder()
+		server.HandleAPIProjects(w, req)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "runeforge") {
+			t.Fatalf("expected cached projects, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("username not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/projects?username=someone-else", nil)
+		w := httptest.NewRecorder()
+		server.HandleAPIProjects(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		var last *httptest.ResponseRecorder
+		for range apiRateBurst + 1 {
+			req := httptest.NewRequest(http.MethodGet, "/api/projects?username=HexSleeves", nil)
+			req.RemoteAddr = "203.0.113.7:4321"
+			last = httptest.NewRecorder()
+			server.routes().ServeHTTP(last, req)
+		}
+		if last.Code != http.StatusTooManyRequests || last.Header().Get("Retry-After") == "" {
+			t.Fatalf("expected 429 with Retry-After, got %d %v", last.Code, last.Header())
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/projects?username=HexSleeves", nil)
+		req.RemoteAddr = "203.0.113.8:4321"
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected another client to be served, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIProjectsLeavesOutShowcaseSources(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	hits := 0
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `[{"name":"gitlab-only","namespace":{"path":"HexSleeves"},"web_url":"https://gitlab.example/HexSleeves/gitlab-only"}]`)
+	}))
+	defer gitlab.Close()
+
+	server := newTestServer(t)
+	server.DataDir = t.TempDir()
+	config := "apiUsers: [someone-else]\nextra: [HexSleeves/extra-repo]\nsources:\n  - type: gitlab\n    user: HexSleeves\n    url: " + gitlab.URL + "\n"
+	if err := os.WriteFile(filepath.Join(server.DataDir, "showcase.yaml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server.fetchUserProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{Name: "their-repo", Owner: username, URL: "https://github.com/" + username + "/their-repo"}}, nil
+	}
+
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/projects?username=someone-else", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "their-repo") {
+		t.Fatalf("expected someone-else's repositories, got %d %q", w.Code, w.Body.String())
+	}
+	for _, unwanted := range []string{"gitlab-only", "extra-repo"} {
+		if strings.Contains(w.Body.String(), unwanted) {
+			t.Fatalf("expected no showcase source in the response, got %q", w.Body.String())
+		}
+	}
+	if hits != 0 {
+		t.Fatalf("expected the configured GitLab source not to be queried, got %d requests", hits)
+	}
 }
 
 func TestDraftBlogPostNotServed(t *testing.T) {
@@ -236,6 +348,322 @@ func TestShowcaseReturnsCachedProjectsWhenGitHubFails(t *testing.T) {
 	}
 }
 
+func TestProjectDetailPage(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{Name: "runeforge", Owner: "HexSleeves", URL: "https://github.com/HexSleeves/runeforge"}}, nil
+	}
+	detailFetches := 0
+	server.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		detailFetches++
+		if owner != "HexSleeves" || name != "runeforge" {
+			t.Fatalf("unexpected detail fetch for %s/%s", owner, name)
+		}
+		return &githubapi.ProjectDetail{
+			Project:       githubapi.Project{Name: "runeforge", Owner: "HexSleeves", Topics: []string{"roguelike"}},
+			README:        "# Runeforge\n\nSee [the guide](docs/guide.md).\n\n![demo](demo.gif)\n",
+			DefaultBranch: "main",
+			LatestRelease: &githubapi.Release{TagName: "v1.2.0", URL: "https://github.com/HexSleeves/runeforge/releases/tag/v1.2.0", PublishedAt: "2026-03-04T10:00:00Z"},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/github/HexSleeves/runeforge", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`href="https://github.com/HexSleeves/runeforge/blob/main/docs/guide.md"`,
+		`src="https://raw.githubusercontent.com/HexSleeves/runeforge/main/demo.gif"`,
+		"roguelike",
+		"v1.2.0",
+		"Mar 4, 2026",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected project page to contain %q, got %q", want, body)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects/github/HexSleeves/runeforge", nil))
+	if detailFetches != 1 {
+		t.Fatalf("expected cached detail to be reused, fetched %d times", detailFetches)
+	}
+
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects/github/HexSleeves/not-showcased", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a project outside the showcase, got %d", w.Code)
+	}
+
+	// Links from before pages were keyed by owner still work.
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects/runeforge", nil))
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/projects/github/HexSleeves/runeforge" {
+		t.Fatalf("expected /projects/runeforge to redirect, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestProjectDetailPagesAreKeyedByForgeAndOwner(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{
+			{Name: "tools", Owner: "HexSleeves", Forge: githubapi.ForgeGitHub},
+			{Name: "tools", Owner: "acme", Forge: githubapi.ForgeGitHub},
+			{Name: "tools", Owner: "hex", Forge: sources.TypeGitLab, Description: "Tools on GitLab"},
+		}, nil
+	}
+	server.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		return &githubapi.ProjectDetail{
+			Project: githubapi.Project{Name: name, Owner: owner},
+			README:  "README of " + owner + "/" + name,
+		}, nil
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	for path, want := range map[string]string{
+		"/projects/github/HexSleeves/tools": "README of HexSleeves/tools",
+		"/projects/github/acme/tools":       "README of acme/tools",
+		"/projects/gitlab/hex/tools":        "Tools on GitLab",
+	} {
+		// Twice, so the second view comes from the detail cache.
+		for range 2 {
+			if w := get(path); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), want) {
+				t.Fatalf("GET %s = %d, want it to contain %q", path, w.Code, want)
+			}
+		}
+	}
+	if w := get("/projects/tools"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected an ambiguous old URL to be not found, got %d", w.Code)
+	}
+}
+
+func TestProjectDetailFallsBackWhenGitHubFails(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{Name: "runeforge", Description: "A roguelike engine"}}, nil
+	}
+	server.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		return nil, errors.New("github down")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/github/runeforge", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "A roguelike engine") {
+		t.Fatalf("expected showcase data to be rendered without the README")
+	}
+	if !strings.Contains(body, "The README could not be loaded") {
+		t.Fatalf("expected README outage message, got %q", body)
+	}
+
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return nil, errors.New("github down")
+	}
+	setProjectsCacheAge(server, time.Hour)
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects/github/runeforge", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when the showcase cannot be loaded, got %d", w.Code)
+	}
+}
+
+func TestLanguageBreakdowns(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{
+			Name:      "runeforge",
+			Languages: []githubapi.Language{{Name: "Go", Color: "#00ADD8", Bytes: 3}, {Name: "Shell", Color: "#89e051", Bytes: 1}},
+		}}, nil
+	}
+	statsFetches := 0
+	server.fetchLanguageStats = func(ctx context.Context, username string) ([]githubapi.LanguageShare, error) {
+		statsFetches++
+		if statsFetches > 1 {
+			return nil, errors.New("github down")
+		}
+		return githubapi.AggregateLanguages([]githubapi.Project{{
+			Languages: []githubapi.Language{{Name: "TypeScript", Color: "#3178c6", Bytes: 10}},
+		}}), nil
+	}
+
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		"width: 75.00%; background-color: #00ADD8",
+		"width: 25.00%; background-color: #89e051",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase language bar to contain %q", want)
+		}
+	}
+
+	for range 2 {
+		w = httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resume", nil))
+		if !strings.Contains(w.Body.String(), "TypeScript 100.0%") {
+			t.Fatalf("expected resume to list the aggregated languages")
+		}
+	}
+	if statsFetches != 1 {
+		t.Fatalf("expected language stats to be cached, fetched %d times", statsFetches)
+	}
+
+	// Once the cache expires, a failed refresh keeps the old breakdown and
+	// is not retried on every request.
+	server.languageStats.fetchedAt = time.Now().Add(-2 * projectsCacheTTL)
+	for range 2 {
+		w = httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resume", nil))
+		if !strings.Contains(w.Body.String(), "TypeScript 100.0%") {
+			t.Fatalf("expected resume to keep the previous languages after a failure")
+		}
+	}
+	if statsFetches != 2 {
+		t.Fatalf("expected one retry after the cache expired, fetched %d times", statsFetches)
+	}
+
+	server.fetchLanguageStats = func(ctx context.Context, username string) ([]githubapi.LanguageShare, error) {
+		return nil, nil
+	}
+	server.languageStats = languageStatsCache{}
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resume", nil))
+	if strings.Contains(w.Body.String(), "Code on GitHub by language") {
+		t.Fatalf("expected no language section when stats are disabled")
+	}
+}
+
+func TestShowcaseRendersContributionHeatmap(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{Name: "runeforge"}}, nil
+	}
+	fetches := 0
+	server.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		fetches++
+		if fetches > 1 {
+			return nil, errors.New("github down")
+		}
+		return &githubapi.ContributionCalendar{Total: 3, Days: []githubapi.ContributionDay{
+			{Date: "2026-03-01", Count: 1, Level: 1},
+			{Date: "2026-03-02", Count: 2, Level: 2},
+		}}, nil
+	}
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+		body := w.Body.String()
+		for _, want := range []string{
+			`<svg class="heatmap"`,
+			"2 contributions on Mar 2, 2026",
+			"3 contributions in the last year",
+			"current streak 2 days",
+		} {
+			if !strings.Contains(body, want) {
+				t.Fatalf("expected showcase to contain %q", want)
+			}
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("expected contributions to be cached, fetched %d times", fetches)
+	}
+
+	// An expired calendar is still shown when the refresh fails.
+	server.projectsCache.mu.Lock()
+	server.projectsCache.contributionsAt = time.Now().Add(-time.Hour)
+	server.projectsCache.mu.Unlock()
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+	if fetches != 2 || !strings.Contains(w.Body.String(), `<svg class="heatmap"`) {
+		t.Fatalf("expected the previous heatmap after a failed refresh (fetches=%d)", fetches)
+	}
+}
+
+func TestProjectActivity(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{Name: "runeforge", Owner: "HexSleeves"}}, nil
+	}
+	fetches := 0
+	server.fetchActivity = func(ctx context.Context, projects []githubapi.Project) ([]githubapi.ActivityItem, error) {
+		fetches++
+		if len(projects) != 1 || projects[0].Name != "runeforge" {
+			t.Fatalf("unexpected projects %+v", projects)
+		}
+		return []githubapi.ActivityItem{
+			{
+				Kind: githubapi.ActivityRelease, Repo: "runeforge", Owner: "HexSleeves", Ref: "v1.2.0", Title: "v1.2.0",
+				Body: "Adds <b>dungeons</b>.", URL: "https://github.com/HexSleeves/runeforge/releases/tag/v1.2.0",
+				Date: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC),
+			},
+			{
+				Kind: githubapi.ActivityCommit, Repo: "runeforge", Owner: "HexSleeves", Ref: "0123456789abcdef", Title: "Fix pathfinding",
+				URL: "https://github.com/HexSleeves/runeforge/commit/0123456789abcdef", Author: "HexSleeves",
+				Date: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC),
+			},
+		}, nil
+	}
+
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+	body := w.Body.String()
+	for _, want := range []string{"Recent activity", "released", "Fix pathfinding", "0123456", "Mar 4, 2026", "Adds &lt;b&gt;dungeons&lt;/b&gt;.", `href="/projects/activity.xml"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase to contain %q", want)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/projects/activity.xml", nil)
+	// Links come from the configured site, not from request headers.
+	req.Host = "evil.example"
+	req.Header.Set("X-Forwarded-Proto", "http")
+	server.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/atom+xml") {
+		t.Fatalf("expected an Atom feed, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body = w.Body.String()
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<link href="https://test-hostname/projects/activity.xml" rel="self" type="application/atom+xml"></link>`,
+		"<updated>2026-03-04T10:00:00Z</updated>",
+		"<title>runeforge released v1.2.0</title>",
+		"<title>runeforge: Fix pathfinding</title>",
+		`<content type="text">Adds &lt;b&gt;dungeons&lt;/b&gt;.</content>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected feed to contain %q, got %s", want, body)
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("expected activity to be cached, fetched %d times", fetches)
+	}
+}
+
 func TestShowcaseDisplaysLastSyncWhenGitHubSucceeds(t *testing.T) {
 	t.Setenv("ENABLE_DEV_LOGS", "")
 	server := newTestServer(t)
@@ -304,6 +732,49 @@ func TestShowcaseReturnsServiceUnavailableWithoutCache(t *testing.T) {
 	}
 }
 
+func TestShowcaseExplainsGitHubErrors(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "rate limited",
+			err:  &githubapi.APIError{Err: githubapi.ErrRateLimited, RetryAt: time.Now().Add(30*time.Minute + time.Second)},
+			want: "rate limit was reached; it resets in 30 minutes.",
+		},
+		{
+			name: "unauthorized",
+			err:  fmt.Errorf("fetch: %w", &githubapi.APIError{Err: githubapi.ErrUnauthorized, StatusCode: http.StatusUnauthorized}),
+			want: "GitHub rejected the site",
+		},
+		{
+			name: "not found",
+			err:  &githubapi.APIError{Err: githubapi.ErrNotFound},
+			want: "The GitHub profile could not be found.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+				return nil, tt.err
+			}
+
+			w := httptest.NewRecorder()
+			server.HandleShowcase(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Fatalf("expected 503, got %d", w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Fatalf("expected %q in response body", tt.want)
+			}
+		})
+	}
+}
+
 func TestDescribeDuration(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -344,6 +815,144 @@ func TestBlogListReturnsServiceUnavailableOnLoadFailure(t *testing.T) {
 	}
 }
 
+func TestResumePDFIsCachedAndConditional(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/resume.pdf", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Fatalf("expected PDF content type, got %s", ct)
+	}
+	if !strings.HasPrefix(w.Body.String(), "%PDF-") {
+		t.Fatalf("expected PDF body")
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("expected ETag header")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/resume.pdf", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching ETag, got %d", w.Code)
+	}
+}
+
+func TestResumeExports(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+
+	tests := []struct {
+		path        string
+		contentType string
+		want        string
+	}{
+		{"/resume.txt", "text/plain; charset=utf-8", "JACOB LECOQ\n"},
+		{"/resume.md", "text/markdown; charset=utf-8", "# Jacob LeCoq\n"},
+		{"/resume/backend/resume.md", "text/markdown; charset=utf-8", "Senior Backend Engineer"},
+		{"/contact.vcf", "text/vcard; charset=utf-8", "PHOTO:data:image/jpeg;base64,"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tt.path, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Fatalf("%s: expected content type %q, got %q", tt.path, tt.contentType, ct)
+		}
+		if !strings.Contains(w.Body.String(), tt.want) {
+			t.Fatalf("%s: expected body to contain %q", tt.path, tt.want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/resume", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	for _, link := range []string{"/resume.txt", "/resume.md", "/contact.vcf"} {
+		if !strings.Contains(w.Body.String(), `href="`+link+`"`) {
+			t.Fatalf("expected resume page to link to %s", link)
+		}
+	}
+}
+
+func TestResumeVariants(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/resume/backend", http.StatusOK},
+		{"/resume/backend/resume.json", http.StatusOK},
+		{"/resume/backend/resume.pdf", http.StatusOK},
+		{"/resume/unknown", http.StatusNotFound},
+		{"/resume/unknown/resume.pdf", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Fatalf("%s: expected status %d, got %d", tt.path, tt.status, w.Code)
+		}
+		if tt.status == http.StatusOK && w.Header().Get("X-Robots-Tag") != "noindex" {
+			t.Fatalf("%s: expected variant to be marked noindex", tt.path)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/resume/backend", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	body := w.Body.String()
+	if !strings.Contains(body, `<meta name="robots" content="noindex">`) {
+		t.Fatalf("expected robots meta tag on variant page")
+	}
+	if !strings.Contains(body, "Senior Backend Engineer") {
+		t.Fatalf("expected variant label on page")
+	}
+	if !strings.Contains(body, "/resume/backend/resume.pdf") {
+		t.Fatalf("expected variant PDF download link")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/resume", nil)
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), `content="noindex"`) {
+		t.Fatalf("expected the full resume to stay indexable")
+	}
+}
+
+func TestResumeReturnsServiceUnavailableOnLoadFailure(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.DataDir = filepath.Join(t.TempDir(), "missing")
+
+	req := httptest.NewRequest(http.MethodGet, "/resume", nil)
+	w := httptest.NewRecorder()
+	server.HandleResume(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected resume to return 503 on load failure, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "The resume is temporarily unavailable") {
+		t.Fatalf("expected outage message in response body")
+	}
+}
+
 func newTestServer(t *testing.T) *Server {
 	t.Helper()
 
@@ -352,6 +961,13 @@ func newTestServer(t *testing.T) *Server {
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
+	// Keep tests off the network; tests that need activity stub it again.
+	server.fetchActivity = func(ctx context.Context, projects []githubapi.Project) ([]githubapi.ActivityItem, error) {
+		return nil, nil
+	}
+	server.fetchUpstream = func(ctx context.Context, username string) ([]githubapi.UpstreamRepo, error) {
+		return nil, nil
+	}
 	return server
 }
 
@@ -360,3 +976,962 @@ func setProjectsCacheAge(server *Server, age time.Duration) {
 	defer server.projectsCache.mu.Unlock()
 	server.projectsCache.fetchedAt = time.Now().Add(-age)
 }
+
+func TestProjectsFromOtherForges(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{
+			{Source: githubapi.SourceGitHub, Name: "runeforge", Owner: "HexSleeves", URL: "https://github.com/HexSleeves/runeforge"},
+			{Source: "Codeberg", Forge: sources.TypeForgejo, Name: "dotfiles", Owner: "hex", URL: "https://codeberg.org/hex/dotfiles", Description: "My dotfiles"},
+		}, nil
+	}
+	server.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		t.Fatalf("unexpected GitHub detail fetch for %s/%s", owner, name)
+		return nil, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	body := w.Body.String()
+	for _, want := range []string{">GitHub</span>", ">Codeberg</span>", "→ source"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase to contain %q, got %q", want, body)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/projects/forgejo/hex/dotfiles", nil)
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "My dotfiles") || strings.Contains(body, "temporarily unavailable") {
+		t.Fatalf("expected the project page without a GitHub error, got %q", body)
+	}
+}
+
+func TestShowcaseAppliesProjectOverrides(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.DataDir = t.TempDir()
+	overrides := `
+featured: [consulting-portal]
+hide: [scratch]
+overrides:
+  runeforge:
+    description: A data-driven roguelike engine
+projects:
+  - name: consulting-portal
+    description: Client portal built for a consultancy
+    source: Closed source
+    tech: [go, htmx]
+    links:
+      - label: case study
+        url: https://example.com/case-study
+`
+	if err := os.WriteFile(filepath.Join(server.DataDir, "projects.yaml"), []byte(overrides), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{
+			{Name: "runeforge", Owner: "HexSleeves", Description: "roguelike", URL: "https://github.com/HexSleeves/runeforge"},
+			{Name: "scratch", Owner: "HexSleeves"},
+		}, nil
+	}
+	server.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		if name != "runeforge" {
+			t.Fatalf("unexpected GitHub detail fetch for %s/%s", owner, name)
+		}
+		return &githubapi.ProjectDetail{Project: githubapi.Project{Name: name, Description: "roguelike"}}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/projects", nil)
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	body := w.Body.String()
+	for _, want := range []string{"A data-driven roguelike engine", ">Closed source</span>", "→ case study", "htmx"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase to contain %q, got %q", want, body)
+		}
+	}
+	if strings.Contains(body, "/projects/github/HexSleeves/scratch") {
+		t.Fatal("expected hidden project to be left out")
+	}
+	if strings.Index(body, "/projects/manual/consulting-portal") > strings.Index(body, "/projects/github/HexSleeves/runeforge") {
+		t.Fatal("expected the featured project to be listed first")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/projects/manual/consulting-portal", nil)
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Client portal built for a consultancy") {
+		t.Fatalf("expected the manual project page, got %d %q", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/projects/github/HexSleeves/runeforge", nil)
+	w = httptest.NewRecorder()
+	server.routes().ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), "A data-driven roguelike engine") {
+		t.Fatalf("expected the overridden description on the detail page, got %q", w.Body.String())
+	}
+}
+
+func TestServerServesProjectsSnapshot(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	path := filepath.Join(t.TempDir(), "projects.json")
+	snap := snapshot.New("HexSleeves")
+	snap.Projects = []githubapi.Project{{Name: "runeforge", Owner: "HexSleeves", Description: "Offline roguelike"}}
+	snap.Details[snap.Projects[0].Key()] = &githubapi.ProjectDetail{Project: snap.Projects[0], README: "Saved README text"}
+	if err := snap.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PROJECTS_SNAPSHOT", path)
+
+	server, err := New(filepath.Join(t.TempDir(), "test.sqlite3"), "test-hostname")
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	for path, want := range map[string]string{
+		"/projects":                             "Offline roguelike",
+		"/projects/github/HexSleeves/runeforge": "Saved README text",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), want) {
+			t.Fatalf("GET %s = %d, want it to contain %q: %q", path, w.Code, want, w.Body.String())
+		}
+	}
+
+	t.Setenv("PROJECTS_SNAPSHOT", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := New(filepath.Join(t.TempDir(), "test.sqlite3"), "test-hostname"); err == nil {
+		t.Fatal("expected New to fail when the snapshot cannot be read")
+	}
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.webhookSecret = "s3cret"
+	marker := filepath.Join(t.TempDir(), "built")
+	server.webhookBuildCommand = "touch " + marker
+
+	fetches := 0
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		fetches++
+		return []githubapi.Project{{Name: "runeforge", Owner: "HexSleeves", Stars: fetches}}, nil
+	}
+	server.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		return nil, nil
+	}
+	server.fetchUpstream = func(ctx context.Context, username string) ([]githubapi.UpstreamRepo, error) {
+		return []githubapi.UpstreamRepo{{Name: "golang/go"}}, nil
+	}
+
+	send := func(event, delivery, body, signature string) *httptest.ResponseRecorder {
+		t.Helper()
+		if signature == "" {
+			mac := hmac.New(sha256.New, []byte("s3cret"))
+			mac.Write([]byte(body))
+			signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		}
+		req := httptest.NewRequest(http.MethodPost, "/hooks/github", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-GitHub-Delivery", delivery)
+		req.Header.Set("X-Hub-Signature-256", signature)
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+		return w
+	}
+	push := func(ref string) string {
+		return fmt.Sprintf(`{"ref":%q,"repository":{"name":"runeforge","full_name":"HexSleeves/runeforge","default_branch":"main"}}`, ref)
+	}
+
+	if w := send("push", "d0", push("refs/heads/main"), "sha256=00"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected a bad signature to get 401, got %d", w.Code)
+	}
+	if w := send("ping", "d1", `{"zen":"Keep it simple."}`, ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "pong") {
+		t.Fatalf("expected pong, got %d %q", w.Code, w.Body.String())
+	}
+	if w := send("push", "d2", push("refs/heads/feature"), ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "ignored") {
+		t.Fatalf("expected a feature-branch push to be ignored, got %d %q", w.Code, w.Body.String())
+	}
+
+	server.projectsCache.set([]githubapi.Project{{Name: "runeforge"}})
+	ours := githubapi.Project{Name: "runeforge", Owner: "HexSleeves"}
+	theirs := githubapi.Project{Name: "runeforge", Owner: "someone-else"}
+	server.projectDetails.set(ours.Key(), &githubapi.ProjectDetail{Project: ours})
+	server.projectDetails.set(theirs.Key(), &githubapi.ProjectDetail{Project: theirs})
+	if w := send("push", "d3", push("refs/heads/main"), ""); w.Code != http.StatusAccepted {
+		t.Fatalf("expected a default-branch push to be accepted, got %d %q", w.Code, w.Body.String())
+	}
+	if _, ok := server.projectDetails.get(ours.Key()); ok {
+		t.Fatal("expected the pushed repository's detail to be invalidated")
+	}
+	if _, ok := server.projectDetails.get(theirs.Key()); !ok {
+		t.Fatal("expected a same-named repository of another owner to stay cached")
+	}
+	server.webhookRefresh.wait()
+	server.webhookBuild.wait()
+	if fetches != 1 {
+		t.Fatalf("expected one refresh, got %d", fetches)
+	}
+	if projects, _, ok := server.projectsCache.getFresh(projectsCacheTTL); !ok || projects[0].Stars != 1 {
+		t.Fatalf("expected the refreshed projects in the cache, got %+v", projects)
+	}
+	if repos, fetchedAt := server.projectsCache.upstreamSnapshot(); len(repos) != 1 || fetchedAt.IsZero() {
+		t.Fatalf("expected the refreshed upstream contributions in the cache, got %+v", repos)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("expected the build command to run: %v", err)
+	}
+
+	if w := send("push", "d3", push("refs/heads/main"), ""); !strings.Contains(w.Body.String(), "duplicate") {
+		t.Fatalf("expected a redelivery to be recognised, got %q", w.Body.String())
+	}
+	var count, handled int
+	if err := server.DB.QueryRow("SELECT COUNT(*), SUM(handled) FROM webhook_deliveries").Scan(&count, &handled); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || handled != 1 {
+		t.Fatalf("recorded %d deliveries (%d handled), want 3 (1 handled)", count, handled)
+	}
+}
+
+func TestProjectStarHistory(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		return nil, nil
+	}
+	stars := 40
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{
+			{Name: "runeforge", Owner: "HexSleeves", Stars: stars, Forks: 3},
+			{Name: "runeforge", Owner: "someone-else", Stars: 5},
+			{Name: "notes", Manual: true},
+		}, nil
+	}
+
+	// Seed earlier days as previous fetches would have.
+	q := dbgen.New(server.DB)
+	now := time.Now().UTC()
+	for _, seed := range []struct{ daysAgo, stars int }{{100, 1}, {60, 10}, {20, 30}} {
+		day := now.AddDate(0, 0, -seed.daysAgo)
+		err := q.UpsertProjectHistory(context.Background(), dbgen.UpsertProjectHistoryParams{
+			Forge: "github", Owner: "HexSleeves", Project: "runeforge", Day: day.Format(time.DateOnly), Stars: int64(seed.stars), Forks: 2, RecordedAt: day,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Two fetches on the same day leave one row, with the later counts.
+	get("/projects")
+	stars = 42
+	server.projectsCache.invalidate()
+	w := get("/projects")
+	body := w.Body.String()
+	for _, want := range []string{`class="sparkline"`, "★ &#43;12 30d · &#43;32 90d"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase to contain %q, got %s", want, body)
+		}
+	}
+
+	w = get("/api/projects/github/HexSleeves/runeforge/history?days=365")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected history, got %d %q", w.Code, w.Body.String())
+	}
+	var history struct {
+		Project string
+		Days    int
+		Points  []pagedata.HistoryPoint
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Points) != 4 || history.Points[0].Stars != 1 {
+		t.Fatalf("expected four points from the oldest, got %+v", history.Points)
+	}
+	if last := history.Points[3]; last.Date != now.Format(time.DateOnly) || last.Stars != 42 || last.Forks != 3 {
+		t.Fatalf("expected today's point to hold the latest fetch, got %+v", last)
+	}
+
+	if w := get("/api/projects/github/HexSleeves/runeforge/history"); !strings.Contains(w.Body.String(), `"days":90`) || strings.Count(w.Body.String(), `"date"`) != 3 {
+		t.Fatalf("expected the default 90-day window, got %q", w.Body.String())
+	}
+	if w := get("/api/projects/github/someone-else/runeforge/history"); strings.Count(w.Body.String(), `"date"`) != 1 || !strings.Contains(w.Body.String(), `"stars":5`) {
+		t.Fatalf("expected a same-named repository to keep its own history, got %q", w.Body.String())
+	}
+	if w := get("/api/projects/runeforge/history?days=365"); strings.Count(w.Body.String(), `"date"`) != 4 || !strings.Contains(w.Body.String(), `"owner":"HexSleeves"`) {
+		t.Fatalf("expected the short path to serve the site owner's repository, got %q", w.Body.String())
+	}
+	if w := get("/api/projects/manual/notes/history"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected manual projects to have no history, got %d", w.Code)
+	}
+	if w := get("/api/projects/github/HexSleeves/runeforge/history?days=0"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid window to be rejected, got %d", w.Code)
+	}
+}
+
+func TestShowcaseListsUpstreamContributions(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{{Name: "runeforge", Owner: "HexSleeves"}}, nil
+	}
+	server.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		return nil, nil
+	}
+	fetches := 0
+	server.fetchUpstream = func(ctx context.Context, username string) ([]githubapi.UpstreamRepo, error) {
+		fetches++
+		if fetches > 1 {
+			return nil, errors.New("search unavailable")
+		}
+		return []githubapi.UpstreamRepo{{
+			Name:  "golang/go",
+			URL:   "https://github.com/golang/go",
+			Stars: 120000,
+			PullRequests: []githubapi.PullRequest{{
+				Title:    "net/http: fix a typo",
+				Number:   12345,
+				URL:      "https://github.com/golang/go/pull/12345",
+				MergedAt: time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC),
+			}},
+		}}, nil
+	}
+
+	render := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects", nil))
+		return w.Body.String()
+	}
+	wants := []string{
+		">Contributions</h2>",
+		`href="https://github.com/golang/go"`,
+		"★ 120000",
+		"net/http: fix a typo",
+		"#12345 · merged Mar 5, 2026",
+	}
+	body := render()
+	for _, want := range wants {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase to contain %q, got %s", want, body)
+		}
+	}
+
+	render()
+	if fetches != 1 {
+		t.Fatalf("expected upstream contributions to be cached, fetched %d times", fetches)
+	}
+
+	// A failed refresh keeps the previous list.
+	server.projectsCache.mu.Lock()
+	server.projectsCache.upstreamAt = time.Now().Add(-2 * projectsCacheTTL)
+	server.projectsCache.mu.Unlock()
+	if body := render(); fetches != 2 || !strings.Contains(body, "net/http: fix a typo") {
+		t.Fatalf("expected the cached list after a failed refresh (fetches=%d)", fetches)
+	}
+}
+
+func TestShowcaseServesCachedPreviewImages(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	downloads, failures := 0, 0
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.png" {
+			failures++
+			http.Error(w, "gone", http.StatusInternalServerError)
+			return
+		}
+		downloads++
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png-bytes"))
+	}))
+	defer images.Close()
+
+	server := newTestServer(t)
+	server.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		return nil, nil
+	}
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{
+			{Name: "runeforge", Owner: "HexSleeves", OpenGraphImage: images.URL + "/custom.png", CustomOpenGraphImage: true},
+			{Name: "plain", Owner: "HexSleeves", OpenGraphImage: images.URL + "/generated.png"},
+		}, nil
+	}
+	server.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		image := images.URL + "/custom.png"
+		if name == "plain" {
+			image = images.URL + "/broken.png"
+		}
+		return &githubapi.ProjectDetail{Project: githubapi.Project{
+			Name: name, Owner: owner, OpenGraphImage: image, CustomOpenGraphImage: true,
+		}}, nil
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	body := get("/projects").Body.String()
+	if strings.Contains(body, images.URL) {
+		t.Fatalf("expected no hot-linked images, got %s", body)
+	}
+	_, rest, ok := strings.Cut(body, `<img src="/static/projects/`)
+	if !ok || strings.Count(body, "<img ") != 1 {
+		t.Fatalf("expected one card image served locally, got %s", body)
+	}
+	src := "/static/projects/" + rest[:strings.Index(rest, `"`)]
+
+	if page := get("/projects/github/HexSleeves/runeforge").Body.String(); !strings.Contains(page, `src="`+src+`"`) {
+		t.Fatalf("expected the detail page to use %s, got %s", src, page)
+	}
+	get("/projects/github/HexSleeves/runeforge")
+	if downloads != 1 {
+		t.Fatalf("expected the image to be downloaded once, got %d", downloads)
+	}
+
+	// A failed download is not retried on every view of the cached detail.
+	for range 2 {
+		if w := get("/projects/github/HexSleeves/plain"); w.Code != http.StatusOK || strings.Contains(w.Body.String(), images.URL) {
+			t.Fatalf("expected the detail page without the broken image, got %d %s", w.Code, w.Body.String())
+		}
+	}
+	if failures != 1 {
+		t.Fatalf("expected one attempt at the broken image, got %d", failures)
+	}
+
+	w := get(src)
+	if w.Code != http.StatusOK || w.Body.String() != "png-bytes" || !strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+		t.Fatalf("expected the cached image, got %d %q %v", w.Code, w.Body.String(), w.Header())
+	}
+	if w := get("/static/projects/index.json"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected only cached images to be served, got %d", w.Code)
+	}
+}
+
+func TestShowcaseFiltersAndSorts(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		return nil, nil
+	}
+	server.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		return []githubapi.Project{
+			{Name: "runeforge", Owner: "HexSleeves", Language: "Go", Topics: []string{"game"}, Stars: 5},
+			{Name: "atlas", Owner: "HexSleeves", Language: "TypeScript", Topics: []string{"web"}, Stars: 20},
+			{Name: "mcp", Owner: "HexSleeves", Language: "Go", Topics: []string{"cli"}, Stars: 9},
+		}, nil
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	order := func(body string) []string {
+		var names []string
+		for _, name := range []string{"runeforge", "atlas", "mcp"} {
+			if i := strings.Index(body, `/projects/github/HexSleeves/`+name+`"`); i >= 0 {
+				names = append(names, fmt.Sprintf("%08d:%s", i, name))
+			}
+		}
+		slices.Sort(names)
+		for i, n := range names {
+			_, names[i], _ = strings.Cut(n, ":")
+		}
+		return names
+	}
+
+	body := get("/projects").Body.String()
+	for _, want := range []string{
+		`href="/projects?lang=Go"`,
+		`>Go · 2</a>`,
+		`href="/projects?topic=cli"`,
+		`href="/projects?sort=stars"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected showcase to contain %q, got %s", want, body)
+		}
+	}
+	if strings.Contains(body, `content="noindex"`) {
+		t.Fatal("expected the unfiltered page to be indexed")
+	}
+
+	body = get("/projects?lang=go&sort=stars").Body.String()
+	if got := order(body); !slices.Equal(got, []string{"mcp", "runeforge"}) {
+		t.Fatalf("expected Go projects by stars, got %v", got)
+	}
+	for _, want := range []string{
+		// The active chip clears itself but keeps the sort.
+		`href="/projects?sort=stars" class="px-2 py-0.5 rounded bg-paper-200 dark:bg-paper-800" aria-current="true">Go · 2</a>`,
+		`content="noindex"`,
+		`>clear</a>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected filtered showcase to contain %q, got %s", want, body)
+		}
+	}
+
+	if got := order(get("/projects?sort=name").Body.String()); !slices.Equal(got, []string{"atlas", "mcp", "runeforge"}) {
+		t.Fatalf("expected projects by name, got %v", got)
+	}
+	if body := get("/projects?topic=rust").Body.String(); !strings.Contains(body, "No projects match these filters.") {
+		t.Fatalf("expected an empty filter message, got %s", body)
+	}
+	if w := get("/projects?sort=forks"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown sort to be rejected, got %d", w.Code)
+	}
+}
+
+func TestBlogComments(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.PostsDir = t.TempDir()
+	server.adminPassword = "hunter2"
+	post := "---\ntitle: Hello\ndate: 2026-01-01\npublished: true\n---\nHello, world.\n"
+	if err := os.WriteFile(filepath.Join(server.PostsDir, "hello.md"), []byte(post), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := server.routes()
+	send := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		return w
+	}
+	submit := func(form url.Values, ip string) *httptest.ResponseRecorder {
+		t.Helper()
+		if !form.Has("ts") {
+			form.Set("ts", comments.NewToken(server.commentKey, "hello", time.Now().Add(-time.Minute)))
+		}
+		req := httptest.NewRequest(http.MethodPost, "/blog/hello/comments", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = ip + ":1234"
+		return send(req)
+	}
+	admin := func(method, path string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		req.SetBasicAuth("admin", "hunter2")
+		return send(req)
+	}
+	get := func(path string) string {
+		t.Helper()
+		return send(httptest.NewRequest(http.MethodGet, path, nil)).Body.String()
+	}
+
+	body := get("/blog/hello")
+	if !strings.Contains(body, `action="/blog/hello/comments"`) || !strings.Contains(body, "No comments yet.") {
+		t.Fatalf("expected an empty comment section with a form, got %s", body)
+	}
+
+	w := submit(url.Values{"author": {"ada"}, "body": {"Nice **post** <script>x</script>"}}, "192.0.2.1")
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/blog/hello?comment=pending#comments" {
+		t.Fatalf("expected a redirect to the pending notice, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if body := get("/blog/hello?comment=pending"); strings.Contains(body, "Nice") || !strings.Contains(body, "once it has been approved") {
+		t.Fatalf("expected the comment to stay hidden until approved, got %s", body)
+	}
+
+	// Bots that fill the honeypot or post straight away are turned away.
+	if w := submit(url.Values{"author": {"bot"}, "body": {"spam"}, "website": {"http://spam.example"}}, "192.0.2.2"); w.Code != http.StatusSeeOther {
+		t.Fatalf("expected the honeypot to look like success, got %d", w.Code)
+	}
+	fast := url.Values{"author": {"bot"}, "body": {"spam"}, "ts": {comments.NewToken(server.commentKey, "hello", time.Now())}}
+	if w := submit(fast, "192.0.2.2"); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "That was quick!") {
+		t.Fatalf("expected an instant submission to be rejected, got %d", w.Code)
+	}
+	if w := submit(url.Values{"author": {"bob"}, "body": {"  "}}, "192.0.2.2"); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "the comment is empty") {
+		t.Fatalf("expected an empty comment to be rejected, got %d", w.Code)
+	}
+	if w := submit(url.Values{"author": {"bob"}, "body": {"hi"}}, "192.0.2.2"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the client's fourth submission to be rate-limited, got %d", w.Code)
+	}
+
+	if w := send(httptest.NewRequest(http.MethodGet, "/admin/comments", nil)); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the queue to require credentials, got %d", w.Code)
+	}
+	queue := admin(http.MethodGet, "/admin/comments").Body.String()
+	if !strings.Contains(queue, "Nice <strong>post</strong>") || !strings.Contains(queue, "192.0.2.1") || strings.Contains(queue, "spam") {
+		t.Fatalf("expected only ada's comment in the queue, got %s", queue)
+	}
+	var id int64
+	if err := server.DB.QueryRow("SELECT id FROM comments WHERE author = 'ada'").Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	crossSite := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/admin/comments/%d/approve", id), nil)
+	crossSite.SetBasicAuth("admin", "hunter2")
+	crossSite.Header.Set("Sec-Fetch-Site", "cross-site")
+	if w := send(crossSite); w.Code != http.StatusForbidden {
+		t.Fatalf("expected a cross-site approval to be refused, got %d", w.Code)
+	}
+	if w := admin(http.MethodPost, fmt.Sprintf("/admin/comments/%d/approve", id)); w.Code != http.StatusSeeOther {
+		t.Fatalf("expected approval to redirect, got %d", w.Code)
+	}
+
+	body = get("/blog/hello")
+	if !strings.Contains(body, "Nice <strong>post</strong>") || strings.Contains(body, "<script>x") || !strings.Contains(body, "Comments · 1") {
+		t.Fatalf("expected the approved comment, rendered safely, got %s", body)
+	}
+	if body := get(fmt.Sprintf("/blog/hello?reply=%d", id)); !strings.Contains(body, "Replying to ada") || !strings.Contains(body, fmt.Sprintf(`name="parent" value="%d"`, id)) {
+		t.Fatalf("expected a reply form, got %s", body)
+	}
+	if w := submit(url.Values{"author": {"cy"}, "body": {"Agreed"}, "parent": {fmt.Sprint(id)}}, "192.0.2.3"); w.Code != http.StatusSeeOther {
+		t.Fatalf("expected the reply to be accepted, got %d %s", w.Code, w.Body.String())
+	}
+	if w := submit(url.Values{"author": {"cy"}, "body": {"Hm"}, "parent": {"9999"}}, "192.0.2.3"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected a reply to an unknown comment to be rejected, got %d", w.Code)
+	}
+	var reply int64
+	if err := server.DB.QueryRow("SELECT id FROM comments WHERE author = 'cy'").Scan(&reply); err != nil {
+		t.Fatal(err)
+	}
+	admin(http.MethodPost, fmt.Sprintf("/admin/comments/%d/approve", reply))
+	body = get("/blog/hello")
+	if i, j := strings.Index(body, `class="comment-replies`), strings.Index(body, "Agreed"); i < 0 || j < i {
+		t.Fatalf("expected the reply nested under its parent, got %s", body)
+	}
+
+	// Hold several pooled connections, so the deletion runs on yet another
+	// one; each must enforce foreign keys for the replies to cascade.
+	ctx := context.Background()
+	for range 3 {
+		conn, err := server.DB.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		var on int
+		if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&on); err != nil || on != 1 {
+			t.Fatalf("expected foreign keys on every connection, got %d, %v", on, err)
+		}
+	}
+	if w := admin(http.MethodPost, fmt.Sprintf("/admin/comments/%d/delete", id)); w.Code != http.StatusSeeOther {
+		t.Fatalf("expected deletion to redirect, got %d", w.Code)
+	}
+	var replies int
+	if err := server.DB.QueryRow("SELECT COUNT(*) FROM comments WHERE parent_id = ?", id).Scan(&replies); err != nil || replies != 0 {
+		t.Fatalf("expected replies to be deleted with their parent, got %d, %v", replies, err)
+	}
+	if body := get("/blog/hello"); strings.Contains(body, "Agreed") {
+		t.Fatal("expected replies to be deleted with their parent")
+	}
+
+	server.adminPassword = ""
+	if w := admin(http.MethodGet, "/admin/comments"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected admin pages to be hidden without a password, got %d", w.Code)
+	}
+}
+
+func TestWebmentions(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	server := newTestServer(t)
+	server.PostsDir = t.TempDir()
+	post := "---\ntitle: Hello\ndate: 2026-01-01\npublished: true\n---\nHello, world.\n"
+	if err := os.WriteFile(filepath.Join(server.PostsDir, "hello.md"), []byte(post), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pages := map[string]string{
+		"/like":  `<div class="h-entry"><a class="p-author h-card" href="https://ada.example">Ada</a> liked <a class="u-like-of" href="https://hexsleeves.github.io/portfolio/blog/hello/">this</a></div>`,
+		"/reply": `<div class="h-entry"><a class="p-author h-card" href="https://bob.example">Bob</a><a class="u-in-reply-to" href="https://test-hostname/blog/hello">re</a><p class="e-content">Lovely <b>post</b></p></div>`,
+	}
+	stand := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = io.WriteString(w, pages[r.URL.Path])
+	}))
+	defer stand.Close()
+	server.webmentionClient = stand.Client()
+
+	send := func(source, target string) *httptest.ResponseRecorder {
+		t.Helper()
+		form := url.Values{"source": {source}, "target": {target}}
+		req := httptest.NewRequest(http.MethodPost, "/webmention", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+		return w
+	}
+	get := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/blog/hello", nil))
+		return w.Body.String()
+	}
+
+	if body := get(); !strings.Contains(body, `<link rel="webmention" href="/webmention">`) || strings.Contains(body, "Around the web") {
+		t.Fatalf("expected the endpoint to be advertised and no mentions yet, got %s", body)
+	}
+	for _, target := range []string{"https://test-hostname/blog/missing", "https://elsewhere.example/blog/hello", "ftp://test-hostname/blog/hello", stand.URL + "/like"} {
+		if w := send(stand.URL+"/like", target); w.Code != http.StatusBadRequest {
+			t.Fatalf("expected target %s to be rejected, got %d", target, w.Code)
+		}
+	}
+	// The request's Host header is not one of the site's hosts.
+	if w := send(stand.URL+"/reply", "http://example.com/blog/hello"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected a target on the request's Host to be rejected, got %d", w.Code)
+	}
+
+	if w := send(stand.URL+"/like", "https://hexsleeves.github.io/portfolio/blog/hello/"); w.Code != http.StatusAccepted {
+		t.Fatalf("expected the like to be accepted, got %d %s", w.Code, w.Body.String())
+	}
+	if w := send(stand.URL+"/reply", "https://test-hostname/blog/hello"); w.Code != http.StatusAccepted {
+		t.Fatalf("expected the reply to be accepted, got %d %s", w.Code, w.Body.String())
+	}
+	server.webmentionQueue.wait()
+	body := get()
+	for _, want := range []string{"1 like:", `href="https://ada.example"`, "Bob</a> replied", "Lovely post"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected the post to show %q, got %s", want, body)
+		}
+	}
+
+	// A source that drops its link is removed on the next Webmention.
+	pages["/reply"] = `<p>Never mind.</p>`
+	send(stand.URL+"/reply", "https://test-hostname/blog/hello")
+	server.webmentionQueue.wait()
+	if body := get(); strings.Contains(body, "Lovely post") || !strings.Contains(body, "1 like:") {
+		t.Fatalf("expected only the reply to be removed, got %s", body)
+	}
+}
+
+func TestPageViewAnalytics(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	t.Setenv("DISABLE_ANALYTICS", "")
+	server := newTestServer(t)
+	const browser = "Mozilla/5.0 (X11; Linux x86_64; rv:140.0) Gecko/20100101 Firefox/140.0"
+
+	visit := func(target string, header http.Header) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header = header
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := visit("/?utm_source=newsletter&utm_medium=email&utm_campaign=launch", http.Header{
+		"User-Agent": {browser},
+		"Referer":    {"https://www.news.example/item?id=1"},
+	}); code != http.StatusOK {
+		t.Fatalf("GET / = %d", code)
+	}
+	visit("/blog", http.Header{"User-Agent": {browser}, "Referer": {"https://test-hostname/"}})
+	// The request's Host doesn't make a referrer the site's own.
+	visit("/", http.Header{"User-Agent": {browser}, "Referer": {"http://example.com/"}})
+	// None of these count.
+	visit("/resume", http.Header{"User-Agent": {browser}, "Dnt": {"1"}})
+	visit("/resume", http.Header{"User-Agent": {browser}, "Sec-Gpc": {"1"}})
+	visit("/resume", http.Header{"User-Agent": {"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"}})
+	visit("/resume", http.Header{})
+	visit("/resume.json", http.Header{"User-Agent": {browser}})
+	visit("/blog/missing", http.Header{"User-Agent": {browser}})
+	visit("/static/css/styles.css", http.Header{"User-Agent": {browser}})
+
+	rows, err := server.DB.Query(`SELECT visitor_id, path, referrer_host, utm_source, utm_medium, utm_campaign FROM page_views ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var views []string
+	var visitors []string
+	for rows.Next() {
+		var visitor, path, referrer, source, medium, campaign string
+		if err := rows.Scan(&visitor, &path, &referrer, &source, &medium, &campaign); err != nil {
+			t.Fatal(err)
+		}
+		visitors = append(visitors, visitor)
+		views = append(views, strings.Join([]string{path, referrer, source, medium, campaign}, " "))
+	}
+	want := []string{"/ news.example newsletter email launch", "/blog    ", "/ example.com   "}
+	if strings.Join(views, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("page views = %q, want %q", views, want)
+	}
+	if visitors[0] != visitors[1] || visitors[1] != visitors[2] || strings.Contains(visitors[0], "203.0.113.7") {
+		t.Fatalf("expected one hashed visitor, got %q", visitors)
+	}
+
+	var count int
+	if err := server.DB.QueryRow(`SELECT view_count FROM visitors WHERE id = ?`, visitors[0]).Scan(&count); err != nil || count != 3 {
+		t.Fatalf("visitor view_count = %d, %v; want 3", count, err)
+	}
+}
+
+func TestStatsDashboard(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	t.Setenv("DISABLE_ANALYTICS", "")
+	server := newTestServer(t)
+	server.adminPassword = "hunter2"
+	server.retentionDays = 2
+
+	now := time.Now().UTC()
+	q := dbgen.New(server.DB)
+	for i, v := range []dbgen.InsertPageViewParams{
+		{VisitorID: "a", Path: "/blog/hello", ReferrerHost: "news.example", ViewedAt: now.AddDate(0, 0, -3)},
+		{VisitorID: "a", Path: "/blog/hello", ViewedAt: now.AddDate(0, 0, -3)},
+		{VisitorID: "b", Path: "/blog/hello", UtmSource: "newsletter", UtmCampaign: "launch", ViewedAt: now.AddDate(0, 0, -1)},
+		{VisitorID: "c", Path: "/projects", ReferrerHost: "news.example", ViewedAt: now.Add(-time.Minute)},
+		{VisitorID: "d", Path: "/old", ViewedAt: now.AddDate(0, 0, -60)},
+	} {
+		if err := q.InsertPageView(context.Background(), v); err != nil {
+			t.Fatalf("insert view %d: %v", i, err)
+		}
+	}
+
+	get := func(target string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetBasicAuth("admin", "hunter2")
+		w := httptest.NewRecorder()
+		server.routes().ServeHTTP(w, req)
+		return w
+	}
+	w := httptest.NewRecorder()
+	server.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected the dashboard to need credentials, got %d", w.Code)
+	}
+
+	w = get("/admin/stats")
+	body := w.Body.String()
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /admin/stats = %d %s", w.Code, body)
+	}
+	for _, want := range []string{
+		`<svg class="linechart"`,
+		`>4</span> views · <span class="tabular-nums">3</span> visitors`,
+		`<td>/blog/hello</td><td>3</td><td>2</td>`,
+		`<td>news.example</td><td>2</td><td>2</td>`,
+		`<td>newsletter / – / launch</td><td>1</td><td>1</td>`,
+		`<span id="live-visitors" class="text-3xl font-medium tabular-nums">1</span>`,
+		`aria-current="true">30 days</a>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected the dashboard to contain %q, got %s", want, body)
+		}
+	}
+	if strings.Contains(body, "/old") {
+		t.Fatalf("expected views outside the range to be left out")
+	}
+
+	from := now.AddDate(0, 0, -89).Format(time.DateOnly)
+	if body := get("/admin/stats?from=" + from).Body.String(); !strings.Contains(body, "<td>/old</td>") || !strings.Contains(body, `aria-current="true">90 days</a>`) {
+		t.Fatalf("expected a wider range to include older views, got %s", body)
+	}
+	if w := get("/admin/stats?from=2026-05-02&to=2026-05-01"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected a reversed range to be rejected, got %d", w.Code)
+	}
+
+	// Rolling up the older views leaves the dashboard unchanged.
+	server.rollUpAnalytics(context.Background())
+	var raw int
+	if err := server.DB.QueryRow(`SELECT COUNT(*) FROM page_views`).Scan(&raw); err != nil || raw != 2 {
+		t.Fatalf("expected two raw views to be left, got %d, %v", raw, err)
+	}
+	if rolled := get("/admin/stats").Body.String(); rolled != body {
+		t.Fatalf("expected the same dashboard after rolling up, got %s", rolled)
+	}
+
+	w = get("/admin/stats/export?from=" + from)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/csv; charset=utf-8" ||
+		!strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment; filename=\"page-views-"+from) {
+		t.Fatalf("GET /admin/stats/export = %d %v", w.Code, w.Header())
+	}
+	if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 6 || lines[0] != "day,path,referrer_host,views,visitors" {
+		t.Fatalf("unexpected export %q", lines)
+	}
+	w = get("/admin/stats/export?format=json")
+	var rows []dbgen.DailyPageView
+	if err := json.Unmarshal(w.Body.Bytes(), &rows); err != nil || len(rows) != 4 {
+		t.Fatalf("json export = %d rows, %v: %s", len(rows), err, w.Body.String())
+	}
+	if w := get("/admin/stats/export?format=xml"); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unknown format to be rejected, got %d", w.Code)
+	}
+}
+
+func TestLiveStats(t *testing.T) {
+	t.Setenv("ENABLE_DEV_LOGS", "")
+	t.Setenv("DISABLE_ANALYTICS", "")
+	server := newTestServer(t)
+	server.adminPassword = "hunter2"
+	ts := httptest.NewServer(server.routes())
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/admin/stats/live", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("admin", "hunter2")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	events := bufio.NewScanner(resp.Body)
+	next := func() pagedata.LiveStats {
+		t.Helper()
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				var live pagedata.LiveStats
+				if err := json.Unmarshal([]byte(data), &live); err != nil {
+					t.Fatal(err)
+				}
+				return live
+			}
+		}
+		t.Fatalf("stream ended: %v", events.Err())
+		return pagedata.LiveStats{}
+	}
+	if live := next(); live.Visitors != 0 || len(live.Pages) != 0 {
+		t.Fatalf("expected no visitors yet, got %+v", live)
+	}
+
+	visit, err := http.NewRequest(http.MethodGet, ts.URL+"/blog", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	visit.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:140.0) Gecko/20100101 Firefox/140.0")
+	page, err := http.DefaultClient.Do(visit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page.Body.Close()
+
+	live := next()
+	if live.Visitors != 1 || live.Views != 1 || len(live.Pages) != 1 || live.Pages[0].Label != "/blog" {
+		t.Fatalf("expected the view to be pushed, got %+v", live)
+	}
+
+	// A burst of views waits for liveMinInterval and is recounted once.
+	last := time.Now()
+	for range 3 {
+		server.liveViews.publish("/blog")
+	}
+	next()
+	if gap := time.Since(last); gap < liveMinInterval*9/10 {
+		t.Fatalf("expected the burst to be recounted after %v, got %v", liveMinInterval, gap)
+	}
+}
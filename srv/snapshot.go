@@ -0,0 +1,79 @@
+package srv
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"srv.exe.dev/internal/githubapi"
+	"srv.exe.dev/internal/showcase"
+	"srv.exe.dev/internal/snapshot"
+)
+
+// useSnapshot serves project data from snap, written by
+// cmd/build -projects-snapshot, instead of calling GitHub and the other
+// sources. projects.yaml is still applied on top.
+func (s *Server) useSnapshot(snap *snapshot.Snapshot) {
+	s.snapshotted = true
+	matches := func(username string) error {
+		if !strings.EqualFold(username, snap.User) {
+			return fmt.Errorf("projects snapshot only holds data for %s", snap.User)
+		}
+		return nil
+	}
+	s.fetchProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		if err := matches(username); err != nil {
+			return nil, err
+		}
+		return slices.Clone(snap.Projects), nil
+	}
+	s.fetchUserProjects = func(ctx context.Context, username string) ([]githubapi.Project, error) {
+		if err := matches(username); err != nil {
+			return nil, err
+		}
+		// The snapshot holds the showcase; keep only the user's own GitHub
+		// repositories.
+		return slices.DeleteFunc(slices.Clone(snap.Projects), func(p githubapi.Project) bool {
+			return !p.OnGitHub() || !strings.EqualFold(cmp.Or(p.Owner, snap.User), snap.User)
+		}), nil
+	}
+	s.fetchProjectDetail = func(ctx context.Context, owner, name string) (*githubapi.ProjectDetail, error) {
+		for _, p := range snap.Projects {
+			if p.OnGitHub() && p.Name == name && cmp.Or(p.Owner, snap.User) == owner {
+				return snap.Detail(p), nil
+			}
+		}
+		return snap.Detail(githubapi.Project{Owner: owner, Name: name}), nil
+	}
+	s.fetchContributions = func(ctx context.Context, username string) (*githubapi.ContributionCalendar, error) {
+		if err := matches(username); err != nil {
+			return nil, err
+		}
+		return snap.Contributions, nil
+	}
+	s.fetchUpstream = func(ctx context.Context, username string) ([]githubapi.UpstreamRepo, error) {
+		if err := matches(username); err != nil {
+			return nil, err
+		}
+		return snap.Upstream, nil
+	}
+	s.fetchActivity = func(ctx context.Context, projects []githubapi.Project) ([]githubapi.ActivityItem, error) {
+		return snap.Activity, nil
+	}
+	s.fetchLanguageStats = func(ctx context.Context, username string) ([]githubapi.LanguageShare, error) {
+		cfg, err := showcase.Load(filepath.Join(s.DataDir, "showcase.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("load showcase config: %w", err)
+		}
+		if !cfg.ResumeLanguages {
+			return nil, nil
+		}
+		if err := matches(username); err != nil {
+			return nil, err
+		}
+		return snap.Languages, nil
+	}
+}
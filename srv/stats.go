@@ -0,0 +1,229 @@
+package srv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/analytics"
+	"srv.exe.dev/internal/pagedata"
+)
+
+// statsTopLimit is how many pages, referrers and campaigns the dashboard
+// lists.
+const statsTopLimit = 10
+
+// liveWindow is how far back the realtime panel counts visitors.
+const liveWindow = 5 * time.Minute
+
+// liveRefresh is how often the realtime panel is recounted without new
+// views, so visitors age out of it.
+const liveRefresh = 15 * time.Second
+
+// liveMinInterval is the shortest gap between two recounts of the realtime
+// panel, however fast views arrive.
+const liveMinInterval = time.Second
+
+// statsPresets are the dashboard's quick ranges, in days.
+var statsPresets = []int{7, 30, 90, 365}
+
+// HandleStats serves GET /admin/stats, page views over ?from= to ?to=
+// (YYYY-MM-DD, the last 30 days by default).
+func (s *Server) HandleStats(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC()
+	rng, err := analytics.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := s.loadStats(r.Context(), rng, now)
+	if err != nil {
+		slog.Warn("load stats", "error", err)
+		http.Error(w, "Failed to load stats", http.StatusInternalServerError)
+		return
+	}
+	s.renderTemplate(w, r, "admin_stats.html", data)
+}
+
+func (s *Server) loadStats(ctx context.Context, rng analytics.Range, now time.Time) (pagedata.StatsPageData, error) {
+	data := pagedata.StatsPageData{PageData: s.newPage("admin"), Range: rng, RetentionDays: s.retentionDays}
+	data.NoIndex = true
+	for _, days := range statsPresets {
+		preset := analytics.LastDays(days, now)
+		data.Presets = append(data.Presets, pagedata.StatsPreset{
+			Label:  fmt.Sprintf("%d days", days),
+			URL:    statsURL(preset),
+			Active: preset == rng,
+		})
+	}
+
+	q := dbgen.New(s.DB)
+	daily, err := analytics.Daily(ctx, q, rng)
+	if err != nil {
+		return data, fmt.Errorf("daily page views: %w", err)
+	}
+	days := make([]pagedata.DailyStats, len(daily))
+	for i, d := range daily {
+		days[i] = pagedata.DailyStats{Date: d.Day, Views: int(d.Views), Visitors: int(d.Visitors)}
+	}
+	data.Traffic = pagedata.NewTraffic(days, rng)
+
+	views, err := analytics.PageViews(ctx, q, rng)
+	if err != nil {
+		return data, fmt.Errorf("page views: %w", err)
+	}
+	data.Pages = statsRows(analytics.Top(views, func(v dbgen.DailyPageView) string { return v.Path }, statsTopLimit))
+	data.Referrers = statsRows(analytics.Top(views, func(v dbgen.DailyPageView) string { return v.ReferrerHost }, statsTopLimit))
+	campaigns, err := q.TopCampaigns(ctx, dbgen.TopCampaignsParams{Since: rng.Since(), Until: rng.Until(), Limit: statsTopLimit})
+	if err != nil {
+		return data, fmt.Errorf("top campaigns: %w", err)
+	}
+	for _, c := range campaigns {
+		data.Campaigns = append(data.Campaigns, pagedata.CampaignRow{
+			Source:   c.UtmSource,
+			Medium:   c.UtmMedium,
+			Campaign: c.UtmCampaign,
+			Views:    int(c.Views),
+			Visitors: int(c.Visitors),
+		})
+	}
+
+	data.Live, err = s.loadLiveStats(ctx, now)
+	return data, err
+}
+
+// HandleStatsExport serves GET /admin/stats/export, the page views over
+// ?from= to ?to= per day, path and referrer as ?format=csv (the default) or
+// json.
+func (s *Server) HandleStatsExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if !analytics.ValidExportFormat(format) {
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+	rng, err := analytics.ParseRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), time.Now().UTC())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	views, err := analytics.PageViews(r.Context(), dbgen.New(s.DB), rng)
+	if err != nil {
+		slog.Warn("export page views", "error", err)
+		http.Error(w, "Failed to load stats", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", analytics.ExportContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="page-views-%s-%s.%s"`,
+		rng.From.Format(time.DateOnly), rng.To.Format(time.DateOnly), format))
+	if err := analytics.Export(w, format, views); err != nil {
+		slog.Warn("write page view export", "error", err)
+	}
+}
+
+func statsRows(totals []analytics.Total) []pagedata.StatsRow {
+	rows := make([]pagedata.StatsRow, len(totals))
+	for i, t := range totals {
+		rows[i] = pagedata.StatsRow{Label: t.Key, Views: t.Views, Visitors: t.Visitors}
+	}
+	return rows
+}
+
+// statsURL links to the dashboard for rng.
+func statsURL(rng analytics.Range) string {
+	return "/admin/stats?" + url.Values{
+		"from": {rng.From.Format(time.DateOnly)},
+		"to":   {rng.To.Format(time.DateOnly)},
+	}.Encode()
+}
+
+// loadLiveStats counts the visitors and page views of the last liveWindow.
+func (s *Server) loadLiveStats(ctx context.Context, now time.Time) (pagedata.LiveStats, error) {
+	since := now.Add(-liveWindow)
+	q := dbgen.New(s.DB)
+	active, err := q.ActiveVisitors(ctx, since)
+	if err != nil {
+		return pagedata.LiveStats{}, fmt.Errorf("active visitors: %w", err)
+	}
+	pages, err := q.RecentPages(ctx, dbgen.RecentPagesParams{ViewedAt: since, Limit: statsTopLimit})
+	if err != nil {
+		return pagedata.LiveStats{}, fmt.Errorf("active pages: %w", err)
+	}
+	live := pagedata.LiveStats{Visitors: int(active.Visitors), Views: int(active.Views), Pages: []pagedata.StatsRow{}}
+	for _, p := range pages {
+		live.Pages = append(live.Pages, pagedata.StatsRow{Label: p.Path, Views: int(p.Views), Visitors: int(p.Visitors)})
+	}
+	return live, nil
+}
+
+// HandleLiveStats serves GET /admin/stats/live, a stream of server-sent
+// events carrying the realtime panel as JSON. It is sent on connecting,
+// after recorded page views and every liveRefresh. Views arriving within
+// liveMinInterval of the last send are counted together once it has passed.
+func (s *Server) HandleLiveStats(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// The stream outlives the server's write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := s.liveViews.subscribe()
+	defer s.liveViews.unsubscribe(ch)
+	ticker := time.NewTicker(liveRefresh)
+	defer ticker.Stop()
+
+	send := func() error {
+		live, err := s.loadLiveStats(r.Context(), time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(live)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	for {
+		if err := send(); err != nil {
+			if r.Context().Err() == nil {
+				slog.Warn("send live stats", "error", err)
+			}
+			return
+		}
+		sentAt := time.Now()
+		// pending fires when a view has arrived too soon after sentAt.
+		var pending <-chan time.Time
+	wait:
+		for {
+			select {
+			case <-ch:
+				if pending != nil {
+					continue
+				}
+				if d := liveMinInterval - time.Since(sentAt); d > 0 {
+					pending = time.After(d)
+					continue
+				}
+				break wait
+			case <-pending:
+				break wait
+			case <-ticker.C:
+				break wait
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
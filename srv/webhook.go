@@ -0,0 +1,241 @@
+package srv
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/githubapi"
+)
+
+// maxWebhookBody bounds webhook payloads; GitHub caps them at 25 MB, but
+// the fields read here are near the top of much smaller documents.
+const maxWebhookBody = 10 << 20
+
+// webhookRefreshTimeout bounds the background refresh after a delivery.
+const webhookRefreshTimeout = time.Minute
+
+// webhookBuildTimeout bounds WEBHOOK_BUILD_COMMAND.
+const webhookBuildTimeout = 10 * time.Minute
+
+// webhookPayload holds the fields read from push, release, star and
+// repository events.
+type webhookPayload struct {
+	Action     string `json:"action"`
+	Ref        string `json:"ref"`
+	Repository *struct {
+		// FullName is "owner/name".
+		FullName      string `json:"full_name"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+}
+
+// coalescedRunner runs a function in the background, one run at a time.
+// Triggers that arrive during a run schedule a single extra run after it,
+// so a burst of deliveries costs at most two refreshes.
+type coalescedRunner struct {
+	mu      sync.Mutex
+	running bool
+	pending bool
+	wg      sync.WaitGroup
+}
+
+func (r *coalescedRunner) trigger(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		r.pending = true
+		return
+	}
+	r.running = true
+	r.wg.Go(func() {
+		for {
+			fn()
+			r.mu.Lock()
+			if !r.pending {
+				r.running = false
+				r.mu.Unlock()
+				return
+			}
+			r.pending = false
+			r.mu.Unlock()
+		}
+	})
+}
+
+// wait blocks until no run is in progress.
+func (r *coalescedRunner) wait() {
+	r.wg.Wait()
+}
+
+// HandleGitHubWebhook serves POST /hooks/github. Deliveries signed with
+// webhookSecret are recorded and, for events that change what the
+// projects pages show, trigger a refresh of the cached GitHub data and
+// the optional rebuild command.
+func (s *Server) HandleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhookSecret == "" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "Failed to read payload", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !validWebhookSignature(s.webhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	if event == "" || delivery == "" {
+		http.Error(w, "Missing X-GitHub-Event or X-GitHub-Delivery", http.StatusBadRequest)
+		return
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	refresh := webhookRefreshes(event, payload)
+	var repo string
+	if payload.Repository != nil {
+		repo = payload.Repository.FullName
+	}
+	inserted, err := dbgen.New(s.DB).InsertWebhookDelivery(r.Context(), dbgen.InsertWebhookDeliveryParams{
+		ID:         delivery,
+		Event:      event,
+		Action:     payload.Action,
+		Repository: repo,
+		Handled:    refresh,
+		ReceivedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		slog.Warn("record webhook delivery", "delivery", delivery, "error", err)
+		http.Error(w, "Failed to record delivery", http.StatusInternalServerError)
+		return
+	}
+	if inserted == 0 {
+		_, _ = io.WriteString(w, "duplicate delivery\n")
+		return
+	}
+
+	slog.Info("github webhook", "event", event, "action", payload.Action, "repo", repo, "delivery", delivery, "refresh", refresh)
+	switch {
+	case event == "ping":
+		_, _ = io.WriteString(w, "pong\n")
+	case refresh:
+		if payload.Repository != nil {
+			if owner, name, ok := strings.Cut(payload.Repository.FullName, "/"); ok {
+				s.projectDetails.delete(githubapi.Project{Forge: githubapi.ForgeGitHub, Owner: owner, Name: name}.Key())
+			}
+		}
+		s.projectsCache.invalidate()
+		s.webhookRefresh.trigger(s.refreshProjectData)
+		if s.webhookBuildCommand != "" {
+			s.webhookBuild.trigger(s.runWebhookBuild)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, "refreshing\n")
+	default:
+		_, _ = io.WriteString(w, "ignored\n")
+	}
+}
+
+// validWebhookSignature checks an X-Hub-Signature-256 header, an HMAC-SHA256
+// of the body keyed with the webhook secret.
+func validWebhookSignature(secret string, body []byte, header string) bool {
+	got, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	sig, err := hex.DecodeString(got)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// webhookRefreshes reports whether an event changes what the projects
+// pages show. Pushes only count on the default branch, where READMEs and
+// the commit list come from.
+func webhookRefreshes(event string, p webhookPayload) bool {
+	switch event {
+	case "push":
+		return p.Repository != nil && p.Ref == "refs/heads/"+p.Repository.DefaultBranch
+	case "release":
+		return p.Action == "published" || p.Action == "edited" || p.Action == "deleted"
+	case "star", "repository":
+		return true
+	default:
+		return false
+	}
+}
+
+// refreshProjectData re-fetches the projects, activity, contribution
+// calendar and upstream pull requests into projectCache.
+func (s *Server) refreshProjectData() {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookRefreshTimeout)
+	defer cancel()
+	result, err := s.loadShowcaseProjects(ctx)
+	if err != nil {
+		slog.Warn("refresh github repos after webhook", "user", s.githubUser, "error", err)
+		return
+	}
+	if activity, err := s.fetchActivity(ctx, result.projects); err != nil {
+		slog.Warn("refresh github activity after webhook", "user", s.githubUser, "error", err)
+	} else {
+		s.projectsCache.setActivity(activity)
+	}
+	if cal, err := s.fetchContributions(ctx, s.githubUser); err != nil {
+		slog.Warn("refresh github contributions after webhook", "user", s.githubUser, "error", err)
+	} else {
+		s.projectsCache.setContributions(cal)
+	}
+	if repos, err := s.fetchUpstream(ctx, s.githubUser); err != nil {
+		slog.Warn("refresh github upstream contributions after webhook", "user", s.githubUser, "error", err)
+	} else {
+		s.projectsCache.setUpstream(repos)
+	}
+}
+
+// runWebhookBuild runs WEBHOOK_BUILD_COMMAND, e.g. a cmd/build invocation
+// that regenerates the static site. The command is split on spaces and run
+// without a shell.
+func (s *Server) runWebhookBuild() {
+	args := strings.Fields(s.webhookBuildCommand)
+	if len(args) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookBuildTimeout)
+	defer cancel()
+	start := time.Now()
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput() // #nosec G204 -- the command comes from server configuration.
+	if err != nil {
+		slog.Warn("webhook build failed", "error", err, "output", tail(string(out), 2000))
+		return
+	}
+	slog.Info("webhook build finished", "duration", time.Since(start).Round(time.Millisecond))
+}
+
+// tail returns at most the last n bytes of s.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
@@ -0,0 +1,183 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"srv.exe.dev/db/dbgen"
+	"srv.exe.dev/internal/blog"
+	"srv.exe.dev/internal/webmention"
+)
+
+// maxWebmentionForm bounds a Webmention request, two URLs in a form.
+const maxWebmentionForm = 16 << 10
+
+// webmentionVerifyTimeout bounds fetching and checking one source.
+const webmentionVerifyTimeout = 30 * time.Second
+
+// maxPendingWebmentions bounds the verification queue; further requests
+// are turned away until it drains.
+const maxPendingWebmentions = 100
+
+// webmentionJob is a received Webmention awaiting verification.
+type webmentionJob struct {
+	source, target, slug string
+}
+
+// webmentionQueue verifies received Webmentions in the background, one at
+// a time. A mention already waiting is not queued twice.
+type webmentionQueue struct {
+	mu      sync.Mutex
+	pending []webmentionJob
+	running bool
+	wg      sync.WaitGroup
+}
+
+// add queues job for verify, reporting false when the queue is full.
+func (q *webmentionQueue) add(job webmentionJob, verify func(webmentionJob)) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if slices.Contains(q.pending, job) {
+		return true
+	}
+	if len(q.pending) >= maxPendingWebmentions {
+		return false
+	}
+	q.pending = append(q.pending, job)
+	if q.running {
+		return true
+	}
+	q.running = true
+	q.wg.Go(func() {
+		for {
+			q.mu.Lock()
+			if len(q.pending) == 0 {
+				q.running = false
+				q.mu.Unlock()
+				return
+			}
+			next := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+			verify(next)
+		}
+	})
+	return true
+}
+
+// wait blocks until the queue is empty.
+func (q *webmentionQueue) wait() {
+	q.wg.Wait()
+}
+
+// HandleWebmention serves POST /webmention, the endpoint blog posts
+// advertise. It checks that target is one of the posts and answers 202
+// Accepted; the source is fetched and checked in the background.
+func (s *Server) HandleWebmention(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebmentionForm)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		return
+	}
+	source, target := r.PostForm.Get("source"), r.PostForm.Get("target")
+	if !httpURL(source) || !httpURL(target) {
+		http.Error(w, "source and target must be http or https URLs", http.StatusBadRequest)
+		return
+	}
+	if source == target {
+		http.Error(w, "source and target must differ", http.StatusBadRequest)
+		return
+	}
+	post := s.webmentionPost(target)
+	if post == nil {
+		http.Error(w, "target is not a post on this site", http.StatusBadRequest)
+		return
+	}
+
+	if !s.webmentionQueue.add(webmentionJob{source: source, target: target, slug: post.Slug}, s.verifyWebmention) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "Too many webmentions waiting; try again later", http.StatusServiceUnavailable)
+		return
+	}
+	slog.Info("webmention received", "source", source, "target", target)
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = io.WriteString(w, "verifying\n")
+}
+
+// verifyWebmention fetches a mention's source and stores the mention, or
+// deletes a stored one whose source no longer links to the post.
+func (s *Server) verifyWebmention(job webmentionJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), webmentionVerifyTimeout)
+	defer cancel()
+	q := dbgen.New(s.DB)
+	m, err := webmention.Verify(ctx, s.webmentionClient, job.source, job.target)
+	if errors.Is(err, webmention.ErrNoLink) || errors.Is(err, webmention.ErrGone) {
+		if _, err := q.DeleteWebmention(ctx, dbgen.DeleteWebmentionParams{Source: job.source, Target: job.target}); err != nil {
+			slog.Warn("delete webmention", "source", job.source, "error", err)
+		}
+		slog.Info("webmention rejected", "source", job.source, "target", job.target, "reason", err)
+		return
+	}
+	if err != nil {
+		slog.Warn("verify webmention", "source", job.source, "target", job.target, "error", err)
+		return
+	}
+	if err := q.UpsertWebmention(ctx, dbgen.UpsertWebmentionParams{
+		Source:     m.Source,
+		Target:     m.Target,
+		PostSlug:   job.slug,
+		Type:       string(m.Type),
+		AuthorName: m.AuthorName,
+		AuthorUrl:  m.AuthorURL,
+		Content:    m.Content,
+		VerifiedAt: time.Now().UTC(),
+	}); err != nil {
+		slog.Warn("store webmention", "source", job.source, "error", err)
+		return
+	}
+	slog.Info("webmention verified", "source", job.source, "target", job.target, "type", m.Type)
+}
+
+// webmentionPost returns the published post target points at, or nil.
+// Targets may be on Hostname or on webmentionHosts, such as the static
+// site, whose pages may live under a base path like /portfolio. The
+// request's Host header is client-supplied and never consulted.
+func (s *Server) webmentionPost(target string) *blog.Post {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Host)
+	if host != strings.ToLower(s.Hostname) && !slices.Contains(s.webmentionHosts, host) {
+		return nil
+	}
+	prefix, slug, ok := strings.Cut(u.Path, "/blog/")
+	if !ok || strings.Count(prefix, "/") > 1 || (prefix != "" && !strings.HasPrefix(prefix, "/")) {
+		return nil
+	}
+	return s.findPublishedPost(strings.TrimSuffix(slug, "/"))
+}
+
+// loadWebmentions returns the verified Webmentions of a post. A failed
+// query is logged and shows the post without them.
+func (s *Server) loadWebmentions(ctx context.Context, slug string) []webmention.Mention {
+	rows, err := dbgen.New(s.DB).WebmentionsForPost(ctx, slug)
+	if err != nil {
+		slog.Warn("load webmentions", "post", slug, "error", err)
+		return nil
+	}
+	return webmention.FromRows(rows)
+}
+
+func httpURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}